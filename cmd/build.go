@@ -30,7 +30,7 @@ SYNOPSIS
 	{{rootCmdUse}} build [-r|--registry] [--builder] [--builder-image]
 		         [--push] [--username] [--password] [--token]
 	             [--platform] [-p|--path] [-c|--confirm] [-v|--verbose]
-		         [--build-timestamp] [--registry-insecure]
+		         [--build-timestamp] [--registry-insecure] [--all]
 
 DESCRIPTION
 
@@ -46,6 +46,13 @@ DESCRIPTION
 	When building a function for the first time, either a registry or explicit
 	image name is required.  Subsequent builds will reuse these option values.
 
+	Workspaces
+	  The --all flag builds every function of the workspace rooted at --path.
+	  Functions of a workspace are those listed in its func-workspace.yaml, or
+	  all functions found beneath the workspace root if none are listed.
+	  A registry defined by the workspace is used for those functions which do
+	  not define their own.
+
 EXAMPLES
 
 	o Build a function container using the given registry.
@@ -59,6 +66,9 @@ EXAMPLES
 	o Rebuild a function using prior values to determine container name.
 	  $ {{rootCmdUse}} build
 
+	o Build all functions of the workspace in the current directory.
+	  $ {{rootCmdUse}} build --all
+
 	o Build a function specifying the Source-to-Image (S2I) builder
 	  $ {{rootCmdUse}} build --builder=s2i
 
@@ -70,7 +80,7 @@ EXAMPLES
 		SuggestFor: []string{"biuld", "buidl", "built"},
 		PreRunE: bindEnv("image", "path", "builder", "registry", "confirm",
 			"push", "builder-image", "platform", "verbose", "build-timestamp",
-			"registry-insecure", "username", "password", "token"),
+			"registry-insecure", "username", "password", "token", "all"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runBuild(cmd, args, newClient)
 		},
@@ -136,6 +146,7 @@ EXAMPLES
 	_ = cmd.Flags().MarkHidden("token")
 
 	// Oft-shared flags:
+	addAllFlag(cmd)
 	addConfirmFlag(cmd, cfg.Confirm)
 	addPathFlag(cmd)
 	addVerboseFlag(cmd, cfg.Verbose)
@@ -156,6 +167,9 @@ func runBuild(cmd *cobra.Command, _ []string, newClient ClientFactory) (err erro
 		cfg buildConfig
 		f   fn.Function
 	)
+	if viper.GetBool("all") { // build each function of the workspace
+		return runBuildAll(cmd, newClient)
+	}
 	if cfg, err = newBuildConfig().Prompt(); err != nil { // gather values into a single instruction set
 		return
 	}
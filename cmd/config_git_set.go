@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -25,7 +26,7 @@ func NewConfigGitSetCmd(newClient ClientFactory) *cobra.Command {
 	directory or from the directory specified with --path.
 	`,
 		SuggestFor: []string{"add", "ad", "update", "create", "insert", "append"},
-		PreRunE:    bindEnv("path", "builder", "builder-image", "image", "registry", "git-provider", "git-url", "git-branch", "git-dir", "gh-access-token", "config-local", "config-cluster", "config-remote"),
+		PreRunE:    bindEnv("path", "builder", "builder-image", "image", "registry", "git-provider", "git-url", "git-branch", "git-dir", "git-user", "gh-access-token", "config-local", "config-cluster", "config-remote"),
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			return runConfigGitSetCmd(cmd, newClient)
 		},
@@ -72,10 +73,12 @@ func NewConfigGitSetCmd(newClient ClientFactory) *cobra.Command {
 		"Git revision (branch) to be used when deploying via the Git repository ($FUNC_GIT_BRANCH)")
 	cmd.Flags().StringP("git-dir", "d", "",
 		"Directory in the Git repository containing the function (default is the root) ($FUNC_GIT_DIR)")
+	cmd.Flags().String("git-user", "",
+		"User owning the access token, required by Bitbucket Server ($FUNC_GIT_USER)")
 
 	// GitHub related Flags:
 	cmd.Flags().String("gh-access-token", "",
-		"GitHub Personal Access Token. For public repositories the scope is 'public_repo', for private is 'repo'. If you want to configure the webhook automatically, 'admin:repo_hook' is needed as well. Get more details: https://pipelines-as-code.pages.dev/docs/install/github_webhook/.")
+		"Personal Access Token of the Git provider. For GitHub public repositories the scope is 'public_repo', for private is 'repo'. If you want to configure the webhook automatically, 'admin:repo_hook' is needed as well. Get more details: https://pipelines-as-code.pages.dev/docs/install/github_webhook/.")
 	cmd.Flags().String("gh-webhook-secret", "",
 		"GitHub Webhook Secret used for payload validation. If not specified, it will be generated automatically.")
 
@@ -134,6 +137,7 @@ func newConfigGitSetConfig(_ *cobra.Command) (c configGitSetConfig) {
 
 		metadata: pipelines.PacMetadata{
 			GitProvider:         viper.GetString("git-provider"),
+			GitUser:             viper.GetString("git-user"),
 			PersonalAccessToken: viper.GetString("gh-access-token"),
 			WebhookSecret:       viper.GetString("gh-webhook-secret"),
 
@@ -146,7 +150,7 @@ func newConfigGitSetConfig(_ *cobra.Command) (c configGitSetConfig) {
 	return c
 }
 
-func (c configGitSetConfig) Prompt(f fn.Function) (configGitSetConfig, error) {
+func (c configGitSetConfig) Prompt(ctx context.Context, f fn.Function) (configGitSetConfig, error) {
 	var err error
 	if c.buildConfig, err = c.buildConfig.Prompt(); err != nil {
 		return c, err
@@ -216,7 +220,7 @@ func (c configGitSetConfig) Prompt(f fn.Function) (configGitSetConfig, error) {
 	if c.metadata.ConfigureRemoteResources {
 		// Configure Git provider
 		if c.metadata.GitProvider == "" {
-			provider, err := git.GitProviderName(c.GitURL)
+			provider, err := git.DetectGitProvider(ctx, c.GitURL)
 			if err != nil {
 				msg := "Please select the type of the Git platform provider to setup webhook:"
 				if err = survey.AskOne(&survey.Select{
@@ -232,15 +236,33 @@ func (c configGitSetConfig) Prompt(f fn.Function) (configGitSetConfig, error) {
 
 		// prompt if PersonalAccessToken hasn't been set previously
 		if c.metadata.PersonalAccessToken == "" {
-			var personalAccessToken string
-			if err := survey.AskOne(&survey.Password{
+			prompt := &survey.Password{
 				Message: "Please enter the GitHub Personal Access Token:",
 				Help:    "For public repositories the scope is 'public_repo', for private is 'repo'. If you want to configure the webhook automatically 'admin:repo_hook' is needed as well. Get more details: https://pipelines-as-code.pages.dev/docs/install/github_webhook/.",
-			}, &personalAccessToken, survey.WithValidator(survey.Required)); err != nil {
+			}
+			if c.metadata.GitProvider != git.GitHubProvider {
+				prompt = &survey.Password{
+					Message: fmt.Sprintf("Please enter the Personal Access Token for %q:", c.metadata.GitProvider),
+					Help:    "The token needs read access to the repository, and admin access to configure the webhook automatically. Get more details: https://pipelines-as-code.pages.dev/docs/install/.",
+				}
+			}
+			var personalAccessToken string
+			if err := survey.AskOne(prompt, &personalAccessToken, survey.WithValidator(survey.Required)); err != nil {
 				return c, err
 			}
 			c.metadata.PersonalAccessToken = personalAccessToken
 		}
+
+		// prompt for the owner of the token if required by the provider
+		if c.metadata.GitProvider == git.BitbucketServerProvider && c.metadata.GitUser == "" {
+			var user string
+			if err := survey.AskOne(&survey.Input{
+				Message: "Please enter the Bitbucket Server user owning the token:",
+			}, &user, survey.WithValidator(survey.Required)); err != nil {
+				return c, err
+			}
+			c.metadata.GitUser = user
+		}
 	}
 
 	return c, nil
@@ -294,7 +316,7 @@ func runConfigGitSetCmd(cmd *cobra.Command, newClient ClientFactory) (err error)
 	if f, err = fn.NewFunction(cfg.Path); err != nil {
 		return
 	}
-	if cfg, err = cfg.Prompt(f); err != nil {
+	if cfg, err = cfg.Prompt(cmd.Context(), f); err != nil {
 		return
 	}
 	if err = cfg.Validate(cmd); err != nil {
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"text/tabwriter"
 	"text/template"
@@ -37,7 +41,7 @@ NAME
 
 SYNOPSIS
 	{{.Name}} create [-l|--language] [-t|--template] [-r|--repository]
-	            [-c|--confirm]  [-v|--verbose]  [path]
+	            [--param] [--no-hooks] [--trust-hooks] [--adopt] [-c|--confirm]  [-v|--verbose]  [path]
 
 DESCRIPTION
 	Creates a new function project.
@@ -53,11 +57,38 @@ DESCRIPTION
 	To complete this command interactively, use --confirm (-c):
 	  $ {{.Name}} create -c
 
+	The language and a template of any installed repository are chosen from
+	lists, the template's files are previewed before creating the function,
+	and the default registry and namespace for deploying functions may be set
+	(saved to the global config).
+
 	Available Language Runtimes and Templates:
 {{ .Options | indent 2 " " | indent 1 "\t" }}
 
 	To install more language runtimes and their templates see '{{.Name}} repository'.
 
+	Templates may declare parameters (in their manifest.yaml) with which the
+	template is rendered.  Provide values using --param NAME=VALUE, which may
+	be repeated.  Parameters not provided take their default value, or are
+	prompted for when using --confirm.
+
+	Templates may also declare post-create hooks, commands such as
+	'go mod tidy' or 'npm install' which are run in the new function's
+	directory once it has been created.  Hooks of templates from repositories
+	other than the default are confirmed before being run when in an
+	interactive terminal, and are otherwise refused unless trusted with
+	--trust-hooks.  To skip running hooks, use --no-hooks.  Should a hook
+	fail, the function is not created.
+
+	Adopting Existing Projects:
+	An existing Go, Node.js, TypeScript or Python project which implements a
+	function handler can be turned into a function in place using --adopt.
+	A func.yaml is written, as is the function's run data directory (.func),
+	which is added to .gitignore; the project's files are otherwise left
+	untouched.  The language is inferred from the project's files (go.mod,
+	package.json, tsconfig.json, pyproject.toml etc.) unless provided with
+	--language, as is whether it handles CloudEvents or plain HTTP requests.
+
 
 EXAMPLES
 	o Create a Node.js function in the current directory (the default path) which
@@ -69,9 +100,15 @@ EXAMPLES
 
 	o Create a Go function which handles CloudEvents in ./myfunc.
 	  $ {{.Name}} create -l go -t cloudevents myfunc
+
+	o Create a function from a parameterized template of a custom repository.
+	  $ {{.Name}} create -l go -t mytemplates/api --param database=postgres myfunc
+
+	o Adopt the existing project in the current directory as a function.
+	  $ {{.Name}} init --adopt
 		`,
 		SuggestFor: []string{"vreate", "creaet", "craete", "new"},
-		PreRunE:    bindEnv("language", "template", "repository", "confirm", "verbose"),
+		PreRunE:    bindEnv("language", "template", "repository", "param", "no-hooks", "trust-hooks", "adopt", "confirm", "verbose"),
 		Aliases:    []string{"init"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runCreate(cmd, args, newClient)
@@ -88,6 +125,10 @@ EXAMPLES
 	cmd.Flags().StringP("language", "l", cfg.Language, "Language Runtime (see help text for list) ($FUNC_LANGUAGE)")
 	cmd.Flags().StringP("template", "t", fn.DefaultTemplate, "Function template. (see help text for list) ($FUNC_TEMPLATE)")
 	cmd.Flags().StringP("repository", "r", "", "URI to a Git repository containing the specified template ($FUNC_REPOSITORY)")
+	cmd.Flags().StringArray("param", []string{}, "Template parameter in the form NAME=VALUE. May be provided multiple times. ($FUNC_PARAM)")
+	cmd.Flags().Bool("no-hooks", false, "Do not run the template's post-create hooks ($FUNC_NO_HOOKS)")
+	cmd.Flags().Bool("trust-hooks", false, "Run the post-create hooks of templates from any repository without confirmation ($FUNC_TRUST_HOOKS)")
+	cmd.Flags().Bool("adopt", false, "Adopt the existing project at path as a function, writing func.yaml and the ignored .func directory ($FUNC_ADOPT)")
 
 	addConfirmFlag(cmd, cfg.Confirm)
 	// TODO: refactor to use --path like all the other commands
@@ -109,6 +150,10 @@ EXAMPLES
 
 // Run Create
 func runCreate(cmd *cobra.Command, args []string, newClient ClientFactory) (err error) {
+	if viper.GetBool("adopt") {
+		return runAdopt(cmd, args, newClient)
+	}
+
 	// Config
 	// Create a config based on args.  Also uses the newClient to create a
 	// temporary client for completing options such as available runtimes.
@@ -132,12 +177,19 @@ func runCreate(cmd *cobra.Command, args []string, newClient ClientFactory) (err
 	}
 
 	// Create
+	hooks := fn.InitWithHookConfirmation(confirmTemplateHooks)
+	if cfg.NoHooks {
+		hooks = fn.InitWithoutHooks()
+	} else if cfg.TrustHooks {
+		hooks = fn.InitWithTrustedHooks()
+	}
 	_, err = client.Init(fn.Function{
-		Name:     cfg.Name,
-		Root:     cfg.Path,
-		Runtime:  cfg.Runtime,
-		Template: cfg.Template,
-	})
+		Name:           cfg.Name,
+		Root:           cfg.Path,
+		Runtime:        cfg.Runtime,
+		Template:       cfg.Template,
+		TemplateParams: cfg.Params,
+	}, hooks)
 	if err != nil {
 		return err
 	}
@@ -146,6 +198,51 @@ func runCreate(cmd *cobra.Command, args []string, newClient ClientFactory) (err
 	return nil
 }
 
+// runAdopt adopts the existing project at path as a function.  The language
+// and template are inferred unless explicitly provided.
+func runAdopt(cmd *cobra.Command, args []string, newClient ClientFactory) error {
+	var path string
+	if len(args) >= 1 {
+		path = args[0]
+	}
+	name, root := deriveNameAndAbsolutePathFromPath(path)
+	f := fn.Function{Name: name, Root: root}
+	if cmd.Flags().Changed("language") {
+		f.Runtime = viper.GetString("language")
+	}
+	if cmd.Flags().Changed("template") {
+		f.Template = viper.GetString("template")
+	}
+
+	client, done := newClient(ClientConfig{Verbose: viper.GetBool("verbose")})
+	defer done()
+
+	f, err := client.Adopt(f)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStderr(), "Adopted %v function in %v\n", f.Runtime, root)
+	return nil
+}
+
+// confirmTemplateHooks prompts to confirm running the post-create hooks of
+// a template from a repository other than the default.  Noninteractive
+// terminals refuse them; use --trust-hooks to run or --no-hooks to skip them.
+func confirmTemplateHooks(t fn.Template, hooks []fn.TemplateHook) (confirmed bool, err error) {
+	fmt.Fprintf(os.Stderr, "Template %v declares post-create hooks:\n", t.Fullname())
+	for _, h := range hooks {
+		fmt.Fprintf(os.Stderr, "  %v\n", h.Command)
+	}
+	if !interactiveTerminal() {
+		return false, fmt.Errorf("%w: %v. Pass --trust-hooks to run them or --no-hooks to skip them", fn.ErrHooksNotTrusted, t.Fullname())
+	}
+	err = survey.AskOne(&survey.Confirm{
+		Message: "Run these commands?",
+		Default: false,
+	}, &confirmed)
+	return
+}
+
 type createConfig struct {
 	Path       string // Absolute path to function source
 	Runtime    string // Language Runtime
@@ -162,6 +259,15 @@ type createConfig struct {
 	// minimum implementation of the signature itself and example tests.
 	Template string
 
+	// Params are values for the parameters declared by the template.
+	Params map[string]string
+
+	// NoHooks disables running the template's post-create hooks.
+	NoHooks bool
+
+	// TrustHooks runs the template's post-create hooks without confirmation.
+	TrustHooks bool
+
 	// Name of the function
 	Name string
 }
@@ -195,9 +301,14 @@ func newCreateConfig(cmd *cobra.Command, args []string, newClient ClientFactory)
 		Repository: viper.GetString("repository"),
 		Runtime:    viper.GetString("language"), // users refer to it is language
 		Template:   viper.GetString("template"),
+		NoHooks:    viper.GetBool("no-hooks"),
+		TrustHooks: viper.GetBool("trust-hooks"),
 		Confirm:    viper.GetBool("confirm"),
 		Verbose:    viper.GetBool("verbose"),
 	}
+	if cfg.Params, err = parseTemplateParams(cmd); err != nil {
+		return
+	}
 	// If not in confirm/prompting mode, this cfg structure is complete.
 	if !cfg.Confirm {
 		return
@@ -230,20 +341,63 @@ func newCreateConfig(cmd *cobra.Command, args []string, newClient ClientFactory)
 		fmt.Printf("Repository:   %v\n", cfg.Repository) // show only the override
 	}
 	fmt.Printf("Template:     %v\n", cfg.Template)
+	for _, k := range sortedParamNames(cfg.Params) {
+		fmt.Printf("Param:        %v=%v\n", k, cfg.Params[k])
+	}
 	return
 }
 
+// parseTemplateParams from the --param flag values (NAME=VALUE).
+func parseTemplateParams(cmd *cobra.Command) (map[string]string, error) {
+	pp, err := cmd.Flags().GetStringArray("param")
+	if err != nil {
+		return nil, err
+	}
+	// Values from the environment variable are space-delimited.
+	if len(pp) == 0 && viper.IsSet("param") {
+		pp = viper.GetStringSlice("param")
+	}
+	params := make(map[string]string, len(pp))
+	for _, p := range pp {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid template parameter %q, expected NAME=VALUE", p)
+		}
+		params[k] = v
+	}
+	return params, nil
+}
+
+// sortedParamNames returns the names of the given template parameters in
+// a stable order.
+func sortedParamNames(params map[string]string) []string {
+	names := make([]string, 0, len(params))
+	for k := range params {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // singleCommand that could be used by the current user to minimally recreate the current state.
 func singleCommand(cmd *cobra.Command, args []string, cfg createConfig) string {
 	var b strings.Builder
 	b.WriteString(cmd.Root().Name())    // process executable
 	b.WriteString(" -l " + cfg.Runtime) // language runtime is required
-	if cmd.Flags().Lookup("template").Changed {
+	if cmd.Flags().Lookup("template").Changed || cfg.Template != fn.DefaultTemplate {
 		b.WriteString(" -t " + cfg.Template)
 	}
 	if cmd.Flags().Lookup("repository").Changed {
 		b.WriteString(" -r " + cfg.Repository)
 	}
+	for _, k := range sortedParamNames(cfg.Params) {
+		b.WriteString(fmt.Sprintf(" --param %v=%v", k, cfg.Params[k]))
+	}
+	if cfg.NoHooks {
+		b.WriteString(" --no-hooks")
+	} else if cfg.TrustHooks {
+		b.WriteString(" --trust-hooks")
+	}
 	if cmd.Flags().Lookup("verbose").Changed {
 		b.WriteString(fmt.Sprintf(" -v %v", cfg.Verbose))
 	}
@@ -385,6 +539,12 @@ func newInvalidTemplateError(client *fn.Client, runtime, template string) error
 // mutating the values. The provided clientFn is used to construct a transient
 // client for use during prompt autocompletion/suggestions (such as suggesting
 // valid templates)
+//
+// The user chooses a language runtime, a template of any repository (shown
+// with its description) and values for the template's parameters.  The
+// default registry and namespace are then asked for, and saved to the global
+// config once the user confirms creating the function after a preview of
+// its files.
 func (c createConfig) prompt(client *fn.Client) (createConfig, error) {
 	var qs []*survey.Question
 
@@ -421,28 +581,227 @@ func (c createConfig) prompt(client *fn.Client) (createConfig, error) {
 		return c, err
 	}
 
-	// Second loop: choose template with autocompletion filtered by chosen runtime
-	qs = []*survey.Question{
+	c.Name, c.Path = deriveNameAndAbsolutePathFromPath(c.Path)
+
+	// Second loop: choose template.  Templates of all installed repositories
+	// are listed with their descriptions.  The templates of a repository
+	// override are not yet known, so the name is entered with suggestions.
+	templates, err := client.Templates().List(c.Runtime)
+	if err != nil {
+		return c, err
+	}
+	var templatePrompt survey.Prompt = &survey.Select{
+		Message: "Template:",
+		Options: templates,
+		Default: surveySelectDefault(c.Template, templates),
+		Description: func(value string, _ int) string {
+			if t, err := client.Templates().Get(c.Runtime, value); err == nil {
+				return t.Description()
+			}
+			return ""
+		},
+	}
+	if c.Repository != "" || len(templates) == 0 {
+		templatePrompt = &survey.Input{
+			Message: "Template:",
+			Default: c.Template,
+			Suggest: func(prefix string) []string {
+				suggestions, err := templatesWithPrefix(prefix, c.Runtime, client)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "unable to suggest: %v", err)
+				}
+				return suggestions
+			},
+		}
+	}
+	qs = []*survey.Question{{Name: "Template", Prompt: templatePrompt}}
+	if err := survey.Ask(qs, &c); err != nil {
+		return c, err
+	}
+
+	// Third, values for any parameters declared by the chosen template
+	// which were not already provided.
+	if c.Repository != "" {
+		// templates of a repository override are not yet known
+		defaults, err := promptGlobalDefaults()
+		if err != nil {
+			return c, err
+		}
+		return c, defaults.save()
+	}
+	t, err := client.Templates().Get(c.Runtime, c.Template)
+	if err != nil {
+		return c, err
+	}
+	for _, p := range t.Parameters() {
+		if _, ok := c.Params[p.Name]; ok {
+			continue
+		}
+		var (
+			value  string
+			prompt survey.Prompt
+		)
+		if len(p.Choices) > 0 {
+			prompt = &survey.Select{
+				Message: p.Name + ":",
+				Help:    p.Description,
+				Options: p.Choices,
+				Default: surveySelectDefault(p.Default, p.Choices),
+			}
+		} else {
+			prompt = &survey.Input{
+				Message: p.Name + ":",
+				Help:    p.Description,
+				Default: p.Default,
+			}
+		}
+		if err := survey.AskOne(prompt, &value, survey.WithValidator(survey.Required)); err != nil {
+			return c, err
+		}
+		if c.Params == nil {
+			c.Params = map[string]string{}
+		}
+		c.Params[p.Name] = value
+	}
+
+	// Defaults for functions' deployment, and a preview of the files to be
+	// created, before confirming.
+	defaults, err := promptGlobalDefaults()
+	if err != nil {
+		return c, err
+	}
+	if err = previewTemplate(t, c); err != nil {
+		return c, err
+	}
+	confirmed := true
+	if err = survey.AskOne(&survey.Confirm{Message: "Create function?", Default: true}, &confirmed); err != nil {
+		return c, err
+	}
+	if !confirmed {
+		return c, errors.New("function creation canceled")
+	}
+	return c, defaults.save()
+}
+
+// globalDefaults are the global settings chosen when creating a function
+// interactively.
+type globalDefaults struct {
+	Registry  string
+	Namespace string
+
+	// defaultRegistry and defaultNamespace are those presented, of which
+	// only changed values are saved.
+	defaultRegistry, defaultNamespace string
+}
+
+// promptGlobalDefaults for the registry and namespace to which functions are
+// deployed by default, with the current global settings as defaults.
+func promptGlobalDefaults() (d globalDefaults, err error) {
+	cfg, err := config.NewDefault()
+	if err != nil {
+		return
+	}
+	// The default registry may be detected from the cluster, such as on
+	// OpenShift, which is not to be saved unless chosen otherwise
+	d.defaultRegistry, d.defaultNamespace = cfg.RegistryDefault(), cfg.Namespace
+	d.Registry, d.Namespace = d.defaultRegistry, d.defaultNamespace
+	qs := []*survey.Question{
 		{
-			Name: "Template",
+			Name: "Registry",
 			Prompt: &survey.Input{
-				Message: "Template:",
-				Default: c.Template,
-				Suggest: func(prefix string) []string {
-					suggestions, err := templatesWithPrefix(prefix, c.Runtime, client)
-					if err != nil {
-						fmt.Fprintf(os.Stderr, "unable to suggest: %v", err)
-					}
-					return suggestions
-				},
+				Message: "Default registry:",
+				Help:    "Registry to which functions' images are pushed by default, for example ghcr.io/alice. Saved to " + config.File(),
+				Default: d.Registry,
+			},
+		}, {
+			Name: "Namespace",
+			Prompt: &survey.Input{
+				Message: "Default namespace:",
+				Help:    "Namespace to which functions are deployed by default. Empty for the current kubernetes context's namespace. Saved to " + config.File(),
+				Default: d.Namespace,
 			},
 		},
 	}
-	if err := survey.Ask(qs, &c); err != nil {
-		return c, err
+	err = survey.Ask(qs, &d)
+	return
+}
+
+// save the defaults to the global config file if they were changed.  Only
+// the settings of the config file are written, not static defaults.
+func (d globalDefaults) save() (err error) {
+	if d.Registry == d.defaultRegistry && d.Namespace == d.defaultNamespace {
+		return
+	}
+	cfg := config.Global{}
+	if _, err = os.Stat(config.File()); err == nil {
+		if cfg, err = config.Load(config.File()); err != nil {
+			return
+		}
+	} else if !os.IsNotExist(err) {
+		return
+	}
+	if d.Registry != d.defaultRegistry {
+		cfg.Registry = d.Registry
+	}
+	if d.Namespace != d.defaultNamespace {
+		cfg.Namespace = d.Namespace
+	}
+	if err = config.CreatePaths(); err != nil {
+		return
+	}
+	if err = cfg.Write(config.File()); err != nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Defaults saved to %v\n", config.File())
+	return
+}
+
+// previewTemplate prints the tree of files which the template would write
+// with the config's parameters.
+func previewTemplate(t fn.Template, c createConfig) error {
+	root, err := os.MkdirTemp("", "func-preview")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(root)
+
+	f := fn.Function{
+		Name:           c.Name,
+		Root:           root,
+		Runtime:        c.Runtime,
+		Template:       c.Template,
+		TemplateParams: c.Params,
 	}
+	if err = t.Write(context.Background(), &f); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Files:\n%v/\n", c.Name)
+	return printFileTree(os.Stderr, root, "")
+}
 
-	return c, nil
+// printFileTree prints the files of dir, recursively, in the style of tree(1).
+func printFileTree(w io.Writer, dir, indent string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for i, e := range entries {
+		branch, next := "├── ", "│   "
+		if i == len(entries)-1 {
+			branch, next = "└── ", "    "
+		}
+		name := e.Name()
+		if e.IsDir() {
+			name += "/"
+		}
+		fmt.Fprintf(w, "%v%v%v\n", indent, branch, name)
+		if e.IsDir() {
+			if err = printFileTree(w, filepath.Join(dir, e.Name()), indent+next); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // Tab Completion and Prompt Suggestions Helpers
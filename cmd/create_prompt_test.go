@@ -0,0 +1,54 @@
+//go:build linux
+// +build linux
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"knative.dev/func/pkg/config"
+	fn "knative.dev/func/pkg/functions"
+)
+
+// TestCreate_Prompt ensures that a function can be created interactively,
+// choosing a language and template, and that the default registry and
+// namespace chosen are saved to the global config.
+func TestCreate_Prompt(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "nonexistent"))
+	t.Setenv("FUNC_CONFIRM", "true")
+	t.Setenv("FUNC_LANGUAGE", "go")
+
+	cmd := NewCreateCmd(NewClient)
+	run := createRunFunc(cmd, t)
+	run(filepath.Join(root, "myfunc"),
+		enter,          // path
+		enter,          // language (go)
+		arrowUp, enter, // template (cloudevents, above http)
+		"example.com/alice", enter, // registry
+		"dev", enter, // namespace
+		enter, // confirm
+	)
+
+	f, err := fn.NewFunction(filepath.Join(root, "myfunc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Initialized() || f.Runtime != "go" || f.Invoke != "cloudevent" {
+		t.Fatalf("expected a go cloudevents function, got runtime %q invoke %q", f.Runtime, f.Invoke)
+	}
+	if _, err := os.Stat(filepath.Join(root, "myfunc", "handle.go")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.Load(config.File())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Registry != "example.com/alice" || cfg.Namespace != "dev" {
+		t.Fatalf("expected registry and namespace to be saved, got %q and %q", cfg.Registry, cfg.Namespace)
+	}
+}
@@ -2,8 +2,13 @@ package cmd
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
+	"knative.dev/func/pkg/config"
+	fn "knative.dev/func/pkg/functions"
 	. "knative.dev/func/pkg/testing"
 	"knative.dev/func/pkg/utils"
 )
@@ -98,3 +103,117 @@ func TestCreate_ConfigOptional(t *testing.T) {
 	// Not failing is success.  Config files or settings beyond what are
 	// automatically written to to the given config home are currently optional.
 }
+
+// TestCreate_Param ensures that template parameters are validated: malformed
+// values are rejected, as are parameters the template does not declare.
+func TestCreate_Param(t *testing.T) {
+	_ = FromTempDirectory(t)
+
+	cmd := NewCreateCmd(NewClient)
+	cmd.SetArgs([]string{"--language", "go", "--param", "invalid", "myfunc"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected malformed --param to error")
+	}
+
+	cmd = NewCreateCmd(NewClient)
+	cmd.SetArgs([]string{"--language", "go", "--param", "name=value", "myfunc"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected --param for a template without parameters to error")
+	}
+}
+
+// TestCreate_Adopt ensures that an existing project can be adopted in place,
+// with its language inferred.
+func TestCreate_Adopt(t *testing.T) {
+	root := FromTempDirectory(t)
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module myfunc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "handle.go"), []byte("package myfunc\n\nimport \"net/http\"\n\nfunc Handle(w http.ResponseWriter, r *http.Request) {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewCreateCmd(NewClient)
+	cmd.SetArgs([]string{"--adopt"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Initialized() || f.Runtime != "go" {
+		t.Fatalf("expected an initialized go function, got runtime %q", f.Runtime)
+	}
+}
+
+// TestCreate_HooksNotTrusted ensures that the post-create hooks of a template
+// from a repository other than the default are refused when not in an
+// interactive terminal, unless trusted with --trust-hooks.
+func TestCreate_HooksNotTrusted(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks use touch")
+	}
+	_ = FromTempDirectory(t)
+
+	dir := filepath.Join(config.RepositoriesPath(), "hooks", "go", "http")
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "hooks:\n  postCreate:\n  - command: touch hooked\n"
+	if err := os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "handle.go"), []byte("package function\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewCreateCmd(NewClient)
+	cmd.SetArgs([]string{"--language", "go", "--template", "hooks/http", "refused"})
+	if err := cmd.Execute(); !errors.Is(err, fn.ErrHooksNotTrusted) {
+		t.Fatalf("expected ErrHooksNotTrusted, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join("refused", "hooked")); !os.IsNotExist(err) {
+		t.Fatalf("expected hook not to have been run. %v", err)
+	}
+
+	cmd = NewCreateCmd(NewClient)
+	cmd.SetArgs([]string{"--language", "go", "--template", "hooks/http", "--trust-hooks", "trusted"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join("trusted", "hooked")); err != nil {
+		t.Fatalf("expected hook to have been run. %v", err)
+	}
+}
+
+// TestCreate_GlobalDefaultsSave ensures that only the global defaults changed
+// from those presented are saved, such that a registry detected from the
+// cluster is not persisted by merely accepting it.
+func TestCreate_GlobalDefaultsSave(t *testing.T) {
+	_ = FromTempDirectory(t)
+
+	detected := "image-registry.openshift-image-registry.svc:5000/default"
+
+	// Accepting the presented defaults saves nothing
+	d := globalDefaults{Registry: detected, defaultRegistry: detected}
+	if err := d.save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(config.File()); !os.IsNotExist(err) {
+		t.Fatalf("expected no config to be saved, got %v", err)
+	}
+
+	// Changing only the namespace saves only the namespace
+	d.Namespace = "myns"
+	if err := d.save(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(config.File())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Registry != "" || cfg.Namespace != "myns" {
+		t.Fatalf("expected only the namespace to be saved, got registry %q namespace %q", cfg.Registry, cfg.Namespace)
+	}
+}
@@ -29,11 +29,14 @@ No local files are deleted.
 
 # Undeploy the function 'myfunc' in namespace 'apps'
 {{rootCmdUse}} delete myfunc --namespace apps
+
+# Undeploy the function defined in the local directory from its "staging" environment
+{{rootCmdUse}} delete --environment staging
 `,
 		SuggestFor:        []string{"remove", "del"},
 		Aliases:           []string{"rm"},
 		ValidArgsFunction: CompleteFunctionList,
-		PreRunE:           bindEnv("path", "confirm", "all", "namespace", "verbose"),
+		PreRunE:           bindEnv("path", "confirm", "all", "namespace", "verbose", "environment"),
 		SilenceUsage:      true, // no usage dump on error
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runDelete(cmd, args, newClient)
@@ -50,6 +53,7 @@ No local files are deleted.
 	cmd.Flags().StringP("namespace", "n", defaultNamespace(fn.Function{}, false), "The namespace when deleting by name. ($FUNC_NAMESPACE)")
 	cmd.Flags().StringP("all", "a", "true", "Delete all resources created for a function, eg. Pipelines, Secrets, etc. ($FUNC_ALL) (allowed values: \"true\", \"false\")")
 	addConfirmFlag(cmd, cfg.Confirm)
+	addEnvironmentFlag(cmd)
 	addPathFlag(cmd)
 	addVerboseFlag(cmd, cfg.Verbose)
 
@@ -75,16 +79,32 @@ func runDelete(cmd *cobra.Command, args []string, newClient ClientFactory) (err
 		if err != nil {
 			return err
 		}
-		return client.Remove(cmd.Context(), "", "", f, cfg.All)
+		if cfg.Environment == "" {
+			return client.Remove(cmd.Context(), "", "", f, cfg.All)
+		}
+		// Remove from the named environment and clear its deployed state.
+		base := f
+		if f, err = f.ForEnvironment(cfg.Environment); err != nil {
+			return err
+		}
+		if f.Deploy.Namespace == "" {
+			return fmt.Errorf("function %v is not deployed to the %q environment", f.Name, cfg.Environment)
+		}
+		if err = client.Remove(cmd.Context(), "", "", f, cfg.All); err != nil {
+			return err
+		}
+		delete(base.Deploy.Environments, cfg.Environment)
+		return base.Write()
 	}
 }
 
 type deleteConfig struct {
-	Name      string
-	Namespace string
-	Path      string
-	All       bool
-	Verbose   bool
+	Name        string
+	Namespace   string
+	Path        string
+	All         bool
+	Verbose     bool
+	Environment string
 }
 
 // newDeleteConfig returns a config populated from the current execution context
@@ -95,11 +115,12 @@ func newDeleteConfig(cmd *cobra.Command, args []string) (cfg deleteConfig, err e
 		name = args[0]
 	}
 	cfg = deleteConfig{
-		All:       viper.GetBool("all"),
-		Name:      name, // args[0] or derived
-		Namespace: viper.GetString("namespace"),
-		Path:      viper.GetString("path"),
-		Verbose:   viper.GetBool("verbose"), // defined on root
+		All:         viper.GetBool("all"),
+		Name:        name, // args[0] or derived
+		Namespace:   viper.GetString("namespace"),
+		Path:        viper.GetString("path"),
+		Verbose:     viper.GetBool("verbose"), // defined on root
+		Environment: viper.GetString("environment"),
 	}
 	if cfg.Name == "" && cmd.Flags().Changed("namespace") {
 		// logicially inconsistent to supply only a namespace.
@@ -113,6 +134,10 @@ func newDeleteConfig(cmd *cobra.Command, args []string) (cfg deleteConfig, err e
 		// a name and a namespace to ignore any local function source.
 		err = fmt.Errorf("only one of --path and [NAME] should be provided")
 	}
+	if cfg.Name != "" && cfg.Environment != "" {
+		// environments are defined by the function's local state
+		err = fmt.Errorf("only one of --environment and [NAME] should be provided")
+	}
 	return
 }
 
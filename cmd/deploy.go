@@ -34,7 +34,8 @@ SYNOPSIS
 	             [-b|--build] [--builder] [--builder-image] [-p|--push]
 	             [--domain] [--platform] [--build-timestamp] [--pvc-size]
 	             [--service-account] [-c|--confirm] [-v|--verbose]
-	             [--registry-insecure] [--remote-storage-class]
+	             [--registry-insecure] [--remote-storage-class] [--retry-last]
+	             [--all] [--environment]
 
 DESCRIPTION
 
@@ -77,6 +78,11 @@ DESCRIPTION
 	  eliminating the need for a local container engine.  To trigger deployment
 	  of a git repository instead of local source, combine with '--git-url':
 	  '{{rootCmdUse}} deploy --remote --git-url=git.example.com/alice/f.git'
+	  Interrupting a remote deployment asks to cancel its pipeline run on the
+	  cluster, such that it does not race the next deployment.  A failed or
+	  cancelled remote deployment can be retried with '--retry-last', which
+	  runs the previous pipeline run again using the source code it was sent,
+	  without uploading the sources again.
 
 	Domain
 	  When deploying, a function's route is automatically generated using the
@@ -86,6 +92,32 @@ DESCRIPTION
 	  selectors. Note that the domain specified must be one of those configured
 	  or the flag will be ignored.
 
+	Environments
+	  Named environments (such as "staging" or "prod") may be defined in the
+	  function's func.yaml, each overriding the namespace, registry, domain,
+	  environment variables and scale of the function:
+	    environments:
+	      staging:
+	        namespace: staging
+	        envs:
+	        - name: LOG_LEVEL
+	          value: debug
+	  Use --environment to deploy to a named environment.  The deployed state is
+	  recorded for each environment, such that it can subsequently be described,
+	  invoked and deleted with --environment as well.  Flags which override
+	  values defined by the environment apply only to that deployment and are
+	  not persisted.  The image built for an environment is likewise recorded
+	  for that environment only.
+
+	Workspaces
+	  The --all flag deploys every function of the workspace rooted at --path.
+	  Functions of a workspace are those listed in its func-workspace.yaml, or
+	  all functions found beneath the workspace root if none are listed.
+	  A registry and namespace defined by the workspace are used for those
+	  functions which do not define their own, without being written to their
+	  func.yaml.  Flags which describe a single function, such as --image or
+	  --env, can not be used with --all, nor can --environment.
+
 EXAMPLES
 
 	o Deploy the function
@@ -107,6 +139,9 @@ EXAMPLES
 	  be built and deployed:
 	  $ {{rootCmdUse}} deploy --remote
 
+	o Retry the last remote deploy with the same sources, without uploading them
+	  $ {{rootCmdUse}} deploy --remote --retry-last
+
 	o Trigger a remote deploy, which instructs the cluster to build and deploy
 	  the function in the specified git repository.
 	  $ {{rootCmdUse}} deploy --remote --git-url=https://example.com/alice/myfunc.git
@@ -124,9 +159,15 @@ EXAMPLES
 	  manually deleted from the cluster, it can be quickly redeployed with:
 	  $ {{rootCmdUse}} deploy --build=false --push=false
 
+	o Deploy the function to its "staging" environment defined in func.yaml.
+	  $ {{rootCmdUse}} deploy --environment=staging
+
+	o Deploy all functions of the workspace in the current directory.
+	  $ {{rootCmdUse}} deploy --all
+
 `,
 		SuggestFor: []string{"delpoy", "deplyo"},
-		PreRunE:    bindEnv("build", "build-timestamp", "builder", "builder-image", "confirm", "domain", "env", "git-branch", "git-dir", "git-url", "image", "namespace", "path", "platform", "push", "pvc-size", "service-account", "registry", "registry-insecure", "remote", "username", "password", "token", "verbose", "remote-storage-class"),
+		PreRunE:    bindEnv("build", "build-timestamp", "builder", "builder-image", "confirm", "domain", "env", "git-branch", "git-dir", "git-url", "image", "namespace", "path", "platform", "push", "pvc-size", "service-account", "registry", "registry-insecure", "remote", "retry-last", "username", "password", "token", "verbose", "remote-storage-class", "all", "environment"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runDeploy(cmd, newClient)
 		},
@@ -200,6 +241,8 @@ EXAMPLES
 		"Password to use when pushing to the registry.")
 	cmd.Flags().StringP("token", "", "",
 		"Token to use when pushing to the registry.")
+	cmd.Flags().Bool("retry-last", false,
+		"Rerun the last remote pipeline run of the function with the sources it was run with, rather than uploading them again. Requires --remote. ($FUNC_RETRY_LAST)")
 	cmd.Flags().BoolP("build-timestamp", "", false, "Use the actual time as the created time for the docker image. This is only useful for buildpacks builder.")
 	cmd.Flags().StringP("namespace", "n", defaultNamespace(f, false),
 		"Deploy into a specific namespace. Will use the function's current namespace by default if already deployed, and the currently active context if it can be determined. ($FUNC_NAMESPACE)")
@@ -212,7 +255,9 @@ EXAMPLES
 	_ = cmd.Flags().MarkHidden("token")
 
 	// Oft-shared flags:
+	addAllFlag(cmd)
 	addConfirmFlag(cmd, cfg.Confirm)
+	addEnvironmentFlag(cmd)
 	addPathFlag(cmd)
 	addVerboseFlag(cmd, cfg.Verbose)
 
@@ -233,6 +278,9 @@ func runDeploy(cmd *cobra.Command, newClient ClientFactory) (err error) {
 		cfg deployConfig
 		f   fn.Function
 	)
+	if viper.GetBool("all") { // deploy each function of the workspace
+		return runDeployAll(cmd, newClient)
+	}
 	if cfg, err = newDeployConfig(cmd).Prompt(); err != nil {
 		return
 	}
@@ -245,11 +293,34 @@ func runDeploy(cmd *cobra.Command, newClient ClientFactory) (err error) {
 	if !f.Initialized() {
 		return fn.NewErrNotInitialized(f.Root)
 	}
+	original := f
 	if f, err = cfg.Configure(f); err != nil { // Updates f with deploy cfg
 		return
 	}
 	cmd.SetContext(cfg.WithValues(cmd.Context())) // Some optional settings are passed via context
 
+	// Named environment
+	// The function as configured is retained to record the deployed state of
+	// the environment, with the values the environment overrides restored
+	// such that explicitly provided flags apply only to this deployment.
+	var base fn.Function
+	if cfg.Environment != "" {
+		base = f
+		base.Namespace, base.Registry, base.Domain = original.Namespace, original.Registry, original.Domain
+		if f, err = f.ForEnvironment(cfg.Environment); err != nil {
+			return
+		}
+		if cmd.Flags().Changed("namespace") {
+			f.Namespace = cfg.Namespace
+		}
+		if cmd.Flags().Changed("registry") {
+			f.Registry = cfg.Registry
+		}
+		if cmd.Flags().Changed("domain") {
+			f.Domain = cfg.Domain
+		}
+	}
+
 	changingNamespace := func(f fn.Function) bool {
 		// We're changing namespace if:
 		return f.Deploy.Namespace != "" && // it's already deployed
@@ -288,7 +359,12 @@ func runDeploy(cmd *cobra.Command, newClient ClientFactory) (err error) {
 		// Invoke a remote build/push/deploy pipeline
 		// Returned is the function with fields like Registry, f.Deploy.Image &
 		// f.Deploy.Namespace populated.
-		if url, f, err = client.RunPipeline(cmd.Context(), f); err != nil {
+		if cfg.RetryLast {
+			url, f, err = client.RerunPipeline(cmd.Context(), f)
+		} else {
+			url, f, err = client.RunPipeline(cmd.Context(), f)
+		}
+		if err != nil {
 			return
 		}
 		fmt.Fprintf(cmd.OutOrStdout(), "Function Deployed at %v\n", url)
@@ -344,6 +420,14 @@ func runDeploy(cmd *cobra.Command, newClient ClientFactory) (err error) {
 		}
 	}
 
+	// Record the deployed state of a named environment on the function.
+	// The build state is kept for each environment, such that the function's
+	// own is left as is, and is stamped below.
+	built := f
+	if cfg.Environment != "" {
+		f = base.WithEnvironmentDeployment(cfg.Environment, f)
+	}
+
 	// Write
 	if err = f.Write(); err != nil {
 		return
@@ -353,7 +437,7 @@ func runDeploy(cmd *cobra.Command, newClient ClientFactory) (err error) {
 	// Updates the build stamp because building must have been accomplished
 	// during this process, and a future call to deploy without any appreciable
 	// changes to the filesystem should not rebuild again unless `--build`
-	return f.Stamp()
+	return built.Stamp()
 }
 
 // build when flag == 'auto' and the function is out-of-date, or when the
@@ -504,6 +588,10 @@ type deployConfig struct {
 	// be triggered in a remote environment rather than run locally.
 	Remote bool
 
+	// RetryLast reruns the function's last remote pipeline run with the
+	// sources it was run with instead of uploading them again.
+	RetryLast bool
+
 	// RemoteStorageClass defines the storage class to use for the remote
 	// volume when building on-cluster.
 	RemoteStorageClass string
@@ -514,6 +602,9 @@ type deployConfig struct {
 	// Timestamp the built contaienr with the current date and time.
 	// This is currently only supported by the Pack builder.
 	Timestamp bool
+
+	// Environment is the optional named environment to deploy to.
+	Environment string
 }
 
 // newDeployConfig creates a buildConfig populated from command flags and
@@ -529,10 +620,12 @@ func newDeployConfig(cmd *cobra.Command) deployConfig {
 		GitURL:             viper.GetString("git-url"),
 		Namespace:          viper.GetString("namespace"),
 		Remote:             viper.GetBool("remote"),
+		RetryLast:          viper.GetBool("retry-last"),
 		RemoteStorageClass: viper.GetString("remote-storage-class"),
 		PVCSize:            viper.GetString("pvc-size"),
 		Timestamp:          viper.GetBool("build-timestamp"),
 		ServiceAccountName: viper.GetString("service-account"),
+		Environment:        viper.GetString("environment"),
 	}
 	// NOTE: .Env should be viper.GetStringSlice, but this returns unparsed
 	// results and appears to be an open issue since 2017:
@@ -711,6 +804,11 @@ func (c deployConfig) Validate(cmd *cobra.Command) (err error) {
 		return errors.New("git settings (--git-url --git-dir and --git-branch) are only applicable when triggering remote deployments (--remote)")
 	}
 
+	// Retrying a pipeline run is only applicable to remote deployments
+	if c.RetryLast && !c.Remote {
+		return errors.New("--retry-last is only applicable when triggering remote deployments (--remote)")
+	}
+
 	// Git URL can contain at maximum one '#'
 	urlParts := strings.Split(c.GitURL, "#")
 	if len(urlParts) > 2 {
@@ -1767,6 +1767,40 @@ func TestDeploy_RemotePersists(t *testing.T) {
 	}
 }
 
+// TestDeploy_RetryLast ensures that --retry-last reruns the last pipeline run
+// rather than running a new one, and is only valid for remote deployments.
+func TestDeploy_RetryLast(t *testing.T) {
+	root := FromTempDirectory(t)
+
+	_, err := fn.New().Init(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pipeliner := mock.NewPipelinesProvider()
+	cmd := NewDeployCmd(NewTestClient(fn.WithPipelinesProvider(pipeliner)))
+	cmd.SetArgs([]string{"--retry-last"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an error retrying without --remote")
+	}
+	if pipeliner.RerunInvoked || pipeliner.RunInvoked {
+		t.Fatal("pipelines provider invoked despite invalid flags")
+	}
+
+	viper.Reset()
+	cmd = NewDeployCmd(NewTestClient(fn.WithPipelinesProvider(pipeliner)))
+	cmd.SetArgs([]string{"--remote", "--retry-last", "--namespace", "ns"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !pipeliner.RerunInvoked {
+		t.Fatal("the last pipeline run was not rerun")
+	}
+	if pipeliner.RunInvoked {
+		t.Fatal("a new pipeline run was started instead of a rerun")
+	}
+}
+
 // TestDeploy_UnsetFlag ensures that unsetting a flag on the command
 // line causes the pertinent value to be zeroed out.
 func TestDeploy_UnsetFlag(t *testing.T) {
@@ -2170,3 +2204,128 @@ func Test_isDigested(t *testing.T) {
 		t.Fatal("did not report image reference has digest")
 	}
 }
+
+// TestDeploy_All ensures that --all deploys each function of the workspace,
+// cascading workspace settings to the functions without persisting them.
+func TestDeploy_All(t *testing.T) {
+	root := FromTempDirectory(t)
+
+	for _, name := range []string{"a", "b"} {
+		if _, err := fn.New().Init(fn.Function{Runtime: "go", Root: filepath.Join(root, name)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w := fn.Workspace{Root: root, Registry: TestRegistry, Namespace: "workspace-ns"}
+	if err := w.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	deployer := mock.NewDeployer()
+	cmd := NewDeployCmd(NewTestClient(
+		fn.WithBuilder(mock.NewBuilder()),
+		fn.WithPusher(mock.NewPusher()),
+		fn.WithDeployer(deployer)))
+	cmd.SetArgs([]string{"--all"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"a", "b"} {
+		f, err := fn.NewFunction(filepath.Join(root, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Deploy.Namespace != "workspace-ns" {
+			t.Fatalf("expected %v deployed to 'workspace-ns', got %q", name, f.Deploy.Namespace)
+		}
+		if !strings.HasPrefix(f.Deploy.Image, TestRegistry) {
+			t.Fatalf("expected %v deployed image in registry %q, got %q", name, TestRegistry, f.Deploy.Image)
+		}
+		if f.Registry != "" || f.Namespace != "" {
+			t.Fatalf("expected workspace settings not to be written to %v, got %q/%q", name, f.Registry, f.Namespace)
+		}
+	}
+
+	// Flags describing a single function are not accepted
+	cmd = NewDeployCmd(NewTestClient(fn.WithDeployer(deployer)))
+	cmd.SetArgs([]string{"--all", "--image=example.com/alice/f:latest"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected --image with --all to error")
+	}
+
+	// Environments are defined per function, and not silently ignored
+	deployer.DeployInvoked = false
+	cmd = NewDeployCmd(NewTestClient(fn.WithDeployer(deployer)))
+	cmd.SetArgs([]string{"--all", "--environment=staging"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected --environment with --all to error")
+	}
+	if deployer.DeployInvoked {
+		t.Fatal("expected no function to be deployed")
+	}
+}
+
+// TestDeploy_Environment ensures that deploying to a named environment applies
+// its overrides and records its deployed state without affecting the
+// default deployment.
+func TestDeploy_Environment(t *testing.T) {
+	root := FromTempDirectory(t)
+
+	f, err := fn.New().Init(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Environments = map[string]fn.Environment{
+		"staging": {Namespace: "staging"},
+	}
+	if err = f.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	deployer := mock.NewDeployer()
+	deployer.DeployFn = func(_ context.Context, f fn.Function) (fn.DeploymentResult, error) {
+		if f.Namespace != "staging" {
+			t.Fatalf("expected deploy to namespace 'staging', got %q", f.Namespace)
+		}
+		return fn.DeploymentResult{Status: fn.Deployed, Namespace: f.Namespace}, nil
+	}
+	cmd := NewDeployCmd(NewTestClient(
+		fn.WithBuilder(mock.NewBuilder()),
+		fn.WithPusher(mock.NewPusher()),
+		fn.WithDeployer(deployer)))
+	cmd.SetArgs([]string{"--environment=staging"})
+	if err = cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !deployer.DeployInvoked {
+		t.Fatal("deployer was not invoked")
+	}
+
+	if f, err = fn.NewFunction(root); err != nil {
+		t.Fatal(err)
+	}
+	if f.Deploy.Environments["staging"].Namespace != "staging" {
+		t.Fatalf("expected staging deployment recorded, got %v", f.Deploy.Environments)
+	}
+	if f.Deploy.Namespace != "" || f.Namespace == "staging" {
+		t.Fatalf("expected the default deployment to be unaffected, got %q/%q", f.Namespace, f.Deploy.Namespace)
+	}
+	// The build is recorded for the environment only
+	if f.Build.Image != "" || f.Built() {
+		t.Fatalf("expected the default build state to be unaffected, got %q", f.Build.Image)
+	}
+	staging, err := f.ForEnvironment("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staging.Build.Image == "" || !staging.Built() {
+		t.Fatalf("expected the staging build state to be recorded, got %q", staging.Build.Image)
+	}
+
+	// Undefined environments are an error
+	cmd = NewDeployCmd(NewTestClient(fn.WithDeployer(deployer)))
+	cmd.SetArgs([]string{"--environment=prod"})
+	if err = cmd.Execute(); !errors.Is(err, fn.ErrEnvironmentNotFound) {
+		t.Fatalf("expected ErrEnvironmentNotFound, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/ory/viper"
 	"github.com/spf13/cobra"
@@ -22,7 +23,8 @@ func NewDescribeCmd(newClient ClientFactory) *cobra.Command {
 		Long: `Describe a function
 
 Prints the name, route and event subscriptions for a deployed function in
-the current directory or from the directory specified with --path.
+the current directory or from the directory specified with --path, as well
+as the outcome of its last on-cluster build.
 `,
 		Example: `
 # Show the details of a function as declared in the local func.yaml
@@ -30,12 +32,15 @@ the current directory or from the directory specified with --path.
 
 # Show the details of the function in the directory with yaml output
 {{rootCmdUse}} describe --output yaml --path myotherfunc
+
+# Show the details of the function deployed to its "staging" environment
+{{rootCmdUse}} describe --environment staging
 `,
 		SuggestFor: []string{"ifno", "fino", "get"},
 
 		ValidArgsFunction: CompleteFunctionList,
 		Aliases:           []string{"info", "desc"},
-		PreRunE:           bindEnv("output", "path", "namespace", "verbose"),
+		PreRunE:           bindEnv("output", "path", "namespace", "verbose", "environment"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runDescribe(cmd, args, newClient)
 		},
@@ -50,6 +55,7 @@ the current directory or from the directory specified with --path.
 	// Flags
 	cmd.Flags().StringP("output", "o", "human", "Output format (human|plain|json|xml|yaml|url) ($FUNC_OUTPUT)")
 	cmd.Flags().StringP("namespace", "n", defaultNamespace(fn.Function{}, false), "The namespace in which to look for the named function. ($FUNC_NAMESPACE)")
+	addEnvironmentFlag(cmd)
 	addPathFlag(cmd)
 	addVerboseFlag(cmd, cfg.Verbose)
 
@@ -81,6 +87,9 @@ func runDescribe(cmd *cobra.Command, args []string, newClient ClientFactory) (er
 		if err != nil {
 			return err
 		}
+		if f, err = f.ForEnvironment(cfg.Environment); err != nil {
+			return err
+		}
 		details, err = client.Describe(cmd.Context(), "", "", f)
 		if err != nil {
 			return err
@@ -95,11 +104,12 @@ func runDescribe(cmd *cobra.Command, args []string, newClient ClientFactory) (er
 // ------------------------------
 
 type describeConfig struct {
-	Name      string
-	Namespace string
-	Output    string
-	Path      string
-	Verbose   bool
+	Name        string
+	Namespace   string
+	Output      string
+	Path        string
+	Verbose     bool
+	Environment string
 }
 
 func newDescribeConfig(cmd *cobra.Command, args []string) (cfg describeConfig, err error) {
@@ -108,11 +118,12 @@ func newDescribeConfig(cmd *cobra.Command, args []string) (cfg describeConfig, e
 		name = args[0]
 	}
 	cfg = describeConfig{
-		Name:      name,
-		Namespace: viper.GetString("namespace"),
-		Output:    viper.GetString("output"),
-		Path:      viper.GetString("path"),
-		Verbose:   viper.GetBool("verbose"),
+		Name:        name,
+		Namespace:   viper.GetString("namespace"),
+		Output:      viper.GetString("output"),
+		Path:        viper.GetString("path"),
+		Verbose:     viper.GetBool("verbose"),
+		Environment: viper.GetString("environment"),
 	}
 	if cfg.Name == "" && cmd.Flags().Changed("namespace") {
 		// logicially inconsistent to supply only a namespace.
@@ -126,6 +137,10 @@ func newDescribeConfig(cmd *cobra.Command, args []string) (cfg describeConfig, e
 		// a name and a namespace to ignore any local function source.
 		err = fmt.Errorf("only one of --path and [NAME] should be provided")
 	}
+	if cfg.Name != "" && cfg.Environment != "" {
+		// environments are defined by the function's local state
+		err = fmt.Errorf("only one of --environment and [NAME] should be provided")
+	}
 	return
 }
 
@@ -153,6 +168,20 @@ func (i info) Human(w io.Writer) error {
 			fmt.Fprintf(w, "  %v %v %v\n", s.Source, s.Type, s.Broker)
 		}
 	}
+
+	if b := i.LastBuild; b != nil {
+		fmt.Fprintln(w, "Last build:")
+		fmt.Fprintf(w, "  %v at %v\n", b.Reason, b.Time.Format(time.RFC3339))
+		if b.PipelineRun != "" {
+			fmt.Fprintf(w, "  PipelineRun: %v\n", b.PipelineRun)
+		}
+		if b.GitRevision != "" {
+			fmt.Fprintf(w, "  Git revision: %v\n", b.GitRevision)
+		}
+		if b.Message != "" {
+			fmt.Fprintf(w, "  %v\n", b.Message)
+		}
+	}
 	return nil
 }
 
@@ -170,6 +199,16 @@ func (i info) Plain(w io.Writer) error {
 			fmt.Fprintf(w, "Subscription %v %v %v\n", s.Source, s.Type, s.Broker)
 		}
 	}
+
+	if b := i.LastBuild; b != nil {
+		fmt.Fprintf(w, "LastBuild %v %v\n", b.Reason, b.Time.Format(time.RFC3339))
+		if b.PipelineRun != "" {
+			fmt.Fprintf(w, "LastBuildPipelineRun %v\n", b.PipelineRun)
+		}
+		if b.GitRevision != "" {
+			fmt.Fprintf(w, "LastBuildGitRevision %v\n", b.GitRevision)
+		}
+	}
 	return nil
 }
 
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	fn "knative.dev/func/pkg/functions"
 	"knative.dev/func/pkg/mock"
@@ -80,3 +82,40 @@ func TestDescribe_NameAndPathExclusivity(t *testing.T) {
 		t.Fatal("describer was invoked when conflicting flags were provided")
 	}
 }
+
+// TestDescribe_LastBuild ensures that the outcome of the last on-cluster
+// build is included in the description.
+func TestDescribe_LastBuild(t *testing.T) {
+	describer := mock.NewDescriber()
+	describer.DescribeFn = func(context.Context, string, string) (fn.Instance, error) {
+		return fn.Instance{
+			Name: "testname",
+			LastBuild: &fn.BuildStatus{
+				Reason:      "BuildFailed",
+				Message:     "pipeline run testname-run-abc12 failed",
+				PipelineRun: "testname-run-abc12",
+				GitRevision: "0123abc",
+				Time:        time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+		}, nil
+	}
+
+	stdout := piped(t)
+	cmd := NewDescribeCmd(NewTestClient(fn.WithDescriber(describer)))
+	cmd.SetArgs([]string{"testname"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	output := stdout()
+	for _, s := range []string{
+		"BuildFailed at 2024-01-02T03:04:05Z",
+		"PipelineRun: testname-run-abc12",
+		"Git revision: 0123abc",
+		"pipeline run testname-run-abc12 failed",
+	} {
+		if !strings.Contains(output, s) {
+			t.Errorf("expected output to contain %q, got:\n%v", s, output)
+		}
+	}
+}
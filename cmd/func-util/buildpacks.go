@@ -0,0 +1,301 @@
+//go:build exclude_graphdriver_btrfs || !cgo
+// +build exclude_graphdriver_btrfs !cgo
+
+package main
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pelletier/go-toml"
+	"github.com/spf13/cobra"
+)
+
+const buildpackageLabel = "io.buildpacks.buildpackage.metadata"
+
+func buildpacks(ctx context.Context) error {
+	cmd := newBuildpacksCmd()
+	err := cmd.ExecuteContext(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot prepare buildpacks: %w", err)
+	}
+	return nil
+}
+
+type buildpacksConfig struct {
+	cnbDir string
+	target string
+	refs   []string
+}
+
+func newBuildpacksCmd() *cobra.Command {
+	var config buildpacksConfig
+
+	bpCmd := &cobra.Command{
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config.refs = args
+			return runBuildpacks(cmd.Context(), config)
+		},
+	}
+	bpCmd.Flags().StringVar(&config.cnbDir, "cnb-dir", "/cnb", "")
+	bpCmd.Flags().StringVar(&config.target, "target", "/cnb-custom", "")
+
+	return bpCmd
+}
+
+type orderEntry struct {
+	ID      string `toml:"id"`
+	Version string `toml:"version"`
+}
+
+type orderGroup struct {
+	Group []orderEntry `toml:"group"`
+}
+
+type orderFile struct {
+	Order []orderGroup `toml:"order"`
+}
+
+// runBuildpacks prepares a buildpacks directory and order for the lifecycle
+// creator.  The builder's own buildpacks are linked into the target, and
+// buildpacks given as images are fetched alongside them.  When buildpacks are
+// requested they form the only group of the order, in the given sequence, as
+// is the case for a local pack build.  Otherwise the builder's order is used.
+func runBuildpacks(ctx context.Context, c buildpacksConfig) error {
+	bpDir := filepath.Join(c.target, "buildpacks")
+	if err := linkBuilderBuildpacks(filepath.Join(c.cnbDir, "buildpacks"), bpDir); err != nil {
+		return err
+	}
+
+	var refs []string
+	for _, ref := range c.refs {
+		if ref = strings.TrimSpace(ref); ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) == 0 {
+		return copyFile(filepath.Join(c.cnbDir, "order.toml"), filepath.Join(c.target, "order.toml"))
+	}
+
+	order := orderFile{Order: []orderGroup{{}}}
+	for _, ref := range refs {
+		entry, err := resolveBuildpack(ctx, ref, bpDir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Using buildpack %s@%s\n", entry.ID, entry.Version)
+		order.Order[0].Group = append(order.Order[0].Group, entry)
+	}
+
+	bb, err := toml.Marshal(order)
+	if err != nil {
+		return fmt.Errorf("cannot encode order: %w", err)
+	}
+	return os.WriteFile(filepath.Join(c.target, "order.toml"), bb, 0644)
+}
+
+// linkBuilderBuildpacks links each version of each buildpack of the builder
+// into the target, such that fetched buildpacks can be added next to them.
+func linkBuilderBuildpacks(source, target string) error {
+	ids, err := os.ReadDir(source)
+	if err != nil {
+		return fmt.Errorf("cannot read builder buildpacks: %w", err)
+	}
+	for _, id := range ids {
+		versions, err := os.ReadDir(filepath.Join(source, id.Name()))
+		if err != nil {
+			return fmt.Errorf("cannot read builder buildpacks: %w", err)
+		}
+		if err = os.MkdirAll(filepath.Join(target, id.Name()), 0755); err != nil {
+			return err
+		}
+		for _, v := range versions {
+			link := filepath.Join(target, id.Name(), v.Name())
+			if err = os.Symlink(filepath.Join(source, id.Name(), v.Name()), link); err != nil && !os.IsExist(err) {
+				return fmt.Errorf("cannot link builder buildpack: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveBuildpack returns the order entry for the given buildpack reference,
+// fetching it into the buildpacks directory if it is not part of the builder.
+// References follow pack's conventions: "urn:cnb:builder:<id>[@<version>]"
+// or "<id>[@<version>]" for buildpacks of the builder, and "docker://<image>"
+// or "<image>" for buildpackage images.
+func resolveBuildpack(ctx context.Context, ref, bpDir string) (orderEntry, error) {
+	switch {
+	case strings.HasPrefix(ref, "urn:cnb:builder:"):
+		return builderBuildpack(strings.TrimPrefix(ref, "urn:cnb:builder:"), bpDir)
+	case strings.HasPrefix(ref, "docker://"):
+		return fetchBuildpack(ctx, strings.TrimPrefix(ref, "docker://"), bpDir)
+	case strings.HasPrefix(ref, "urn:cnb:registry:"), strings.HasPrefix(ref, "from="), strings.HasPrefix(ref, "file://"):
+		return orderEntry{}, fmt.Errorf("buildpack %q is not supported for on cluster build", ref)
+	}
+	id, _, _ := strings.Cut(ref, "@")
+	if _, err := os.Stat(filepath.Join(bpDir, escapeID(id))); err == nil {
+		return builderBuildpack(ref, bpDir)
+	}
+	return fetchBuildpack(ctx, ref, bpDir)
+}
+
+func builderBuildpack(ref, bpDir string) (orderEntry, error) {
+	id, version, _ := strings.Cut(ref, "@")
+	dir := filepath.Join(bpDir, escapeID(id))
+	if version != "" {
+		if _, err := os.Stat(filepath.Join(dir, version)); err != nil {
+			return orderEntry{}, fmt.Errorf("buildpack %s@%s not found in builder", id, version)
+		}
+		return orderEntry{ID: id, Version: version}, nil
+	}
+	versions, err := os.ReadDir(dir)
+	if err != nil {
+		return orderEntry{}, fmt.Errorf("buildpack %q not found in builder", id)
+	}
+	if len(versions) != 1 {
+		return orderEntry{}, fmt.Errorf("buildpack %q has %d versions in builder, specify one as %s@<version>", id, len(versions), id)
+	}
+	return orderEntry{ID: id, Version: versions[0].Name()}, nil
+}
+
+// fetchBuildpack pulls a buildpackage image and extracts its buildpacks into
+// the buildpacks directory, replacing any of the same id and version.
+func fetchBuildpack(ctx context.Context, ref, bpDir string) (entry orderEntry, err error) {
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		return entry, fmt.Errorf("buildpack %q is neither part of the builder nor an image: %w", ref, err)
+	}
+	img, err := remote.Image(r,
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithPlatform(v1.Platform{OS: "linux", Architecture: runtime.GOARCH}))
+	if err != nil {
+		return entry, fmt.Errorf("cannot fetch buildpack %q: %w", ref, err)
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return entry, fmt.Errorf("cannot read buildpack %q: %w", ref, err)
+	}
+	label, ok := cfg.Config.Labels[buildpackageLabel]
+	if !ok {
+		return entry, fmt.Errorf("image %q is not a buildpackage (missing label %q)", ref, buildpackageLabel)
+	}
+	if err = json.Unmarshal([]byte(label), &entry); err != nil {
+		return entry, fmt.Errorf("cannot parse metadata of buildpack %q: %w", ref, err)
+	}
+
+	staging, err := os.MkdirTemp(filepath.Dir(bpDir), "fetched-")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(staging)
+
+	rc := mutate.Extract(img)
+	defer rc.Close()
+	if err = extractBuildpacks(rc, staging); err != nil {
+		return entry, fmt.Errorf("cannot extract buildpack %q: %w", ref, err)
+	}
+
+	ids, err := os.ReadDir(staging)
+	if err != nil {
+		return
+	}
+	for _, id := range ids {
+		versions, err := os.ReadDir(filepath.Join(staging, id.Name()))
+		if err != nil {
+			return entry, err
+		}
+		if err = os.MkdirAll(filepath.Join(bpDir, id.Name()), 0755); err != nil {
+			return entry, err
+		}
+		for _, v := range versions {
+			dst := filepath.Join(bpDir, id.Name(), v.Name())
+			if err = os.RemoveAll(dst); err != nil {
+				return entry, err
+			}
+			if err = os.Rename(filepath.Join(staging, id.Name(), v.Name()), dst); err != nil {
+				return entry, err
+			}
+		}
+	}
+	return entry, nil
+}
+
+// extractBuildpacks writes the contents of the image's /cnb/buildpacks
+// directory from the given tar stream into target.
+func extractBuildpacks(r io.Reader, target string) error {
+	const prefix = "cnb/buildpacks/"
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		p := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		if !strings.HasPrefix(p, prefix) {
+			continue
+		}
+		dst := filepath.Join(target, filepath.FromSlash(strings.TrimPrefix(p, prefix)))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(dst, 0755)
+		case tar.TypeReg:
+			err = writeFile(tr, dst, os.FileMode(hdr.Mode).Perm())
+		case tar.TypeSymlink:
+			if err = os.MkdirAll(filepath.Dir(dst), 0755); err == nil {
+				err = os.Symlink(hdr.Linkname, dst)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeFile(r io.Reader, dst string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("cannot read builder order: %w", err)
+	}
+	defer in.Close()
+	return writeFile(in, dst, 0644)
+}
+
+// escapeID returns the directory name of a buildpack id, as laid out by the
+// lifecycle.
+func escapeID(id string) string {
+	return strings.ReplaceAll(id, "/", "_")
+}
@@ -0,0 +1,164 @@
+//go:build exclude_graphdriver_btrfs || !cgo
+// +build exclude_graphdriver_btrfs !cgo
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+// TestBuildpacks_Default ensures that without requested buildpacks the
+// builder's buildpacks and order are used as-is.
+func TestBuildpacks_Default(t *testing.T) {
+	cnb, target := fakeBuilder(t), t.TempDir()
+
+	if err := runBuildpacks(context.Background(), buildpacksConfig{cnbDir: cnb, target: target}); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := os.ReadFile(filepath.Join(target, "order.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(order), "builder-order") {
+		t.Fatalf("expected the builder's order, got:\n%s", order)
+	}
+	if _, err := os.Stat(filepath.Join(target, "buildpacks", "example_go", "1.0.0", "buildpack.toml")); err != nil {
+		t.Fatalf("expected the builder's buildpack to be linked: %v", err)
+	}
+}
+
+// TestBuildpacks_Custom ensures that requested buildpacks, both from the
+// builder and from buildpackage images, make up the order in sequence.
+func TestBuildpacks_Custom(t *testing.T) {
+	cnb, target := fakeBuilder(t), t.TempDir()
+	image := pushBuildpackage(t, "example/extra", "2.0.0")
+
+	cfg := buildpacksConfig{
+		cnbDir: cnb,
+		target: target,
+		refs:   []string{"", "example/go", "docker://" + image},
+	}
+	if err := runBuildpacks(context.Background(), cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := os.ReadFile(filepath.Join(target, "order.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	goIdx := strings.Index(string(order), `id = "example/go"`)
+	extraIdx := strings.Index(string(order), `id = "example/extra"`)
+	if goIdx < 0 || extraIdx < goIdx || strings.Contains(string(order), "builder-order") {
+		t.Fatalf("unexpected order:\n%s", order)
+	}
+	if _, err := os.Stat(filepath.Join(target, "buildpacks", "example_extra", "2.0.0", "bin", "build")); err != nil {
+		t.Fatalf("expected the fetched buildpack to be extracted: %v", err)
+	}
+
+	// Unknown buildpacks are neither in the builder nor valid images
+	cfg.refs = []string{"urn:cnb:builder:example/missing"}
+	if err := runBuildpacks(context.Background(), cfg); err == nil {
+		t.Fatal("expected an error for a buildpack missing from the builder")
+	}
+}
+
+// fakeBuilder returns a directory laid out like a builder's /cnb directory.
+func fakeBuilder(t *testing.T) string {
+	t.Helper()
+	cnb := t.TempDir()
+	bp := filepath.Join(cnb, "buildpacks", "example_go", "1.0.0")
+	if err := os.MkdirAll(bp, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bp, "buildpack.toml"), []byte(`[buildpack]
+id = "example/go"
+version = "1.0.0"
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cnb, "order.toml"), []byte(`# builder-order
+[[order]]
+  [[order.group]]
+    id = "example/go"
+    version = "1.0.0"
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return cnb
+}
+
+// pushBuildpackage pushes a minimal buildpackage image to a test registry,
+// returning its reference.
+func pushBuildpackage(t *testing.T, id, version string) string {
+	t.Helper()
+	srv := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(srv.Close)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	dir := "/cnb/buildpacks/" + strings.ReplaceAll(id, "/", "_") + "/" + version + "/"
+	for name, content := range map[string]string{
+		"buildpack.toml": "[buildpack]\nid = \"" + id + "\"\nversion = \"" + version + "\"\n",
+		"bin/build":      "#!/bin/sh\n",
+		"bin/detect":     "#!/bin/sh\n",
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: dir + name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg = cfg.DeepCopy()
+	cfg.OS, cfg.Architecture = "linux", "amd64"
+	cfg.Config.Labels = map[string]string{
+		buildpackageLabel: `{"id":"` + id + `","version":"` + version + `"}`,
+	}
+	if img, err = mutate.ConfigFile(img, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	ref := strings.TrimPrefix(srv.URL, "http://") + "/buildpacks/extra:latest"
+	tag, err := name.NewTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = remote.Write(tag, img); err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
@@ -0,0 +1,137 @@
+//go:build exclude_graphdriver_btrfs || !cgo
+// +build exclude_graphdriver_btrfs !cgo
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"knative.dev/func/pkg/builders"
+	fn "knative.dev/func/pkg/functions"
+	"knative.dev/func/pkg/oci"
+)
+
+func hostBuild(ctx context.Context) error {
+	cmd := newHostBuildCmd()
+	err := cmd.ExecuteContext(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot host build: %w", err)
+	}
+	return nil
+}
+
+type hostBuildConfig struct {
+	path       string
+	image      string
+	registry   string
+	digestFile string
+	insecure   bool
+}
+
+func newHostBuildCmd() *cobra.Command {
+	var config hostBuildConfig
+
+	buildCmd := &cobra.Command{
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHostBuild(cmd.Context(), config)
+		},
+	}
+	buildCmd.Flags().StringVar(&config.path, "path", ".", "")
+	buildCmd.Flags().StringVar(&config.image, "image", "", "")
+	buildCmd.Flags().StringVar(&config.registry, "registry", "", "")
+	buildCmd.Flags().StringVar(&config.digestFile, "digest-file", "", "")
+	buildCmd.Flags().BoolVar(&config.insecure, "registry-insecure", false, "")
+
+	return buildCmd
+}
+
+// runHostBuild builds the function using the same OCI builder and pusher as
+// a local build with the "host" builder, producing a multi-arch image.
+func runHostBuild(ctx context.Context, c hostBuildConfig) error {
+	// The builder expects an absolute function root.
+	root, err := filepath.Abs(c.path)
+	if err != nil {
+		return fmt.Errorf("cannot determine function path: %w", err)
+	}
+
+	f, err := fn.NewFunction(root)
+	if err != nil {
+		return fmt.Errorf("cannot load function: %w", err)
+	}
+	if !f.Initialized() {
+		return fn.NewErrNotInitialized(f.Root)
+	}
+	if c.registry != "" {
+		f.Registry = c.registry
+	}
+	f.Build.Image = c.image
+	if f.Build.Image == "" {
+		if f.Build.Image, err = f.ImageName(); err != nil {
+			return err
+		}
+	}
+
+	if err = oci.NewBuilder(builders.Host, true).Build(ctx, f, nil); err != nil {
+		return fmt.Errorf("cannot build: %w", err)
+	}
+
+	digest, err := oci.NewPusher(c.insecure, false, true).Push(ctx, f)
+	if err != nil {
+		return fmt.Errorf("cannot push: %w", err)
+	}
+
+	if c.digestFile != "" {
+		if err = os.WriteFile(c.digestFile, []byte(digest), 0644); err != nil {
+			return fmt.Errorf("cannot write image digest: %w", err)
+		}
+	}
+	return nil
+}
+
+// install copies this executable into the directory given as the first
+// argument, linking it as each of the remaining names.  This allows func-util
+// commands to be run from within other images, such as a language toolchain.
+func install(_ context.Context) error {
+	if len(os.Args) < 3 {
+		return fmt.Errorf("expected a target directory and at least one command name")
+	}
+	dir := os.Args[1]
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("cannot determine executable: %w", err)
+	}
+	src, err := os.Open(exe)
+	if err != nil {
+		return fmt.Errorf("cannot open executable: %w", err)
+	}
+	defer src.Close()
+
+	target := filepath.Join(dir, "func-util")
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return fmt.Errorf("cannot create %q: %w", target, err)
+	}
+	if _, err = io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return fmt.Errorf("cannot copy executable: %w", err)
+	}
+	if err = dst.Close(); err != nil {
+		return fmt.Errorf("cannot copy executable: %w", err)
+	}
+
+	for _, name := range os.Args[2:] {
+		link := filepath.Join(dir, name)
+		_ = os.Remove(link)
+		if err = os.Symlink("func-util", link); err != nil {
+			return fmt.Errorf("cannot link %q: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"slices"
 	"syscall"
+	"time"
 
 	"golang.org/x/sys/unix"
 
@@ -19,6 +20,7 @@ import (
 	"k8s.io/klog/v2"
 
 	"knative.dev/func/pkg/builders/s2i"
+	"knative.dev/func/pkg/filesync"
 	fn "knative.dev/func/pkg/functions"
 	"knative.dev/func/pkg/k8s"
 	"knative.dev/func/pkg/knative"
@@ -52,8 +54,18 @@ func main() {
 		cmd = socat
 	case "sh":
 		cmd = sh
+	case "sync":
+		cmd = syncSources
 	case "s2i-generate":
 		cmd = s2iGenerate
+	case "host-build":
+		cmd = hostBuild
+	case "buildpacks":
+		cmd = buildpacks
+	case "install":
+		cmd = install
+	case "report":
+		cmd = report
 	}
 
 	err := cmd(ctx)
@@ -99,7 +111,8 @@ func scaffold(ctx context.Context) error {
 	appRoot := filepath.Join(f.Root, ".s2i", "builds", "last")
 	_ = os.RemoveAll(appRoot)
 
-	err = scaffolding.Write(appRoot, f.Root, f.Runtime, f.Invoke, embeddedRepo.FS())
+	err = scaffolding.Write(appRoot, f.Root, f.Runtime, f.Invoke, embeddedRepo.FS(),
+		scaffolding.WithHealthEndpoints(f.Deploy.HealthEndpoints.Readiness, f.Deploy.HealthEndpoints.Liveness))
 	if err != nil {
 		return fmt.Errorf("cannot write the scaffolding: %w", err)
 	}
@@ -134,9 +147,10 @@ func s2iCmd(ctx context.Context) error {
 
 func deploy(ctx context.Context) error {
 	var err error
+	build := newBuildStatus()
 	deployer := knative.NewDeployer(
 		knative.WithDeployerVerbose(true),
-		knative.WithDeployerDecorator(deployDecorator{}))
+		knative.WithDeployerDecorator(deployDecorator{build: build}))
 
 	var root string
 	if len(os.Args) > 1 {
@@ -161,18 +175,70 @@ func deploy(ctx context.Context) error {
 
 	res, err := deployer.Deploy(ctx, f)
 	if err != nil {
+		build.Reason = knative.ReasonDeployFailed
+		build.Message = fmt.Sprintf("cannot deploy the function: %v", err)
+		namespace := f.Namespace
+		if namespace == "" {
+			namespace = f.Deploy.Namespace
+		}
+		recordBuild(ctx, f.Name, namespace, build)
 		return fmt.Errorf("cannont deploy the function: %w", err)
 	}
 
+	build.Succeeded = true
+	build.Reason = knative.ReasonDeployed
+	build.Message = fmt.Sprintf("function deployed with image %v", f.Deploy.Image)
+	recordBuild(ctx, f.Name, res.Namespace, build)
+
 	fmt.Printf("function has been deployed\n%+v\n", res)
 	return nil
 }
 
+// report records a failed on-cluster build of the function of the given
+// name, which did not get to be deployed.
+func report(ctx context.Context) error {
+	if len(os.Args) != 2 {
+		return fmt.Errorf("expected exactly one positional argument (function name)")
+	}
+
+	build := newBuildStatus()
+	build.Reason = knative.ReasonBuildFailed
+	build.Message = "the function could not be built"
+	if build.PipelineRun != "" {
+		build.Message = fmt.Sprintf("PipelineRun %v failed, the function could not be built", build.PipelineRun)
+	}
+	recordBuild(ctx, os.Args[1], "", build)
+	return nil
+}
+
+// newBuildStatus returns the status of the on-cluster build, which is
+// identified by the environment variables set by the Tasks.
+func newBuildStatus() fn.BuildStatus {
+	return fn.BuildStatus{
+		PipelineRun: os.Getenv("FUNC_PIPELINE_RUN"),
+		GitRevision: os.Getenv("FUNC_GIT_REVISION"),
+		Time:        time.Now(),
+	}
+}
+
+// recordBuild records the build as an Event on the function.  Recording is
+// best effort, for instance the service account of the pipeline may not be
+// permitted to create Events, and so it never fails the build.
+func recordBuild(ctx context.Context, name, namespace string, build fn.BuildStatus) {
+	if err := knative.RecordBuild(ctx, name, namespace, build); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "WARNING: %s\n", err)
+	}
+}
+
 type deployDecorator struct {
 	oshDec k8s.OpenshiftMetadataDecorator
+	build  fn.BuildStatus
 }
 
 func (d deployDecorator) UpdateAnnotations(function fn.Function, annotations map[string]string) map[string]string {
+	for k, v := range knative.BuildAnnotations(d.build) {
+		annotations[k] = v
+	}
 	if k8s.IsOpenShift() {
 		return d.oshDec.UpdateAnnotations(function, annotations)
 	}
@@ -200,3 +266,15 @@ func sh(ctx context.Context) error {
 
 	return tar.Extract(os.Stdin, wd)
 }
+
+// syncSources updates the directory given as the only argument with the
+// sources sent incrementally over stdin, see filesync.Receive.
+func syncSources(_ context.Context) error {
+	if len(os.Args) != 2 {
+		return fmt.Errorf("expected exactly one positional argument (directory)")
+	}
+
+	unix.Umask(0)
+
+	return filesync.Receive(os.Args[1], os.Stdin, os.Stdout)
+}
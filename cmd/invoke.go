@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/ory/viper"
@@ -23,9 +29,10 @@ NAME
 	{{rootCmdUse}} invoke - test a function by invoking it with test data
 
 SYNOPSIS
-	{{rootCmdUse}} invoke [-t|--target] [-f|--format]
+	{{rootCmdUse}} invoke [-t|--target] [--environment] [-f|--format]
 	             [--id] [--source] [--type] [--data] [--file] [--content-type]
 	             [-s|--save] [-p|--path] [-i|--insecure] [-c|--confirm] [-v|--verbose]
+	             [--requests] [--concurrency] [--duration] [-o|--output]
 
 DESCRIPTION
 	Invokes the function by sending a test request to the currently running
@@ -52,6 +59,9 @@ DESCRIPTION
 	    {{rootCmdUse}} invoke --target=remote
 	  To target an arbitrary endpoint, provide a URL:
 	    {{rootCmdUse}} invoke --target=https://myfunction.example.com
+	  To target the function deployed to a named environment (see func.yaml),
+	  use --environment in place of --target:
+	    {{rootCmdUse}} invoke --environment=staging
 
 	Invocation Data
 	  Providing a filename in the --file flag will base64 encode its contents
@@ -66,6 +76,14 @@ DESCRIPTION
 	  To override this behavior, use the --format (-f) flag.
 	    {{rootCmdUse}} invoke -f=cloudevent -t=http://my-sink.my-cluster
 
+	Load Generation
+	  A quick check of the latency and throughput of a function instance can be
+	  made by sending the invocation repeatedly using the --requests and/or
+	  --duration flags.  Requests are sent by --concurrency parallel workers.
+	  When complete, a summary of the p50/p95/p99 latency, errors and response
+	  status codes is printed.  Use --output=json to print the summary as JSON.
+	    {{rootCmdUse}} invoke --requests=1000 --concurrency=10
+
 EXAMPLES
 
 	o Invoke the default (local or remote) running function with default values
@@ -99,9 +117,15 @@ EXAMPLES
 	o Allow insecure server connections when using SSL
 		$ {{rootCmdUse}} invoke --insecure
 
+	o Send 1000 requests using 10 concurrent workers and print latencies
+		$ {{rootCmdUse}} invoke --requests=1000 --concurrency=10
+
+	o Send requests to the remote function for 30 seconds, printing JSON results
+		$ {{rootCmdUse}} invoke --target=remote --duration=30s --output=json
+
 `,
 		SuggestFor: []string{"emit", "emti", "send", "emit", "exec", "nivoke", "onvoke", "unvoke", "knvoke", "imvoke", "ihvoke", "ibvoke"},
-		PreRunE:    bindEnv("path", "format", "target", "id", "source", "type", "data", "content-type", "file", "insecure", "confirm", "verbose"),
+		PreRunE:    bindEnv("path", "format", "target", "id", "source", "type", "data", "content-type", "file", "insecure", "confirm", "verbose", "requests", "concurrency", "duration", "output", "environment"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runInvoke(cmd, args, newClient)
 		},
@@ -123,7 +147,12 @@ EXAMPLES
 	cmd.Flags().StringP("data", "", fn.DefaultInvokeData, "Data to send in the request. ($FUNC_DATA)")
 	cmd.Flags().StringP("file", "", "", "Path to a file to use as data. Overrides --data flag and should be sent with a correct --content-type. ($FUNC_FILE)")
 	cmd.Flags().BoolP("insecure", "i", false, "Allow insecure server connections when using SSL. ($FUNC_INSECURE)")
+	cmd.Flags().Int("requests", 0, "Number of requests to send when generating load.  Enables load generation mode. ($FUNC_REQUESTS)")
+	cmd.Flags().Int("concurrency", fn.DefaultLoadConcurrency, "Number of concurrent workers sending requests when generating load. ($FUNC_CONCURRENCY)")
+	cmd.Flags().Duration("duration", 0, "Maximum duration of load generation, e.g. '30s'.  Enables load generation mode. ($FUNC_DURATION)")
+	cmd.Flags().StringP("output", "o", "human", "Output format of the load generation results (human|json) ($FUNC_OUTPUT)")
 	addConfirmFlag(cmd, cfg.Confirm)
+	addEnvironmentFlag(cmd)
 	addPathFlag(cmd)
 	addVerboseFlag(cmd, cfg.Verbose)
 
@@ -155,6 +184,20 @@ func runInvoke(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 	client, done := newClient(ClientConfig{Verbose: cfg.Verbose, InsecureSkipVerify: cfg.Insecure})
 	defer done()
 
+	// Named environment
+	// The function deployed to the environment is invoked at its route.
+	if cfg.Environment != "" {
+		instance, err := client.Instances().Named(cmd.Context(), f, cfg.Environment)
+		if errors.Is(err, fn.ErrEnvironmentNotFound) {
+			return fmt.Errorf("environment %q is not defined by the function", cfg.Environment)
+		} else if errors.Is(err, fn.ErrNotRunning) {
+			return fmt.Errorf("function is not deployed to the %q environment", cfg.Environment)
+		} else if err != nil {
+			return err
+		}
+		cfg.Target = instance.Route
+	}
+
 	// Message to send the running function built from parameters gathered
 	// from the user (or defaults)
 	m := fn.InvokeMessage{
@@ -175,6 +218,11 @@ func runInvoke(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 		m.Data = content
 	}
 
+	// Load generation mode
+	if cfg.Requests > 0 || cfg.Duration > 0 {
+		return runInvokeLoad(cmd, client, cfg, m)
+	}
+
 	// Invoke
 	metadata, body, err := client.Invoke(cmd.Context(), cfg.Path, cfg.Target, m)
 	if err != nil {
@@ -210,9 +258,80 @@ func runInvoke(cmd *cobra.Command, _ []string, newClient ClientFactory) (err err
 	return
 }
 
+// runInvokeLoad sends the message repeatedly to the function and prints a
+// summary of the results in the requested output format.
+func runInvokeLoad(cmd *cobra.Command, client *fn.Client, cfg invokeConfig, m fn.InvokeMessage) error {
+	if cfg.Requests < 0 {
+		return fmt.Errorf("--requests must be a positive number")
+	}
+	if cfg.Concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if cfg.Output != string(Human) && cfg.Output != JSON {
+		return fmt.Errorf("output format not supported for load generation: %v", cfg.Output)
+	}
+
+	result, err := client.InvokeLoad(cmd.Context(), cfg.Path, cfg.Target, m, fn.LoadOptions{
+		Requests:    cfg.Requests,
+		Concurrency: cfg.Concurrency,
+		Duration:    cfg.Duration,
+	})
+	if err != nil {
+		return err
+	}
+
+	if cfg.Output == JSON {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	return writeLoadResult(cmd.OutOrStdout(), result)
+}
+
+// writeLoadResult in human-readable form.
+func writeLoadResult(w io.Writer, r fn.LoadResult) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Target:\t%v (%v)\n", r.Target, r.Format)
+	fmt.Fprintf(tw, "Requests:\t%v (%v errors)\n", r.Requests, r.Errors)
+	fmt.Fprintf(tw, "Concurrency:\t%v\n", r.Concurrency)
+	fmt.Fprintf(tw, "Elapsed:\t%.2fs\n", r.Elapsed)
+	fmt.Fprintf(tw, "Throughput:\t%.2f req/s\n", r.Throughput)
+	fmt.Fprintln(tw, "Latency:")
+	fmt.Fprintf(tw, "  min\t%.2fms\n", r.Latency.Min)
+	fmt.Fprintf(tw, "  mean\t%.2fms\n", r.Latency.Mean)
+	fmt.Fprintf(tw, "  p50\t%.2fms\n", r.Latency.P50)
+	fmt.Fprintf(tw, "  p95\t%.2fms\n", r.Latency.P95)
+	fmt.Fprintf(tw, "  p99\t%.2fms\n", r.Latency.P99)
+	fmt.Fprintf(tw, "  max\t%.2fms\n", r.Latency.Max)
+	if len(r.StatusCodes) > 0 {
+		fmt.Fprintln(tw, "Status codes:")
+		codes := make([]int, 0, len(r.StatusCodes))
+		for code := range r.StatusCodes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			fmt.Fprintf(tw, "  %v\t%v\n", code, r.StatusCodes[code])
+		}
+	}
+	if len(r.ErrorCounts) > 0 {
+		fmt.Fprintln(tw, "Errors:")
+		msgs := make([]string, 0, len(r.ErrorCounts))
+		for msg := range r.ErrorCounts {
+			msgs = append(msgs, msg)
+		}
+		sort.Strings(msgs)
+		for _, msg := range msgs {
+			fmt.Fprintf(tw, "  %v\t%v\n", r.ErrorCounts[msg], msg)
+		}
+	}
+	return tw.Flush()
+}
+
 type invokeConfig struct {
 	Path        string
 	Target      string
+	Environment string
 	Format      string
 	ID          string
 	Source      string
@@ -223,12 +342,17 @@ type invokeConfig struct {
 	Confirm     bool
 	Verbose     bool
 	Insecure    bool
+	Requests    int
+	Concurrency int
+	Duration    time.Duration
+	Output      string
 }
 
 func newInvokeConfig() (cfg invokeConfig, err error) {
 	cfg = invokeConfig{
 		Path:        viper.GetString("path"),
 		Target:      viper.GetString("target"),
+		Environment: viper.GetString("environment"),
 		Format:      viper.GetString("format"),
 		ID:          viper.GetString("id"),
 		Source:      viper.GetString("source"),
@@ -239,6 +363,14 @@ func newInvokeConfig() (cfg invokeConfig, err error) {
 		Confirm:     viper.GetBool("confirm"),
 		Verbose:     viper.GetBool("verbose"),
 		Insecure:    viper.GetBool("insecure"),
+		Requests:    viper.GetInt("requests"),
+		Concurrency: viper.GetInt("concurrency"),
+		Duration:    viper.GetDuration("duration"),
+		Output:      viper.GetString("output"),
+	}
+
+	if cfg.Target != "" && cfg.Environment != "" {
+		return cfg, fmt.Errorf("only one of --target and --environment should be provided")
 	}
 
 	// If file was passed, read it in as data
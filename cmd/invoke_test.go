@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -78,3 +80,90 @@ func TestInvoke(t *testing.T) {
 		t.Fatal("function was not invoked")
 	}
 }
+
+// TestInvoke_Environment ensures that --environment invokes the function
+// deployed to the named environment.
+func TestInvoke_Environment(t *testing.T) {
+	root := FromTempDirectory(t)
+
+	var invoked int32
+	s := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		atomic.StoreInt32(&invoked, 1)
+		_, _ = res.Write([]byte("invoked"))
+	}))
+	t.Cleanup(s.Close)
+
+	f, err := fn.New().Init(fn.Function{Runtime: "go", Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Environments = map[string]fn.Environment{
+		"staging": {Namespace: "staging"},
+		"prod":    {Namespace: "prod"},
+	}
+	f.Deploy.Environments = map[string]fn.DeployedEnvironment{
+		"staging": {Namespace: "staging"},
+	}
+	if err = f.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	describer := mock.NewDescriber()
+	describer.DescribeFn = func(_ context.Context, name, namespace string) (fn.Instance, error) {
+		if namespace != "staging" {
+			return fn.Instance{}, fmt.Errorf("unexpected namespace %q", namespace)
+		}
+		return fn.Instance{Route: s.URL}, nil
+	}
+	newCmd := func(args ...string) error {
+		cmd := NewInvokeCmd(NewTestClient(fn.WithDescriber(describer)))
+		cmd.SetArgs(args)
+		return cmd.Execute()
+	}
+
+	if err := newCmd("--environment", "staging"); err != nil {
+		t.Fatal(err)
+	}
+	if atomic.LoadInt32(&invoked) != 1 {
+		t.Fatal("function was not invoked")
+	}
+
+	// Defined but not deployed
+	if err := newCmd("--environment", "prod"); err == nil {
+		t.Fatal("expected invoking an environment not deployed to fail")
+	}
+	// Not defined
+	if err := newCmd("--environment", "dev"); err == nil {
+		t.Fatal("expected invoking an undefined environment to fail")
+	}
+	// Environments are not targets
+	if err := newCmd("--target", "staging", "--environment", "staging"); err == nil {
+		t.Fatal("expected --target and --environment to be mutually exclusive")
+	}
+}
+
+// TestInvoke_WriteLoadResult ensures the summary of a load test lists the
+// distinct errors in a stable order.
+func TestInvoke_WriteLoadResult(t *testing.T) {
+	r := fn.LoadResult{
+		Requests:    6,
+		Errors:      6,
+		StatusCodes: map[int]int{},
+		ErrorCounts: map[string]int{
+			"dial tcp: connection refused":       3,
+			"context deadline exceeded":          2,
+			"read tcp: connection reset by peer": 1,
+		},
+	}
+	var b strings.Builder
+	if err := writeLoadResult(&b, r); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	i := strings.Index(out, "context deadline exceeded")
+	j := strings.Index(out, "dial tcp: connection refused")
+	k := strings.Index(out, "read tcp: connection reset by peer")
+	if i < 0 || j < 0 || k < 0 || !(i < j && j < k) {
+		t.Fatalf("expected the errors to be listed in order, got:\n%s", out)
+	}
+}
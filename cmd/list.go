@@ -24,6 +24,9 @@ func NewListCmd(newClient ClientFactory) *cobra.Command {
 		Long: `List deployed functions
 
 Lists deployed functions.
+
+With --local, lists instead the functions of the workspace rooted at --path
+(see func-workspace.yaml), whether deployed or not.
 `,
 		Example: `
 # List all functions in the current namespace with human readable output
@@ -34,10 +37,13 @@ Lists deployed functions.
 
 # List all functions in all namespaces with JSON output
 {{rootCmdUse}} list --all-namespaces --output json
+
+# List the functions of the workspace in the current directory
+{{rootCmdUse}} list --local
 `,
 		SuggestFor: []string{"lsit"},
 		Aliases:    []string{"ls"},
-		PreRunE:    bindEnv("all-namespaces", "output", "namespace", "verbose"),
+		PreRunE:    bindEnv("all-namespaces", "output", "namespace", "verbose", "local", "path"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runList(cmd, args, newClient)
 		},
@@ -70,6 +76,8 @@ Lists deployed functions.
 	cmd.Flags().BoolP("all-namespaces", "A", false, "List functions in all namespaces. If set, the --namespace flag is ignored.")
 	cmd.Flags().StringP("namespace", "n", defaultNamespace(fn.Function{}, false), "The namespace for which to list functions. ($FUNC_NAMESPACE)")
 	cmd.Flags().StringP("output", "o", "human", "Output format (human|plain|json|xml|yaml) ($FUNC_OUTPUT)")
+	cmd.Flags().Bool("local", false, "List the functions of the workspace at --path rather than deployed functions. ($FUNC_LOCAL)")
+	addPathFlag(cmd)
 	addVerboseFlag(cmd, cfg.Verbose)
 
 	if err := cmd.RegisterFlagCompletionFunc("output", CompleteOutputFormatList); err != nil {
@@ -84,6 +92,9 @@ func runList(cmd *cobra.Command, _ []string, newClient ClientFactory) (err error
 	if err != nil {
 		return err
 	}
+	if cfg.Local {
+		return runListLocal(cmd, cfg.Path, cfg.Output)
+	}
 
 	client, done := newClient(ClientConfig{Verbose: cfg.Verbose})
 	defer done()
@@ -114,6 +125,8 @@ type listConfig struct {
 	Namespace string
 	Output    string
 	Verbose   bool
+	Local     bool
+	Path      string
 }
 
 func newListConfig(cmd *cobra.Command) (cfg listConfig, err error) {
@@ -121,6 +134,8 @@ func newListConfig(cmd *cobra.Command) (cfg listConfig, err error) {
 		Namespace: viper.GetString("namespace"),
 		Output:    viper.GetString("output"),
 		Verbose:   viper.GetBool("verbose"),
+		Local:     viper.GetBool("local"),
+		Path:      viper.GetString("path"),
 	}
 	// If --all-namespaces, zero out any value for namespace (such as)
 	// "all" to the lister.
@@ -0,0 +1,464 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ory/viper"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"knative.dev/func/pkg/config"
+	fn "knative.dev/func/pkg/functions"
+	"knative.dev/func/pkg/pipelines/ci"
+)
+
+func NewPipelinesCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pipelines",
+		Short: "Inspect and generate the pipelines of a function",
+		Long: `Inspect and generate the pipelines of a function
+
+Shows the history, status and logs of the Tekton PipelineRuns which built and
+deployed the function in the current directory or from the directory specified
+with --path, such as those started by "deploy --remote" or by a Git push.
+
+Generates the pipeline resources of the function as files, to be applied by
+GitOps tooling, or workflows for hosted CI services which build and deploy
+the function with func.
+`,
+
+		SuggestFor: []string{"pipeline", "pipelnies", "runs"},
+		Aliases:    []string{"pipeline"},
+	}
+
+	cmd.AddCommand(NewPipelinesListCmd(newClient))
+	cmd.AddCommand(NewPipelinesDescribeCmd(newClient))
+	cmd.AddCommand(NewPipelinesLogsCmd(newClient))
+	cmd.AddCommand(NewPipelinesGenerateCmd(newClient))
+
+	return cmd
+}
+
+func NewPipelinesListCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List pipeline runs of a function",
+		Long: `List pipeline runs of a function
+
+Lists the pipeline runs of the function, newest first, with their status,
+start time and duration.
+`,
+		Example: `
+# List the pipeline runs of the function in the current directory
+{{rootCmdUse}} pipelines list
+
+# List the pipeline runs with yaml output
+{{rootCmdUse}} pipelines list --output yaml
+`,
+		Aliases: []string{"ls"},
+		PreRunE: bindEnv("output", "namespace", "path", "verbose"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPipelinesList(cmd, newClient)
+		},
+	}
+	addPipelinesFlags(cmd)
+	cmd.Flags().StringP("output", "o", "human", "Output format (human|plain|json|xml|yaml) ($FUNC_OUTPUT)")
+	if err := cmd.RegisterFlagCompletionFunc("output", CompleteOutputFormatList); err != nil {
+		fmt.Println("internal: error while calling RegisterFlagCompletionFunc: ", err)
+	}
+	return cmd
+}
+
+func NewPipelinesDescribeCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe [run]",
+		Short: "Describe a pipeline run of a function",
+		Long: `Describe a pipeline run of a function
+
+Prints the status and duration of a pipeline run and of each of its tasks.
+If no run is named, the newest pipeline run of the function is described.
+`,
+		Example: `
+# Describe the newest pipeline run of the function in the current directory
+{{rootCmdUse}} pipelines describe
+
+# Describe a specific pipeline run with json output
+{{rootCmdUse}} pipelines describe myfunc-pack-upload-pipeline-run-x7k2p --output json
+`,
+		Aliases: []string{"desc"},
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: bindEnv("output", "namespace", "path", "verbose"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPipelinesDescribe(cmd, args, newClient)
+		},
+	}
+	addPipelinesFlags(cmd)
+	cmd.Flags().StringP("output", "o", "human", "Output format (human|plain|json|xml|yaml) ($FUNC_OUTPUT)")
+	if err := cmd.RegisterFlagCompletionFunc("output", CompleteOutputFormatList); err != nil {
+		fmt.Println("internal: error while calling RegisterFlagCompletionFunc: ", err)
+	}
+	return cmd
+}
+
+func NewPipelinesLogsCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs [run]",
+		Short: "Print logs of a pipeline run of a function",
+		Long: `Print logs of a pipeline run of a function
+
+Prints the logs of each step of the tasks of a pipeline run, or of only the
+task given with --task.  If no run is named, the newest pipeline run of the
+function is used.  For a failed run without --task, only the log of the
+failing step is printed.
+`,
+		Example: `
+# Print the logs of the newest pipeline run of the function
+{{rootCmdUse}} pipelines logs
+
+# Print the logs of the build task of a specific pipeline run
+{{rootCmdUse}} pipelines logs myfunc-pack-upload-pipeline-run-x7k2p --task build
+`,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: bindEnv("task", "namespace", "path", "verbose"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPipelinesLogs(cmd, args, newClient)
+		},
+	}
+	addPipelinesFlags(cmd)
+	cmd.Flags().StringP("task", "t", "", "Name of the pipeline task to print logs of (e.g. build, deploy). ($FUNC_TASK)")
+	return cmd
+}
+
+func NewPipelinesGenerateCmd(newClient ClientFactory) *cobra.Command {
+	cfg, err := config.NewDefault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error loading config at '%v'. %v\n", config.File(), err)
+	}
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate pipeline resources or CI workflows for a function",
+		Long: `Generate pipeline resources or CI workflows for a function
+
+Writes the Tekton Tasks, Pipeline, PipelineRun and PersistentVolumeClaim which
+build and deploy the function on the cluster as files, rather than creating
+them on the cluster as "deploy --remote" does.  The files can be committed and
+applied by GitOps tooling such as Argo CD or Flux, without Pipelines as Code.
+The generated Pipeline fetches the function's sources from its Git repository,
+which therefore must be configured.  The Secret with the registry credentials
+referenced by the PipelineRun is not generated.
+
+With --format github or --format gitlab a workflow for GitHub Actions or
+GitLab CI is written instead, which builds and deploys the function with
+"func build" and "func deploy" on each push to the function's Git revision.
+
+The files are written into the directory given with --output, which defaults
+to "pipelines" for Tekton resources, and for GitHub Actions and GitLab CI to
+".github/workflows" and the root of the Git repository containing the
+function respectively, where they are read from.  The workflows run in the
+function's directory within the repository.  An existing workflow file is
+only overwritten with --force.
+`,
+		Example: `
+# Write the Tekton resources of the function into ./pipelines
+{{rootCmdUse}} pipelines generate
+
+# Write the Tekton resources into a GitOps repository
+{{rootCmdUse}} pipelines generate --output ../gitops/functions/myfunc
+
+# Write a GitHub Actions workflow
+{{rootCmdUse}} pipelines generate --format github
+`,
+		SuggestFor: []string{"gen", "export"},
+		PreRunE:    bindEnv("format", "output", "force", "registry", "path", "verbose"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPipelinesGenerate(cmd, newClient)
+		},
+	}
+	cmd.Flags().String("format", "tekton", fmt.Sprintf("Format of the generated files (tekton|%s) ($FUNC_FORMAT)", strings.Join(ci.Providers, "|")))
+	cmd.Flags().StringP("output", "o", "", "Directory to write the files into. Default depends on --format. ($FUNC_OUTPUT)")
+	cmd.Flags().Bool("force", false, "Overwrite an existing CI workflow file. ($FUNC_FORCE)")
+	cmd.Flags().StringP("registry", "r", cfg.Registry,
+		"Container registry + registry namespace. (ex 'ghcr.io/myuser').  The full image name is automatically determined using this along with function name. ($FUNC_REGISTRY)")
+	addPathFlag(cmd)
+	addVerboseFlag(cmd, cfg.Verbose)
+	return cmd
+}
+
+func addPipelinesFlags(cmd *cobra.Command) {
+	cfg, err := config.NewDefault()
+	if err != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "error loading config at '%v'. %v\n", config.File(), err)
+	}
+	cmd.Flags().StringP("namespace", "n", "", "The namespace of the pipeline runs. Default is the namespace of the function. ($FUNC_NAMESPACE)")
+	addPathFlag(cmd)
+	addVerboseFlag(cmd, cfg.Verbose)
+}
+
+func runPipelinesList(cmd *cobra.Command, newClient ClientFactory) error {
+	cfg, f, err := newPipelinesConfig(nil)
+	if err != nil {
+		return err
+	}
+	client, done := newClient(ClientConfig{Verbose: cfg.Verbose})
+	defer done()
+
+	runs, err := client.PipelineRuns(cmd.Context(), f)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "no pipeline runs found for function %q\n", f.Name)
+		return nil
+	}
+	write(cmd.OutOrStdout(), pipelineRuns(runs), cfg.Output)
+	return nil
+}
+
+func runPipelinesDescribe(cmd *cobra.Command, args []string, newClient ClientFactory) error {
+	cfg, f, err := newPipelinesConfig(args)
+	if err != nil {
+		return err
+	}
+	client, done := newClient(ClientConfig{Verbose: cfg.Verbose})
+	defer done()
+
+	run, err := client.DescribePipelineRun(cmd.Context(), f, cfg.Run)
+	if err != nil {
+		return err
+	}
+	write(cmd.OutOrStdout(), pipelineRun(run), cfg.Output)
+	return nil
+}
+
+func runPipelinesLogs(cmd *cobra.Command, args []string, newClient ClientFactory) error {
+	cfg, f, err := newPipelinesConfig(args)
+	if err != nil {
+		return err
+	}
+	client, done := newClient(ClientConfig{Verbose: cfg.Verbose})
+	defer done()
+
+	logs, err := client.PipelineRunLogs(cmd.Context(), f, cfg.Run, cfg.Task)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(cmd.OutOrStdout(), logs)
+	return err
+}
+
+func runPipelinesGenerate(cmd *cobra.Command, newClient ClientFactory) (err error) {
+	var (
+		format   = viper.GetString("format")
+		output   = viper.GetString("output")
+		force    = viper.GetBool("force")
+		registry = viper.GetString("registry")
+		path     = viper.GetString("path")
+		verbose  = viper.GetBool("verbose")
+	)
+
+	f, err := fn.NewFunction(path)
+	if err != nil {
+		return
+	}
+	if !f.Initialized() {
+		return fn.NewErrNotInitialized(f.Root)
+	}
+	if registry != "" && (f.Registry == "" || cmd.Flags().Changed("registry")) {
+		f.Registry = registry
+	}
+
+	var files []string
+	switch format {
+	case "tekton":
+		if output == "" {
+			output = filepath.Join(f.Root, "pipelines")
+		}
+		client, done := newClient(ClientConfig{Verbose: verbose})
+		defer done()
+		if files, err = client.GeneratePipeline(cmd.Context(), f, output); err != nil {
+			return
+		}
+	case ci.GitHub, ci.GitLab:
+		if output == "" {
+			if output, err = ci.Dir(f, format); err != nil {
+				return
+			}
+		}
+		file, err := ci.Write(f, format, output, force)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+	default:
+		return fmt.Errorf("unknown format %q, supported are: tekton, %s", format, strings.Join(ci.Providers, ", "))
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "Generated files:")
+	for _, file := range files {
+		fmt.Fprintf(cmd.OutOrStdout(), "  %v\n", file)
+	}
+	return
+}
+
+// CLI Configuration (parameters)
+// ------------------------------
+
+type pipelinesConfig struct {
+	Run       string
+	Task      string
+	Namespace string
+	Output    string
+	Path      string
+	Verbose   bool
+}
+
+// newPipelinesConfig returns the config of the pipelines subcommands along
+// with the function whose pipeline runs are inspected.
+func newPipelinesConfig(args []string) (cfg pipelinesConfig, f fn.Function, err error) {
+	cfg = pipelinesConfig{
+		Task:      viper.GetString("task"),
+		Namespace: viper.GetString("namespace"),
+		Output:    viper.GetString("output"),
+		Path:      viper.GetString("path"),
+		Verbose:   viper.GetBool("verbose"),
+	}
+	if len(args) > 0 {
+		cfg.Run = args[0]
+	}
+
+	if f, err = fn.NewFunction(cfg.Path); err != nil {
+		return
+	}
+	if !f.Initialized() {
+		return cfg, f, fn.NewErrNotInitialized(f.Root)
+	}
+	if cfg.Namespace != "" {
+		f.Namespace = cfg.Namespace
+	}
+	return
+}
+
+// Output Formatting (serializers)
+// -------------------------------
+
+type pipelineRuns []fn.PipelineRun
+
+func (runs pipelineRuns) Human(w io.Writer) error {
+	return runs.Plain(w)
+}
+
+func (runs pipelineRuns) Plain(w io.Writer) error {
+	// minwidth, tabwidth, padding, padchar, flags
+	tabWriter := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	defer tabWriter.Flush()
+
+	fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\n", "NAME", "STATUS", "STARTED", "DURATION")
+	for _, r := range runs {
+		fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\n", r.Name, r.Status, since(r.Started), humanDuration(r.Started, r.Duration()))
+	}
+	return nil
+}
+
+func (runs pipelineRuns) JSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(runs)
+}
+
+func (runs pipelineRuns) XML(w io.Writer) error {
+	return xml.NewEncoder(w).Encode(runs)
+}
+
+func (runs pipelineRuns) YAML(w io.Writer) error {
+	return yaml.NewEncoder(w).Encode(runs)
+}
+
+func (runs pipelineRuns) URL(w io.Writer) error {
+	return fmt.Errorf("url output is not supported for pipeline runs")
+}
+
+type pipelineRun fn.PipelineRun
+
+func (r pipelineRun) Human(w io.Writer) error {
+	fmt.Fprintln(w, "Pipeline run:")
+	fmt.Fprintf(w, "  %v\n", r.Name)
+	fmt.Fprintln(w, "Namespace:")
+	fmt.Fprintf(w, "  %v\n", r.Namespace)
+	fmt.Fprintln(w, "Status:")
+	fmt.Fprintf(w, "  %v\n", r.Status)
+	if r.Message != "" {
+		fmt.Fprintln(w, "Message:")
+		fmt.Fprintf(w, "  %v\n", r.Message)
+	}
+	fmt.Fprintln(w, "Started:")
+	fmt.Fprintf(w, "  %v\n", since(r.Started))
+	fmt.Fprintln(w, "Duration:")
+	fmt.Fprintf(w, "  %v\n", humanDuration(r.Started, fn.PipelineRun(r).Duration()))
+
+	if len(r.Tasks) > 0 {
+		fmt.Fprintln(w, "Tasks:")
+		tabWriter := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		for _, t := range r.Tasks {
+			fmt.Fprintf(tabWriter, "  %s\t%s\t%s\n", t.Name, t.Status, humanDuration(t.Started, t.Duration()))
+		}
+		return tabWriter.Flush()
+	}
+	return nil
+}
+
+func (r pipelineRun) Plain(w io.Writer) error {
+	fmt.Fprintf(w, "Name %v\n", r.Name)
+	fmt.Fprintf(w, "Namespace %v\n", r.Namespace)
+	fmt.Fprintf(w, "Status %v\n", r.Status)
+	if !r.Started.IsZero() {
+		fmt.Fprintf(w, "Started %v\n", r.Started.Format(time.RFC3339))
+	}
+	if !r.Completed.IsZero() {
+		fmt.Fprintf(w, "Completed %v\n", r.Completed.Format(time.RFC3339))
+	}
+	for _, t := range r.Tasks {
+		fmt.Fprintf(w, "Task %v %v %v\n", t.Name, t.Status, t.Duration().Round(time.Second))
+	}
+	return nil
+}
+
+func (r pipelineRun) JSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+func (r pipelineRun) XML(w io.Writer) error {
+	return xml.NewEncoder(w).Encode(r)
+}
+
+func (r pipelineRun) YAML(w io.Writer) error {
+	return yaml.NewEncoder(w).Encode(r)
+}
+
+func (r pipelineRun) URL(w io.Writer) error {
+	return fmt.Errorf("url output is not supported for pipeline runs")
+}
+
+// since returns how long ago the given time was in human terms.
+func since(t time.Time) string {
+	if t.IsZero() {
+		return "---"
+	}
+	return duration.HumanDuration(time.Since(t)) + " ago"
+}
+
+// humanDuration returns the duration of something started at the given time
+// in human terms.
+func humanDuration(started time.Time, d time.Duration) string {
+	if started.IsZero() {
+		return "---"
+	}
+	return duration.HumanDuration(d)
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	fn "knative.dev/func/pkg/functions"
+	"knative.dev/func/pkg/mock"
+	"knative.dev/func/pkg/pipelines/ci"
+	. "knative.dev/func/pkg/testing"
+)
+
+// TestPipelines_List ensures that the pipeline runs of the function are
+// listed, and that the namespace flag overrides that of the function.
+func TestPipelines_List(t *testing.T) {
+	root := FromTempDirectory(t)
+	if _, err := fn.New().Init(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}); err != nil {
+		t.Fatal(err)
+	}
+
+	started := time.Now().Add(-5 * time.Minute)
+	pipelines := mock.NewPipelinesProvider()
+	pipelines.ListRunsFn = func(f fn.Function) ([]fn.PipelineRun, error) {
+		if f.Namespace != "ns" {
+			t.Fatalf("expected namespace 'ns', got %q", f.Namespace)
+		}
+		return []fn.PipelineRun{{
+			Name:      "run-1",
+			Status:    "Succeeded",
+			Started:   started,
+			Completed: started.Add(90 * time.Second),
+		}}, nil
+	}
+
+	cmd := NewPipelinesCmd(NewTestClient(fn.WithPipelinesProvider(pipelines)))
+	out := bytes.Buffer{}
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"list", "--namespace", "ns"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !pipelines.ListRunsInvoked {
+		t.Fatal("pipelines provider was not asked to list runs")
+	}
+	for _, s := range []string{"NAME", "run-1", "Succeeded", "5m", "90s"} {
+		if !strings.Contains(out.String(), s) {
+			t.Fatalf("expected output to contain %q, got:\n%s", s, out.String())
+		}
+	}
+}
+
+// TestPipelines_Describe ensures that the named run is described along with
+// its tasks.
+func TestPipelines_Describe(t *testing.T) {
+	root := FromTempDirectory(t)
+	if _, err := fn.New().Init(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}); err != nil {
+		t.Fatal(err)
+	}
+
+	pipelines := mock.NewPipelinesProvider()
+	pipelines.DescribeRunFn = func(f fn.Function, name string) (fn.PipelineRun, error) {
+		if name != "run-1" {
+			t.Fatalf("expected run 'run-1', got %q", name)
+		}
+		return fn.PipelineRun{
+			Name:    name,
+			Status:  "Failed",
+			Message: "build failed",
+			Tasks:   []fn.PipelineTaskRun{{Name: "build", Status: "Failed"}},
+		}, nil
+	}
+
+	cmd := NewPipelinesCmd(NewTestClient(fn.WithPipelinesProvider(pipelines)))
+	out := bytes.Buffer{}
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"describe", "run-1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"run-1", "build failed", "Tasks:", "build"} {
+		if !strings.Contains(out.String(), s) {
+			t.Fatalf("expected output to contain %q, got:\n%s", s, out.String())
+		}
+	}
+}
+
+// TestPipelines_Logs ensures that the logs of the requested task are printed.
+func TestPipelines_Logs(t *testing.T) {
+	root := FromTempDirectory(t)
+	if _, err := fn.New().Init(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}); err != nil {
+		t.Fatal(err)
+	}
+
+	pipelines := mock.NewPipelinesProvider()
+	pipelines.RunLogsFn = func(f fn.Function, name, task string) (string, error) {
+		if name != "" || task != "deploy" {
+			t.Fatalf("expected newest run and task 'deploy', got %q and %q", name, task)
+		}
+		return "[deploy : deploy] done\n", nil
+	}
+
+	cmd := NewPipelinesCmd(NewTestClient(fn.WithPipelinesProvider(pipelines)))
+	out := bytes.Buffer{}
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"logs", "--task", "deploy"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "[deploy : deploy] done\n" {
+		t.Fatalf("unexpected logs: %q", out.String())
+	}
+}
+
+// TestPipelines_NotInitialized ensures that pipeline runs can only be
+// inspected for an initialized function.
+func TestPipelines_NotInitialized(t *testing.T) {
+	_ = FromTempDirectory(t)
+	pipelines := mock.NewPipelinesProvider()
+	cmd := NewPipelinesCmd(NewTestClient(fn.WithPipelinesProvider(pipelines)))
+	cmd.SetArgs([]string{"list"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected an error for an uninitialized function")
+	}
+	if pipelines.ListRunsInvoked {
+		t.Fatal("pipelines provider was invoked for an uninitialized function")
+	}
+}
+
+// TestPipelines_Generate ensures that the pipeline resources are generated
+// into the given directory, and that CI workflows are written without the
+// pipelines provider.
+func TestPipelines_Generate(t *testing.T) {
+	root := FromTempDirectory(t)
+	if _, err := fn.New().Init(fn.Function{Runtime: "go", Root: root, Registry: TestRegistry}); err != nil {
+		t.Fatal(err)
+	}
+
+	pipelines := mock.NewPipelinesProvider()
+	pipelines.GenerateFn = func(f fn.Function, dir string) ([]string, error) {
+		if dir != "out" {
+			t.Fatalf("expected directory 'out', got %q", dir)
+		}
+		return []string{"out/pipeline.yaml"}, nil
+	}
+
+	cmd := NewPipelinesCmd(NewTestClient(fn.WithPipelinesProvider(pipelines)))
+	out := bytes.Buffer{}
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"generate", "--output", "out"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !pipelines.GenerateInvoked {
+		t.Fatal("pipelines provider was not asked to generate")
+	}
+	if !strings.Contains(out.String(), "out/pipeline.yaml") {
+		t.Fatalf("expected generated files to be listed, got:\n%s", out.String())
+	}
+
+	pipelines = mock.NewPipelinesProvider()
+	cmd = NewPipelinesCmd(NewTestClient(fn.WithPipelinesProvider(pipelines)))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"generate", "--format", "github"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if pipelines.GenerateInvoked {
+		t.Fatal("pipelines provider was invoked for a CI workflow")
+	}
+	if _, err := os.Stat(filepath.Join(root, ".github", "workflows", "func-"+filepath.Base(root)+".yaml")); err != nil {
+		t.Fatalf("expected a GitHub workflow: %v", err)
+	}
+
+	// An existing workflow is only overwritten with --force
+	cmd = NewPipelinesCmd(NewTestClient(fn.WithPipelinesProvider(pipelines)))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"generate", "--format", "github"})
+	if err := cmd.Execute(); !errors.Is(err, ci.ErrWorkflowExists) {
+		t.Fatalf("expected ErrWorkflowExists, got %v", err)
+	}
+	cmd = NewPipelinesCmd(NewTestClient(fn.WithPipelinesProvider(pipelines)))
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"generate", "--format", "github", "--force"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+}
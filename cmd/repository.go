@@ -4,13 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/ory/viper"
 	"github.com/spf13/cobra"
 
+	"knative.dev/func/pkg/builders"
 	"knative.dev/func/pkg/config"
 	fn "knative.dev/func/pkg/functions"
+	"knative.dev/func/pkg/oci"
 )
 
 // command constructors
@@ -28,9 +32,11 @@ NAME
 SYNOPSIS
 	{{rootCmdUse}} repo [-c|--confirm] [-v|--verbose]
 	{{rootCmdUse}} repo list [-r|--repositories] [-c|--confirm] [-v|--verbose]
-	{{rootCmdUse}} repo add <name> <url>[-r|--repositories] [-c|--confirm] [-v|--verbose]
+	{{rootCmdUse}} repo add <name> <url> [--ref] [-r|--repositories] [-c|--confirm] [-v|--verbose]
+	{{rootCmdUse}} repo update [name] [-r|--repositories] [-c|--confirm] [-v|--verbose]
 	{{rootCmdUse}} repo rename <old> <new> [-r|--repositories] [-c|--confirm] [-v|--verbose]
 	{{rootCmdUse}} repo remove <name> [-r|--repositories] [-c|--confirm] [-v|--verbose]
+	{{rootCmdUse}} repo lint <url> [--build] [-v|--verbose]
 
 DESCRIPTION
 	Manage template repositories installed on disk at either the default location
@@ -85,10 +91,25 @@ COMMANDS
 	  a new function using the Go Hello World template:
 	    $ {{rootCmdUse}} create -l go -t boson/hello-world
 
+	  Repositories may also be distributed as OCI artifacts in a container
+	  registry, whose layers contain the repository's files:
+	    $ {{rootCmdUse}} repository add corp oci://registry.example.com/templates:v2
+
+	  To pin the repository to a specific tag or commit, use --ref.  Pinned
+	  repositories are not changed by update.
+	    $ {{rootCmdUse}} repository add boson https://github.com/boson-project/templates --ref v1.0.0
+
+	update
+	  Update an installed repository by fetching from the URL from which it was
+	  installed and fast-forwarding to its latest revision.  With no name, all
+	  installed repositories which are not pinned are updated.
+	    $ {{rootCmdUse}} repository update [name]
+
 	list
 	  List all available repositories, including the installed default
 	  repository.  Repositories available are listed by name.  To see the URL
-	  which was used to install remotes, use --verbose (-v).
+	  which was used to install remotes, the revision installed, and the
+	  version declared by the repository's manifest, use --verbose (-v).
 
 	rename
 	  Rename a previously installed repository from <old> to <new>. Only installed
@@ -103,6 +124,18 @@ COMMANDS
 	  (via the FUNC_REPOSITORIES_PATH environment variable).
 	    $ {{rootCmdUse}} repository remove <name>
 
+	lint
+	  Check a repository for problems before publishing it.  The repository is
+	  loaded from the given URL (or path) in the same way as when adding it.
+	  Its manifest.yaml files are validated at the repository, runtime and
+	  template levels, and each template is checked to be accepted by the
+	  scaffolding of its runtime.  Templates of runtimes without scaffolding
+	  (such as quarkus and springboot) can not be checked, and are reported
+	  as warnings, which do not fail the command.  With --build, each
+	  template of a runtime supported by the host builder is also built to
+	  prove it compiles.
+	    $ {{rootCmdUse}} repository lint <url>
+
 EXAMPLES
 	o Run in confirmation mode (interactive prompts) using the --confirm flag
 	  $ {{rootCmdUse}} repository -c
@@ -123,10 +156,14 @@ EXAMPLES
 	  $ {{rootCmdUse}} create -l node -t metacontroller/metacontroller
 	  ...
 
-	o List all repositories including the URL from which remotes were installed
+	o List all repositories including the URL from which remotes were installed,
+	  the revision installed, and the version declared by their manifest
 	  $ {{rootCmdUse}} repository list -v
 	  default
-	  metacontroller	https://github.com/knative-extensions/func-tastic#metacontroller
+	  metacontroller	https://github.com/knative-extensions/func-tastic#metacontroller	4c1d5f2	v1.2.0
+
+	o Update all installed repositories
+	  $ {{rootCmdUse}} repository update
 
 	o Rename an installed repository
 	  $ {{rootCmdUse}} repository list
@@ -144,6 +181,9 @@ EXAMPLES
 	  $ {{rootCmdUse}} repository remove functastic
 	  $ {{rootCmdUse}} repository list
 	  default
+
+	o Check a local repository, building its templates
+	  $ {{rootCmdUse}} repository lint ./mytemplates --build
 `,
 		SuggestFor: []string{"repositories", "repos", "template", "templates", "pack", "packs"},
 		PreRunE:    bindEnv("confirm", "verbose"),
@@ -161,8 +201,10 @@ EXAMPLES
 
 	cmd.AddCommand(NewRepositoryListCmd(newClient))
 	cmd.AddCommand(NewRepositoryAddCmd(newClient))
+	cmd.AddCommand(NewRepositoryUpdateCmd(newClient))
 	cmd.AddCommand(NewRepositoryRenameCmd(newClient))
 	cmd.AddCommand(NewRepositoryRemoveCmd(newClient))
+	cmd.AddCommand(NewRepositoryLintCmd())
 
 	return cmd
 }
@@ -193,12 +235,34 @@ func NewRepositoryAddCmd(newClient ClientFactory) *cobra.Command {
 		Short:      "Add a repository",
 		Use:        "add <name> <url>",
 		SuggestFor: []string{"ad", "install"},
-		PreRunE:    bindEnv("confirm", "verbose"),
+		PreRunE:    bindEnv("confirm", "verbose", "ref"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRepositoryAdd(cmd, args, newClient)
 		},
 	}
 
+	cfg, err := config.NewDefault()
+	if err != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "error loading config at '%v'. %v\n", config.File(), err)
+	}
+	addConfirmFlag(cmd, cfg.Confirm)
+	addVerboseFlag(cmd, cfg.Verbose)
+	cmd.Flags().String("ref", "", "Tag or commit to which the repository is pinned. Requires a git repository. ($FUNC_REF)")
+
+	return cmd
+}
+
+func NewRepositoryUpdateCmd(newClient ClientFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Short:      "Update installed repositories",
+		Use:        "update [name]",
+		SuggestFor: []string{"upgrade", "pull", "fetch"},
+		PreRunE:    bindEnv("confirm", "verbose"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRepositoryUpdate(cmd, args, newClient)
+		},
+	}
+
 	cfg, err := config.NewDefault()
 	if err != nil {
 		fmt.Fprintf(cmd.OutOrStdout(), "error loading config at '%v'. %v\n", config.File(), err)
@@ -252,6 +316,29 @@ func NewRepositoryRemoveCmd(newClient ClientFactory) *cobra.Command {
 	return cmd
 }
 
+func NewRepositoryLintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Short:      "Check a repository for problems",
+		Use:        "lint <url>",
+		Aliases:    []string{"validate", "check"},
+		SuggestFor: []string{"verify"},
+		Args:       cobra.ExactArgs(1),
+		PreRunE:    bindEnv("build", "verbose"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRepositoryLint(cmd, args)
+		},
+	}
+
+	cfg, err := config.NewDefault()
+	if err != nil {
+		fmt.Fprintf(cmd.OutOrStdout(), "error loading config at '%v'. %v\n", config.File(), err)
+	}
+	cmd.Flags().Bool("build", false, "Build each template using the host builder ($FUNC_BUILD)")
+	addVerboseFlag(cmd, cfg.Verbose)
+
+	return cmd
+}
+
 // command implementations
 // -----------------------
 
@@ -274,7 +361,7 @@ func runRepository(cmd *cobra.Command, args []string, newClient ClientFactory) (
 		Name: "Action",
 		Prompt: &survey.Select{
 			Message: "Operation to perform:",
-			Options: []string{"list", "add", "rename", "remove"},
+			Options: []string{"list", "add", "update", "rename", "remove"},
 			Default: "list",
 		}}
 	answer := struct{ Action string }{}
@@ -288,6 +375,8 @@ func runRepository(cmd *cobra.Command, args []string, newClient ClientFactory) (
 		return runRepositoryList(cmd, newClient)
 	case "add":
 		return runRepositoryAdd(cmd, args, newClient)
+	case "update":
+		return runRepositoryUpdate(cmd, args, newClient)
 	case "rename":
 		return runRepositoryRename(cmd, args, newClient)
 	case "remove":
@@ -312,11 +401,19 @@ func runRepositoryList(_ *cobra.Command, newClient ClientFactory) (err error) {
 		return
 	}
 
-	// Print repository names, or name plus url if verbose
+	// Print repository names, or name plus url, revision and version if verbose
 	// This follows the format of `git remote`, as it is likely familiar.
 	for _, r := range rr {
 		if cfg.Verbose {
-			fmt.Fprintln(os.Stdout, r.Name+"\t"+r.URL())
+			revision := r.Revision()
+			if len(revision) > 7 {
+				revision = revision[:7]
+			}
+			if ref := r.Pinned(); ref != "" {
+				revision = "pinned " + ref
+			}
+			fmt.Fprintln(os.Stdout, strings.TrimRight(
+				r.Name+"\t"+r.URL()+"\t"+revision+"\t"+r.Version, "\t"))
 		} else {
 			fmt.Fprintln(os.Stdout, r.Name)
 		}
@@ -410,9 +507,15 @@ func runRepositoryAdd(_ *cobra.Command, args []string, newClient ClientFactory)
 		fmt.Fprintf(os.Stdout, "URL:  %v\n", params.URL)
 	}
 
-	// Add repository
-	var n string
-	if n, err = client.Repositories().Add(params.Name, params.URL); err != nil {
+	// Add repository, optionally pinned to a ref
+	var (
+		n    string
+		opts []fn.RepositoryAddOption
+	)
+	if ref := viper.GetString("ref"); ref != "" {
+		opts = append(opts, fn.WithRepositoryRef(ref))
+	}
+	if n, err = client.Repositories().Add(params.Name, params.URL, opts...); err != nil {
 		return
 	}
 	if cfg.Verbose {
@@ -421,6 +524,46 @@ func runRepositoryAdd(_ *cobra.Command, args []string, newClient ClientFactory)
 	return
 }
 
+// Update
+func runRepositoryUpdate(_ *cobra.Command, args []string, newClient ClientFactory) (err error) {
+	cfg, err := newRepositoryConfig()
+	if err != nil {
+		return
+	}
+
+	client, done := newClient(ClientConfig{Verbose: cfg.Verbose})
+	defer done()
+
+	if len(args) > 1 {
+		return fmt.Errorf("usage: func repository update [name]")
+	}
+
+	// Update the named repository, or all installed if not provided.
+	// Pinned repositories are skipped when updating all, but are an error
+	// when explicitly named.
+	names := args
+	if len(names) == 0 {
+		if names, err = installedRepositories(client); err != nil {
+			return
+		}
+	}
+	for _, name := range names {
+		from, to, err := client.Repositories().Update(name)
+		if errors.Is(err, fn.ErrRepositoryPinned) && len(args) == 0 {
+			fmt.Fprintf(os.Stdout, "Skipping pinned repository: %v\n", name)
+			continue
+		} else if err != nil {
+			return err
+		}
+		if from == to {
+			fmt.Fprintf(os.Stdout, "Repository %v is up to date\n", name)
+		} else {
+			fmt.Fprintf(os.Stdout, "Repository %v updated: %.7s..%.7s\n", name, from, to)
+		}
+	}
+	return
+}
+
 // Rename
 func runRepositoryRename(_ *cobra.Command, args []string, newClient ClientFactory) (err error) {
 	cfg, err := newRepositoryConfig()
@@ -584,6 +727,46 @@ func runRepositoryRemove(_ *cobra.Command, args []string, newClient ClientFactor
 	return
 }
 
+// Lint
+func runRepositoryLint(cmd *cobra.Command, args []string) (err error) {
+	var (
+		uri     = args[0]
+		verbose = viper.GetBool("verbose")
+		options []fn.LintOption
+	)
+
+	// Paths are loaded as file URIs, as when adding a repository.
+	if !strings.Contains(uri, "://") {
+		if uri, err = filepath.Abs(uri); err != nil {
+			return
+		}
+		uri = "file://" + filepath.ToSlash(uri)
+	}
+	if viper.GetBool("build") {
+		options = append(options, fn.WithLintBuilder(
+			oci.NewBuilder(builders.Host, verbose), oci.IsSupported))
+	}
+
+	issues, err := fn.LintRepository(cmd.Context(), uri, options...)
+	if err != nil {
+		return fmt.Errorf("unable to load repository. %w", err)
+	}
+	errs := 0
+	for _, i := range issues {
+		fmt.Fprintln(cmd.OutOrStdout(), i)
+		if !i.Warning {
+			errs++
+		}
+	}
+	if errs > 0 {
+		return fmt.Errorf("%v issues found", errs)
+	}
+	if verbose {
+		fmt.Fprintln(cmd.OutOrStdout(), "No issues found")
+	}
+	return
+}
+
 // Installed repositories
 // All repositories which have been installed (does not include builtin)
 func installedRepositories(client *fn.Client) ([]string, error) {
@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	. "knative.dev/func/pkg/testing"
@@ -164,3 +168,49 @@ func TestRepository_Remove(t *testing.T) {
 		t.Fatalf("expected:\n'%v'\ngot:\n'%v'\n", expect, output)
 	}
 }
+
+// TestRepository_Lint ensures that the 'lint' subcommand accepts a path to a
+// repository, printing its issues and failing if there are any other than
+// warnings.
+func TestRepository_Lint(t *testing.T) {
+	root := FromTempDirectory(t)
+	if err := os.MkdirAll(filepath.Join(root, "repo", "node", "http"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "repo", "node", "http", "index.js"), []byte("module.exports = { handle: () => {} };\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A valid repository, with a template which can only be warned about
+	if err := os.MkdirAll(filepath.Join(root, "repo", "quarkus", "http"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "repo", "quarkus", "http", "pom.xml"), []byte("<project/>\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	cmd := NewRepositoryLintCmd()
+	cmd.SetArgs([]string{"repo"})
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "quarkus/http: warning:") {
+		t.Fatalf("expected a warning for the quarkus template, got %q", out.String())
+	}
+
+	// A repository with an invalid manifest
+	if err := os.WriteFile(filepath.Join(root, "repo", "manifest.yaml"), []byte("nmae: typo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	cmd = NewRepositoryLintCmd()
+	cmd.SetArgs([]string{"repo"})
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected issues to fail the command")
+	}
+	if !strings.Contains(out.String(), "manifest.yaml") {
+		t.Fatalf("expected the manifest issue to be printed, got %q", out.String())
+	}
+}
@@ -86,6 +86,7 @@ Learn more about Knative at: https://knative.dev`, cfg.Name),
 				NewDeployCmd(newClient),
 				NewDeleteCmd(newClient),
 				NewListCmd(newClient),
+				NewPipelinesCmd(newClient),
 				NewSubscribeCmd(),
 			},
 		},
@@ -314,6 +315,12 @@ func addConfirmFlag(cmd *cobra.Command, dflt bool) {
 	cmd.Flags().BoolP("confirm", "c", dflt, "Prompt to confirm options interactively ($FUNC_CONFIRM)")
 }
 
+// addEnvironmentFlag ensures common text/wording when the --environment flag
+// is used to select a named environment of the function.
+func addEnvironmentFlag(cmd *cobra.Command) {
+	cmd.Flags().String("environment", "", "Named environment of the function (as defined in func.yaml) to use in place of the default. ($FUNC_ENVIRONMENT)")
+}
+
 // addPathFlag ensures common text/wording when the --path flag is used
 func addPathFlag(cmd *cobra.Command) {
 	cmd.Flags().StringP("path", "p", "", "Path to the function.  Default is current directory ($FUNC_PATH)")
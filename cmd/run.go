@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/ory/viper"
 	"github.com/spf13/cobra"
 
+	"knative.dev/func/pkg/broker"
 	"knative.dev/func/pkg/config"
 	"knative.dev/func/pkg/docker"
 	fn "knative.dev/func/pkg/functions"
@@ -21,14 +23,14 @@ func NewRunCmd(newClient ClientFactory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "run",
 		Short: "Run the function locally",
-		Long: `
+		Long: fmt.Sprintf(`
 NAME
 	{{rootCmdUse}} run - Run a function locally
 
 SYNOPSIS
 	{{rootCmdUse}} run [-t|--container] [-r|--registry] [-i|--image] [-e|--env]
-	             [--build] [-b|--builder] [--builder-image] [-c|--confirm]
-	             [-v|--verbose]
+	             [--build] [-b|--builder] [--builder-image] [--broker]
+	             [-c|--confirm] [-v|--verbose]
 
 DESCRIPTION
 	Run the function locally.
@@ -57,6 +59,33 @@ DESCRIPTION
 	  or even completely replace this scafolding code, see the 'scaffold'
 	  subcommand.
 
+	Local Broker Emulator
+	  Functions which subscribe to events (see '{{rootCmdUse}} subscribe') can be
+	  exercised locally using the --broker flag.  This starts an emulated
+	  Knative Eventing broker which accepts CloudEvents at the given address
+	  (default %v).  Events are filtered using the function's
+	  subscriptions exactly as a Trigger would, and those matching are delivered
+	  to the running function.  Events sent to /<broker> are routed to the
+	  subscriptions whose source is <broker>; the root path is the broker named
+	  "default".  Events returned by the function in reply are fed back into
+	  the broker, such that chains of functions can be tested locally.
+
+	Secrets
+	  Environment variables which reference secrets ({{"{{"}} secret:name:key }}
+	  or {{"{{"}} secret:name }}) are resolved using the secret resolvers defined in
+	  the function's func.yaml, tried in order, such that the function is run
+	  with the same effective environment as when deployed:
+	    run:
+	      secretResolvers:
+	      - type: dotenv       # KEY=VALUE lines of a .env file
+	        path: .env
+	      - type: file         # <path>/<secret name>/<key> files
+	        path: secrets
+	      - type: exec         # output of a command such as a password manager
+	        command: pass show func/{name}/{key}
+	      - type: kubernetes   # secrets of the current kubeconfig context
+	        namespace: staging
+
 EXAMPLES
 
 	o Run the function locally from within its container.
@@ -68,9 +97,13 @@ EXAMPLES
 
 	o Run the function locally on the host with no containerization (Go only).
 	  $ {{rootCmdUse}} run --container=false
-`,
+
+	o Run the function locally with a broker emulator delivering its
+	  subscribed events.
+	  $ {{rootCmdUse}} run --broker
+`, broker.DefaultAddress),
 		SuggestFor: []string{"rnu"},
-		PreRunE:    bindEnv("build", "builder", "builder-image", "confirm", "container", "env", "image", "path", "registry", "start-timeout", "verbose"),
+		PreRunE:    bindEnv("build", "builder", "builder-image", "broker", "confirm", "container", "env", "image", "path", "registry", "start-timeout", "verbose"),
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return runRun(cmd, newClient)
 		},
@@ -124,6 +157,9 @@ EXAMPLES
 	cmd.Flags().String("build", "auto",
 		"Build the function. [auto|true|false]. ($FUNC_BUILD)")
 	cmd.Flags().Lookup("build").NoOptDefVal = "true" // register `--build` as equivalient to `--build=true`
+	cmd.Flags().String("broker", "",
+		fmt.Sprintf("Start a local broker emulator on the given address which delivers subscribed events to the function.  Default when provided without a value is %v. ($FUNC_BROKER)", broker.DefaultAddress))
+	cmd.Flags().Lookup("broker").NoOptDefVal = broker.DefaultAddress
 
 	// Oft-shared flags:
 	addConfirmFlag(cmd, cfg.Confirm)
@@ -246,6 +282,27 @@ func runRun(cmd *cobra.Command, newClient ClientFactory) (err error) {
 
 	fmt.Fprintf(cmd.OutOrStderr(), "Running on host port %v\n", job.Port)
 
+	// Broker Emulator
+	//
+	// Optionally start a local broker which delivers events matching the
+	// function's subscriptions to the running job.
+	if cfg.Broker != "" {
+		if len(f.Deploy.Subscriptions) == 0 {
+			fmt.Fprintf(cmd.OutOrStderr(), "Warning: the function has no subscriptions, so no events will be delivered by the broker emulator\n")
+		}
+		sink := fmt.Sprintf("http://%v", net.JoinHostPort(job.Host, job.Port))
+		b, err := broker.New(f.Deploy.Subscriptions, sink,
+			broker.WithVerbose(cfg.Verbose), broker.WithOutput(cmd.OutOrStderr()))
+		if err != nil {
+			return err
+		}
+		if err = b.Start(cmd.Context(), cfg.Broker); err != nil {
+			return err
+		}
+		defer func() { _ = b.Stop() }()
+		fmt.Fprintf(cmd.OutOrStderr(), "Broker emulator accepting events at http://%v\n", b.Address())
+	}
+
 	select {
 	case <-cmd.Context().Done():
 		if !errors.Is(cmd.Context().Err(), context.Canceled) {
@@ -285,6 +342,10 @@ type runConfig struct {
 	// StartTimeout optionally adjusts the startup timeout from the client's
 	// default of fn.DefaultStartTimeout.
 	StartTimeout time.Duration
+
+	// Broker is the address on which to start a local broker emulator.
+	// Empty disables the emulator.
+	Broker string
 }
 
 func newRunConfig(cmd *cobra.Command) (c runConfig) {
@@ -294,6 +355,7 @@ func newRunConfig(cmd *cobra.Command) (c runConfig) {
 		Env:          viper.GetStringSlice("env"),
 		Container:    viper.GetBool("container"),
 		StartTimeout: viper.GetDuration("start-timeout"),
+		Broker:       viper.GetString("broker"),
 	}
 	// NOTE: .Env should be viper.GetStringSlice, but this returns unparsed
 	// results and appears to be an open issue since 2017:
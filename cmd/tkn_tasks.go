@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -15,13 +16,44 @@ func NewTektonClusterTasksCmd() *cobra.Command {
 		Long: `This command prints tekton tekton task embed in the func binary.
 Some advanced functionality like OpenShift's Web Console build my require installation of these tasks.
 Installation: func tkn-tasks | kubectl apply -f -
+
+With --bundle the tasks are instead pushed as a Tekton bundle to the given
+image reference, and the reference pinned by the digest of the bundle is
+printed.  Such a bundle can be used by remote builds with build.taskBundle of
+func.yaml, for instance when mirrored into the registry of a disconnected
+cluster.
+Publishing: func tkn-tasks --bundle registry.example.com/knative/func-tasks:v1
+
+With --bundle-file the pinned reference is written to the given file instead,
+such that it can be consumed by scripts regardless of other output.
 `,
 		Hidden: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			_, err := fmt.Fprintln(cmd.OutOrStdout(), tekton.GetClusterTasks())
+			bundle, err := cmd.Flags().GetString("bundle")
+			if err != nil {
+				return err
+			}
+			if bundle != "" {
+				ref, err := tekton.PublishTaskBundle(cmd.Context(), bundle)
+				if err != nil {
+					return err
+				}
+				file, err := cmd.Flags().GetString("bundle-file")
+				if err != nil {
+					return err
+				}
+				if file != "" {
+					return os.WriteFile(file, []byte(ref), 0644)
+				}
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), ref)
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), tekton.GetClusterTasks())
 			return err
 		},
 	}
+	cmd.Flags().String("bundle", "", "Push the tasks as a Tekton bundle to this image reference")
+	cmd.Flags().String("bundle-file", "", "Write the reference of the pushed bundle, pinned by digest, to this file rather than printing it")
 
 	return cmd
 }
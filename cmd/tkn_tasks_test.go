@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+)
+
+// TestTektonClusterTasks_BundleFile ensures that the reference of a published
+// bundle is written, pinned by digest, to --bundle-file rather than printed.
+func TestTektonClusterTasks_BundleFile(t *testing.T) {
+	srv := httptest.NewServer(ggcrregistry.New())
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+	file := filepath.Join(t.TempDir(), "bundle.txt")
+
+	var out bytes.Buffer
+	cmd := NewTektonClusterTasksCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--bundle", host + "/knative/func-tasks:test", "--bundle-file", file})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected no output, got %q", out.String())
+	}
+	bb, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = name.NewDigest(string(bb)); err != nil {
+		t.Fatalf("expected a reference pinned by digest, got %q: %v", bb, err)
+	}
+}
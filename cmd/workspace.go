@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	fn "knative.dev/func/pkg/functions"
+)
+
+// Workspaces
+// ----------
+// Commands which accept --all operate on each function of the workspace
+// rooted at --path (see fn.Workspace), rather than on a single function.
+// Flags which describe a single function (such as --image) are not
+// applicable in this mode.  Of the remaining flags, those explicitly provided
+// take precedence over the function's own values, which in turn take
+// precedence over values defined by the workspace.
+
+// addAllFlag ensures common text/wording when the --all flag is used to
+// operate on all functions of a workspace.
+func addAllFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("all", false, fmt.Sprintf("Operate on all functions of the workspace at --path (see %v) ($FUNC_ALL)", fn.WorkspaceFile))
+}
+
+// workspaceSingularFlags are flags which describe a single function, and are
+// therefore not applicable when operating on a workspace.
+var workspaceSingularFlags = []string{"image", "env", "domain", "git-url", "git-branch", "git-dir", "service-account"}
+
+// loadWorkspace for the given path, returning the workspace and its
+// functions.
+func loadWorkspace(cmd *cobra.Command, path string) (w fn.Workspace, ff []fn.Function, err error) {
+	for _, name := range workspaceSingularFlags {
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Changed {
+			return w, ff, fmt.Errorf("--%v can not be used with --all", name)
+		}
+	}
+	if w, err = fn.NewWorkspace(path); err != nil {
+		return
+	}
+	if ff, err = w.Load(); err != nil {
+		return
+	}
+	if len(ff) == 0 {
+		err = fmt.Errorf("no functions found in workspace %v", w.Root)
+	}
+	return
+}
+
+// configureWorkspaceFunction applies the explicitly provided global flags to
+// the function.
+func configureWorkspaceFunction(cmd *cobra.Command, f fn.Function, cfg buildConfig) fn.Function {
+	if cmd.Flags().Changed("registry") {
+		f.Registry = cfg.Registry
+	}
+	if cmd.Flags().Changed("builder") || f.Build.Builder == "" {
+		f.Build.Builder = cfg.Builder
+	}
+	return f
+}
+
+// writeWorkspaceFunction writes the build and deploy state of a function of
+// the workspace.  Values applied from the workspace and from flags are not
+// persisted, such that they continue to cascade from the workspace.
+func writeWorkspaceFunction(f fn.Function) error {
+	persisted, err := fn.NewFunction(f.Root)
+	if err != nil {
+		return err
+	}
+	persisted.Build.Image = f.Build.Image
+	persisted.Deploy.Namespace = f.Deploy.Namespace
+	persisted.Deploy.Image = f.Deploy.Image
+	if err = persisted.Write(); err != nil {
+		return err
+	}
+	return persisted.Stamp()
+}
+
+// runBuildAll builds each function of the workspace.
+func runBuildAll(cmd *cobra.Command, newClient ClientFactory) error {
+	cfg := newBuildConfig()
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	w, ff, err := loadWorkspace(cmd, cfg.Path)
+	if err != nil {
+		return err
+	}
+	cmd.SetContext(cfg.WithValues(cmd.Context()))
+	buildOptions, err := cfg.buildOptions()
+	if err != nil {
+		return err
+	}
+
+	_, err = fn.ForEach(cmd.Context(), ff, w.Concurrency, func(ctx context.Context, f fn.Function) (fn.Function, error) {
+		f = configureWorkspaceFunction(cmd, f, cfg)
+		c := cfg
+		c.Builder = f.Build.Builder
+		clientOptions, err := c.clientOptions()
+		if err != nil {
+			return f, err
+		}
+		client, done := newClient(ClientConfig{Verbose: cfg.Verbose}, clientOptions...)
+		defer done()
+
+		if f, err = client.Build(ctx, f, buildOptions...); err != nil {
+			return f, err
+		}
+		if cfg.Push {
+			if f, _, err = client.Push(ctx, f); err != nil {
+				return f, err
+			}
+		}
+		return f, writeWorkspaceFunction(f)
+	})
+	return err
+}
+
+// runDeployAll deploys each function of the workspace.
+func runDeployAll(cmd *cobra.Command, newClient ClientFactory) error {
+	cfg := newDeployConfig(cmd)
+	if err := cfg.Validate(cmd); err != nil {
+		return err
+	}
+	// Environments are defined by each function, such that a workspace can
+	// not be deployed to one (also when set via $FUNC_ENVIRONMENT)
+	if cfg.Environment != "" {
+		return errors.New("--environment can not be used with --all")
+	}
+	w, ff, err := loadWorkspace(cmd, cfg.Path)
+	if err != nil {
+		return err
+	}
+	cmd.SetContext(cfg.WithValues(cmd.Context()))
+	buildOptions, err := cfg.buildOptions()
+	if err != nil {
+		return err
+	}
+
+	_, err = fn.ForEach(cmd.Context(), ff, w.Concurrency, func(ctx context.Context, f fn.Function) (fn.Function, error) {
+		f = configureWorkspaceFunction(cmd, f, cfg.buildConfig)
+		if cmd.Flags().Changed("namespace") || (f.Namespace == "" && f.Deploy.Namespace == "") {
+			f.Namespace = cfg.Namespace
+		}
+		if cmd.Flags().Changed("remote") {
+			f.Local.Remote = cfg.Remote
+		}
+		c := cfg
+		c.Builder = f.Build.Builder
+		clientOptions, err := c.clientOptions()
+		if err != nil {
+			return f, err
+		}
+		client, done := newClient(ClientConfig{Verbose: cfg.Verbose, InsecureSkipVerify: cfg.RegistryInsecure}, clientOptions...)
+		defer done()
+
+		if cfg.Remote {
+			var url string
+			if cfg.RetryLast {
+				url, f, err = client.RerunPipeline(ctx, f)
+			} else {
+				url, f, err = client.RunPipeline(ctx, f)
+			}
+			if err != nil {
+				return f, err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Function %v deployed at %v\n", f.Name, url)
+		} else {
+			var justBuilt, justPushed bool
+			if f, justBuilt, err = build(cmd, cfg.Build, f, client, buildOptions); err != nil {
+				return f, err
+			}
+			if cfg.Push {
+				if f, justPushed, err = client.Push(ctx, f); err != nil {
+					return f, err
+				}
+			}
+			if (justBuilt || justPushed) && f.Build.Image != "" {
+				f.Deploy.Image = f.Build.Image
+			}
+			if f, err = client.Deploy(ctx, f, fn.WithDeploySkipBuildCheck(cfg.Build == "false")); err != nil {
+				return f, err
+			}
+		}
+		return f, writeWorkspaceFunction(f)
+	})
+	return err
+}
+
+// runListLocal lists the functions of the workspace at path.
+func runListLocal(cmd *cobra.Command, path, output string) error {
+	if cmd.Flags().Changed("namespace") || cmd.Flags().Changed("all-namespaces") {
+		return errors.New("--local can not be used with --namespace or --all-namespaces")
+	}
+	w, err := fn.NewWorkspace(path)
+	if err != nil {
+		return err
+	}
+	ff, err := w.Load()
+	if err != nil {
+		return err
+	}
+	if len(ff) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "no functions found in workspace %v\n", w.Root)
+		return nil
+	}
+	items := make(localListItems, 0, len(ff))
+	for _, f := range ff {
+		path, err := filepath.Rel(w.Root, f.Root)
+		if err != nil {
+			path = f.Root
+		}
+		namespace := f.Deploy.Namespace
+		if namespace == "" {
+			namespace = f.Namespace
+		}
+		items = append(items, localListItem{
+			Name:      f.Name,
+			Path:      path,
+			Runtime:   f.Runtime,
+			Registry:  f.Registry,
+			Namespace: namespace,
+			Deployed:  f.Deploy.Namespace != "",
+		})
+	}
+	write(cmd.OutOrStdout(), items, output)
+	return nil
+}
+
+// Output Formatting (serializers)
+// -------------------------------
+
+type localListItem struct {
+	Name      string `json:"name" yaml:"name" xml:"name"`
+	Path      string `json:"path" yaml:"path" xml:"path"`
+	Runtime   string `json:"runtime" yaml:"runtime" xml:"runtime"`
+	Registry  string `json:"registry" yaml:"registry" xml:"registry"`
+	Namespace string `json:"namespace" yaml:"namespace" xml:"namespace"`
+	Deployed  bool   `json:"deployed" yaml:"deployed" xml:"deployed"`
+}
+
+type localListItems []localListItem
+
+func (items localListItems) Human(w io.Writer) error {
+	return items.Plain(w)
+}
+
+func (items localListItems) Plain(w io.Writer) error {
+	tabWriter := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	defer tabWriter.Flush()
+
+	fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\t%s\t%s\n", "NAME", "PATH", "RUNTIME", "REGISTRY", "NAMESPACE", "DEPLOYED")
+	for _, item := range items {
+		fmt.Fprintf(tabWriter, "%s\t%s\t%s\t%s\t%s\t%v\n", item.Name, item.Path, item.Runtime, item.Registry, item.Namespace, item.Deployed)
+	}
+	return nil
+}
+
+func (items localListItems) JSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(items)
+}
+
+func (items localListItems) XML(w io.Writer) error {
+	return xml.NewEncoder(w).Encode(items)
+}
+
+func (items localListItems) YAML(w io.Writer) error {
+	return yaml.NewEncoder(w).Encode(items)
+}
+
+func (items localListItems) URL(w io.Writer) error {
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\n", item.Path)
+	}
+	return nil
+}
@@ -33,7 +33,18 @@ function build_release() {
     knative_version="$(git describe --tags --match 'knative-*')"
     go_module_version="$(git describe --tags --match 'v*')"
   fi
-  FUNC_REPO_BRANCH_REF="$(git branch --show-current)" VERS="${go_module_version}" KVER="${knative_version}" make cross-platform
+
+  # The binaries reference the Tekton tasks of the release from a bundle, which
+  # is pinned by digest such that later pushes of the tag do not affect them
+  local tasks_bundle=""
+  if (( PUBLISH_RELEASE )); then
+    echo "📦 Publishing Tekton tasks bundle"
+    KVER="${knative_version}" FUNC_TASKS_BUNDLE_FILE="func-tasks-bundle.txt" make publish-tasks-bundle
+    tasks_bundle="$(cat func-tasks-bundle.txt)"
+    rm -f func-tasks-bundle.txt
+    echo "📦     ${tasks_bundle}"
+  fi
+  FUNC_REPO_BRANCH_REF="$(git branch --show-current)" VERS="${go_module_version}" KVER="${knative_version}" FUNC_TASKS_BUNDLE="${tasks_bundle}" make cross-platform
 
   ARTIFACTS_TO_PUBLISH="func_darwin_amd64 func_darwin_arm64 func_linux_amd64 func_linux_arm64 func_linux_ppc64le func_linux_s390x func_windows_amd64.exe"
   ARTIFACTS_TO_PUBLISH="${ARTIFACTS_TO_PUBLISH}"
@@ -0,0 +1,266 @@
+// Package broker provides a local emulator of a Knative Eventing Broker for
+// exercising functions with subscriptions during local development.
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	fn "knative.dev/func/pkg/functions"
+)
+
+const (
+	// DefaultAddress on which the broker emulator accepts events.
+	DefaultAddress = "127.0.0.1:8081"
+
+	// DefaultBroker is the name of the broker which receives events posted to
+	// the root path of the emulator.
+	DefaultBroker = "default"
+
+	// TTLExtension is the CloudEvent extension attribute used to limit the
+	// number of times an event may be routed through the broker.  This
+	// mirrors the attribute used by Knative Eventing broker implementations.
+	TTLExtension = "knativebrokerttl"
+
+	// DefaultTTL is the number of hops an event is permitted to take before
+	// being dropped, protecting against reply loops.
+	DefaultTTL = 255
+)
+
+// Broker emulates one or more Knative Eventing Brokers.  Events are accepted
+// over HTTP at /<broker> (or /<namespace>/<broker>, or / for the default
+// broker), filtered as a Trigger would using the subscriptions' filters, and
+// forwarded to the subscriber.  Reply events from a subscriber are fed back
+// into the broker from which the event originated.
+type Broker struct {
+	subscriptions []fn.KnativeSubscription
+	sink          string
+	transport     http.RoundTripper
+	out           io.Writer
+	verbose       bool
+
+	client cloudevents.Client
+	server *http.Server
+	wg     sync.WaitGroup
+	addr   string
+}
+
+// Option for the broker constructor.
+type Option func(*Broker)
+
+// WithTransport sets the transport used when delivering events.
+func WithTransport(t http.RoundTripper) Option {
+	return func(b *Broker) {
+		b.transport = t
+	}
+}
+
+// WithVerbose enables logging of each event received and delivered.
+func WithVerbose(verbose bool) Option {
+	return func(b *Broker) {
+		b.verbose = verbose
+	}
+}
+
+// WithOutput sets the writer to which event activity is logged.
+func WithOutput(w io.Writer) Option {
+	return func(b *Broker) {
+		b.out = w
+	}
+}
+
+// New broker emulator which delivers events matching the given subscriptions
+// to the sink (the URL of the locally running function).
+func New(subscriptions []fn.KnativeSubscription, sink string, options ...Option) (*Broker, error) {
+	b := &Broker{
+		subscriptions: subscriptions,
+		sink:          sink,
+		transport:     http.DefaultTransport,
+		out:           os.Stderr,
+	}
+	for _, o := range options {
+		o(b)
+	}
+	var err error
+	b.client, err = cloudevents.NewClientHTTP(cloudevents.WithRoundTripper(b.transport))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create broker client: %w", err)
+	}
+	return b, nil
+}
+
+// Start accepting events on the given address.  The emulator runs until
+// Stop is called or the context is canceled.
+func (b *Broker) Start(ctx context.Context, address string) error {
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("cannot start broker emulator: %w", err)
+	}
+	b.addr = l.Addr().String()
+	b.server = &http.Server{Handler: b}
+	go func() {
+		if err := b.server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(b.out, "broker emulator error: %v\n", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		_ = b.Stop()
+	}()
+	return nil
+}
+
+// Address on which the broker is accepting events.  Empty until started.
+func (b *Broker) Address() string {
+	return b.addr
+}
+
+// Stop the broker, waiting for in-flight deliveries to complete.
+func (b *Broker) Stop() error {
+	if b.server == nil {
+		return nil
+	}
+	err := b.server.Close()
+	b.wg.Wait()
+	return err
+}
+
+// ServeHTTP accepts a CloudEvent (binary or structured mode) for the broker
+// named by the request path.
+func (b *Broker) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		res.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	event, err := cloudevents.NewEventFromHTTPRequest(req)
+	if err != nil {
+		http.Error(res, fmt.Sprintf("invalid CloudEvent: %v", err), http.StatusBadRequest)
+		return
+	}
+	b.Publish(brokerName(req.URL.Path), *event)
+	res.WriteHeader(http.StatusAccepted)
+}
+
+// Publish an event to the named broker.  Delivery is asynchronous.
+func (b *Broker) Publish(broker string, event cloudevents.Event) {
+	ttl := DefaultTTL
+	if v, ok := event.Extensions()[TTLExtension]; ok {
+		if n, err := toInt(v); err == nil {
+			ttl = n
+		}
+	}
+	if ttl <= 0 {
+		fmt.Fprintf(b.out, "broker %q dropped event %v: TTL exceeded\n", broker, event.ID())
+		return
+	}
+	event.SetExtension(TTLExtension, ttl-1)
+
+	if b.verbose {
+		fmt.Fprintf(b.out, "broker %q received event %v (type %q, source %q)\n", broker, event.ID(), event.Type(), event.Source())
+	}
+	for _, s := range b.subscriptions {
+		if s.Source != broker || !Matches(s.Filters, event) {
+			continue
+		}
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.deliver(broker, event)
+		}()
+	}
+}
+
+// deliver the event to the sink, publishing any reply back to the broker.
+func (b *Broker) deliver(broker string, event cloudevents.Event) {
+	ctx := cloudevents.ContextWithTarget(context.Background(), b.sink)
+	reply, result := b.client.Request(ctx, event)
+	if !cloudevents.IsACK(result) {
+		fmt.Fprintf(b.out, "broker %q failed to deliver event %v: %v\n", broker, event.ID(), result)
+		return
+	}
+	if b.verbose {
+		fmt.Fprintf(b.out, "broker %q delivered event %v\n", broker, event.ID())
+	}
+	if reply == nil {
+		return
+	}
+	// Replies inherit the remaining TTL of the event which caused them.
+	reply.SetExtension(TTLExtension, event.Extensions()[TTLExtension])
+	b.Publish(broker, *reply)
+}
+
+// Matches returns true if the event matches the filters as a Trigger's
+// attribute filter would: each filter attribute must exactly equal the
+// event's attribute (context attribute or extension) of the same name.
+// An empty filter value is a wildcard, matching whether or not the event
+// has the attribute.
+func Matches(filters map[string]string, event cloudevents.Event) bool {
+	for name, want := range filters {
+		if want == "" {
+			continue
+		}
+		if got, ok := attribute(event, name); !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// attribute returns the string value of the named attribute of the event.
+func attribute(event cloudevents.Event, name string) (string, bool) {
+	switch strings.ToLower(name) {
+	case "specversion":
+		return event.SpecVersion(), true
+	case "id":
+		return event.ID(), true
+	case "type":
+		return event.Type(), true
+	case "source":
+		return event.Source(), true
+	case "subject":
+		return event.Subject(), event.Subject() != ""
+	case "datacontenttype":
+		return event.DataContentType(), event.DataContentType() != ""
+	case "dataschema":
+		return event.DataSchema(), event.DataSchema() != ""
+	}
+	v, ok := event.Extensions()[strings.ToLower(name)]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprint(v), true
+}
+
+// brokerName from a request path of the form /, /<broker> or
+// /<namespace>/<broker>.
+func brokerName(path string) string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return DefaultBroker
+	}
+	parts := strings.Split(path, "/")
+	return parts[len(parts)-1]
+}
+
+func toInt(v any) (int, error) {
+	switch t := v.(type) {
+	case int32:
+		return int(t), nil
+	case int:
+		return t, nil
+	case string:
+		var n int
+		_, err := fmt.Sscan(t, &n)
+		return n, err
+	}
+	return 0, fmt.Errorf("unexpected type %T", v)
+}
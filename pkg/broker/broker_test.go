@@ -0,0 +1,131 @@
+package broker_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"knative.dev/func/pkg/broker"
+	fn "knative.dev/func/pkg/functions"
+)
+
+// TestMatches ensures filters are applied as a Trigger's attribute filter.
+func TestMatches(t *testing.T) {
+	event := cloudevents.NewEvent()
+	event.SetID("1")
+	event.SetType("com.example.order")
+	event.SetSource("/orders")
+	event.SetExtension("region", "eu")
+
+	tests := []struct {
+		name    string
+		filters map[string]string
+		want    bool
+	}{
+		{"no filters", nil, true},
+		{"type match", map[string]string{"type": "com.example.order"}, true},
+		{"type mismatch", map[string]string{"type": "com.example.invoice"}, false},
+		{"extension match", map[string]string{"type": "com.example.order", "region": "eu"}, true},
+		{"extension mismatch", map[string]string{"region": "us"}, false},
+		{"missing attribute", map[string]string{"subject": "x"}, false},
+		{"any value", map[string]string{"region": ""}, true},
+		{"any value of missing attribute", map[string]string{"subject": ""}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := broker.Matches(tt.filters, event); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestBroker_Delivery ensures that events published to the broker are
+// delivered to the sink when they match a subscription of that broker, and
+// that replies are fed back into the broker.
+func TestBroker_Delivery(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []string
+		done     = make(chan struct{})
+	)
+
+	// The function replies to "order" events with an "invoice" event, and
+	// acknowledges "invoice" events without a reply.
+	sink := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		event, err := cloudevents.NewEventFromHTTPRequest(req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mu.Lock()
+		received = append(received, event.Type())
+		if len(received) == 2 {
+			close(done)
+		}
+		mu.Unlock()
+
+		if event.Type() == "order" {
+			reply := cloudevents.NewEvent()
+			reply.SetID("reply")
+			reply.SetType("invoice")
+			reply.SetSource("/fn")
+			res.Header().Set("Ce-Id", reply.ID())
+			res.Header().Set("Ce-Type", reply.Type())
+			res.Header().Set("Ce-Source", reply.Source())
+			res.Header().Set("Ce-Specversion", "1.0")
+		}
+		res.WriteHeader(http.StatusOK)
+	}))
+	defer sink.Close()
+
+	subscriptions := []fn.KnativeSubscription{
+		{Source: "default", Filters: map[string]string{"type": "order"}},
+		{Source: "default", Filters: map[string]string{"type": "invoice"}},
+		{Source: "other", Filters: map[string]string{"type": "order"}},
+	}
+	b, err := broker.New(subscriptions, sink.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err = b.Start(ctx, "127.0.0.1:"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Send an event to the default broker
+	c, err := cloudevents.NewClientHTTP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := cloudevents.NewEvent()
+	event.SetID("1")
+	event.SetType("order")
+	event.SetSource("/test")
+	if result := c.Send(cloudevents.ContextWithTarget(ctx, "http://"+b.Address()), event); !cloudevents.IsACK(result) {
+		t.Fatal(result)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for delivery")
+	}
+	if err = b.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The order should have been delivered once (the "other" broker does not
+	// receive it), followed by the reply.
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || received[0] != "order" || received[1] != "invoice" {
+		t.Fatalf("unexpected deliveries: %v", received)
+	}
+}
@@ -250,7 +250,8 @@ func scaffold(cfg *api.Config, f fn.Function) (*api.Config, error) {
 	}
 
 	// Write scaffolding to .s2i/builds/last
-	err = scaffolding.Write(appRoot, f.Root, f.Runtime, f.Invoke, embeddedRepo.FS())
+	err = scaffolding.Write(appRoot, f.Root, f.Runtime, f.Invoke, embeddedRepo.FS(),
+		scaffolding.WithHealthEndpoints(f.Deploy.HealthEndpoints.Readiness, f.Deploy.HealthEndpoints.Liveness))
 	if err != nil {
 		return cfg, fmt.Errorf("unable to build due to a scaffold error. %w", err)
 	}
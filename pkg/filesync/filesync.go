@@ -0,0 +1,374 @@
+// Package filesync implements an incremental transfer of a directory tree to
+// a remote copy of it, such as the sources of a function to the volume used by
+// an on-cluster build.  The remote side reports a manifest of what it already
+// has, after which only the entries which differ are sent along with the list
+// of entries to be removed.
+//
+// The exchange over a pair of streams is:
+//
+//	local  -> remote: the Hello line
+//	remote -> local:  the remote Manifest as a line of JSON
+//	local  -> remote: the paths to remove as a line of JSON, followed by a
+//	                  tar stream of the entries which were added or changed
+package filesync
+
+import (
+	"archive/tar"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Hello is sent by the local side to start an exchange.  Output of the remote
+// side is only relayed once its input is connected, so the remote side waits
+// for it before reporting its manifest.
+const Hello = "func-sync/1"
+
+// Entry of a directory tree.
+type Entry struct {
+	// Mode is the type and permission bits of the entry.
+	Mode fs.FileMode `json:"mode"`
+	// Hash is the hex encoded sha256 of the content of a regular file.
+	Hash string `json:"hash,omitempty"`
+	// Link is the target of a symbolic link, relative to the link.
+	Link string `json:"link,omitempty"`
+}
+
+// Manifest of a directory tree, keyed by the slash separated path of each
+// entry relative to the root of the tree.
+type Manifest map[string]Entry
+
+// Scan returns the manifest of the tree at root.  Paths for which ignored
+// returns true are not included; ignored may be nil.  Symbolic links are
+// recorded relative to their location and must not point outside of root.
+func Scan(root string, ignored func(string) bool) (Manifest, error) {
+	return scan(root, ignored, true)
+}
+
+// scan returns the manifest of the tree at root, failing on links which point
+// outside of root if strict.  Otherwise such links are recorded as they are.
+func scan(root string, ignored func(string) bool, strict bool) (Manifest, error) {
+	const up = ".." + string(os.PathSeparator)
+
+	m := Manifest{}
+	err := filepath.Walk(root, func(p string, fi fs.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("error traversing directory: %w", err)
+		}
+		relp, err := filepath.Rel(root, p)
+		if err != nil {
+			return fmt.Errorf("cannot get relative path: %w", err)
+		}
+		if relp == "." || (ignored != nil && ignored(relp)) {
+			return nil
+		}
+
+		e := Entry{Mode: fi.Mode() & (fs.ModeType | fs.ModePerm)}
+		switch {
+		case fi.Mode().IsRegular():
+			if e.Hash, err = hashFile(p); err != nil {
+				return err
+			}
+		case fi.Mode()&fs.ModeSymlink != 0:
+			lnk, err := os.Readlink(p)
+			if err != nil {
+				return fmt.Errorf("cannot read link: %w", err)
+			}
+			if filepath.IsAbs(lnk) {
+				if lnk, err = filepath.Rel(filepath.Dir(p), lnk); err != nil {
+					return fmt.Errorf("cannot get relative path for symlink: %w", err)
+				}
+			}
+			t, err := filepath.Rel(root, filepath.Join(filepath.Dir(p), lnk))
+			if err != nil {
+				return fmt.Errorf("cannot get relative path for symlink: %w", err)
+			}
+			if strict && (strings.HasPrefix(t, up) || t == "..") {
+				return fmt.Errorf("link %q points outside source root", p)
+			}
+			e.Link = filepath.ToSlash(lnk)
+			e.Mode = fs.ModeSymlink | 0777
+		case fi.IsDir():
+		default:
+			// Sockets, devices and the like are not transferred
+			return nil
+		}
+		m[filepath.ToSlash(relp)] = e
+		return nil
+	})
+	return m, err
+}
+
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", fmt.Errorf("cannot open file: %w", err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("cannot read file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Diff returns the paths of local which are missing or different in remote,
+// and the paths of remote which are not in local, both in sorted order.
+func Diff(local, remote Manifest) (changed, removed []string) {
+	for p, l := range local {
+		if r, ok := remote[p]; !ok || r != l {
+			changed = append(changed, p)
+		}
+	}
+	for p := range remote {
+		if _, ok := local[p]; !ok {
+			removed = append(removed, p)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return
+}
+
+// Stats of a completed exchange.
+type Stats struct {
+	// Changed is the number of entries sent.
+	Changed int
+	// Removed is the number of entries removed.
+	Removed int
+	// Bytes is the size of the content of the files sent.
+	Bytes int64
+}
+
+// Send the tree at root, described by the local manifest, to the remote side
+// whose output is read from r and whose input is written to w.  The caller
+// closes w once Send returns.
+func Send(root string, local Manifest, r io.Reader, w io.Writer) (stats Stats, err error) {
+	if _, err = fmt.Fprintln(w, Hello); err != nil {
+		return stats, fmt.Errorf("cannot start sync: %w", err)
+	}
+
+	var remote Manifest
+	if err = json.NewDecoder(r).Decode(&remote); err != nil {
+		return stats, fmt.Errorf("cannot read remote manifest: %w", err)
+	}
+	// Drain any further output of the remote side such that it never blocks.
+	go func() { _, _ = io.Copy(io.Discard, r) }()
+
+	changed, removed := Diff(local, remote)
+	stats.Changed, stats.Removed = len(changed), len(removed)
+
+	bw := bufio.NewWriter(w)
+	if err = json.NewEncoder(bw).Encode(removed); err != nil {
+		return stats, fmt.Errorf("cannot write removed entries: %w", err)
+	}
+
+	tw := tar.NewWriter(bw)
+	for _, p := range changed {
+		e := local[p]
+		hdr := &tar.Header{
+			Name: p,
+			Mode: int64(e.Mode.Perm()),
+		}
+		switch {
+		case e.Mode.IsDir():
+			hdr.Typeflag = tar.TypeDir
+			hdr.Name += "/"
+		case e.Mode&fs.ModeSymlink != 0:
+			hdr.Typeflag = tar.TypeSymlink
+			hdr.Linkname = e.Link
+		default:
+			hdr.Typeflag = tar.TypeReg
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if err = sendFile(tw, hdr, filepath.Join(root, filepath.FromSlash(p))); err != nil {
+				return stats, err
+			}
+			stats.Bytes += hdr.Size
+			continue
+		}
+		if err = tw.WriteHeader(hdr); err != nil {
+			return stats, fmt.Errorf("cannot write header to tar stream: %w", err)
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return stats, fmt.Errorf("cannot finish tar stream: %w", err)
+	}
+	return stats, bw.Flush()
+}
+
+func sendFile(tw *tar.Writer, hdr *tar.Header, p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return fmt.Errorf("cannot open source file: %w", err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("cannot stat source file: %w", err)
+	}
+	hdr.Size = fi.Size()
+	if err = tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("cannot write header to tar stream: %w", err)
+	}
+	if _, err = io.CopyN(tw, f, hdr.Size); err != nil {
+		return fmt.Errorf("cannot copy source file content: %w", err)
+	}
+	return nil
+}
+
+// Receive is the remote side of an exchange, updating the tree at dir with
+// the input read from r after writing its manifest to w.  The directory is
+// created if it does not yet exist.
+func Receive(dir string, r io.Reader, w io.Writer) error {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return fmt.Errorf("cannot create directory: %w", err)
+	}
+
+	br := bufio.NewReader(r)
+	hello, err := br.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("cannot read hello: %w", err)
+	}
+	if strings.TrimSpace(hello) != Hello {
+		return fmt.Errorf("unexpected hello %q", strings.TrimSpace(hello))
+	}
+
+	// Builds may have left links of their own, these are not validated.
+	m, err := scan(dir, nil, false)
+	if err != nil {
+		return err
+	}
+	if err = json.NewEncoder(w).Encode(m); err != nil {
+		return fmt.Errorf("cannot write manifest: %w", err)
+	}
+
+	var removed []string
+	dec := json.NewDecoder(br)
+	if err = dec.Decode(&removed); err != nil {
+		return fmt.Errorf("cannot read removed entries: %w", err)
+	}
+	for _, p := range removed {
+		dst, err := destination(dir, p)
+		if err != nil {
+			return err
+		}
+		if err = os.RemoveAll(dst); err != nil {
+			return fmt.Errorf("cannot remove %q: %w", p, err)
+		}
+	}
+
+	// The decoder may have buffered the beginning of the tar stream, which
+	// follows the newline ending the removed entries.
+	rest := bufio.NewReader(io.MultiReader(dec.Buffered(), br))
+	if b, err := rest.ReadByte(); err == nil && b != '\n' {
+		_ = rest.UnreadByte()
+	}
+	return extract(rest, dir)
+}
+
+// destination returns the path within dir of the slash separated name,
+// ensuring it does not escape dir.
+func destination(dir, name string) (string, error) {
+	name = path.Clean(name)
+	if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") || name == "." {
+		return "", fmt.Errorf("invalid path %q", name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(name)), nil
+}
+
+// extract the entries of the tar stream into dir, replacing existing ones.
+func extract(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read tar stream: %w", err)
+		}
+		dst, err := destination(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		perm := fs.FileMode(hdr.Mode).Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if fi, err := os.Lstat(dst); err == nil && !fi.IsDir() {
+				if err = os.Remove(dst); err != nil {
+					return fmt.Errorf("cannot replace %q: %w", hdr.Name, err)
+				}
+			}
+			if err = os.MkdirAll(dst, perm); err != nil {
+				return fmt.Errorf("cannot create directory: %w", err)
+			}
+			err = os.Chmod(dst, perm)
+		case tar.TypeSymlink:
+			if path.IsAbs(hdr.Linkname) || strings.HasPrefix(path.Clean(path.Join(path.Dir(path.Clean(hdr.Name)), hdr.Linkname)), "..") {
+				return fmt.Errorf("link target escapes: %s->%s", hdr.Name, hdr.Linkname)
+			}
+			if err = replace(dst); err == nil {
+				err = os.Symlink(hdr.Linkname, dst)
+			}
+		case tar.TypeReg:
+			err = writeFile(tr, dst, perm)
+		default:
+			err = fmt.Errorf("unsupported type flag: %d", hdr.Typeflag)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot create %q: %w", hdr.Name, err)
+		}
+	}
+}
+
+// replace prepares dst to be replaced, ensuring its parent exists.
+func replace(dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dst); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeFile writes the content to a temporary file which then replaces dst,
+// such that dst is never left partially written.
+func writeFile(content io.Reader, dst string, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(dst), ".func-sync-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err = io.Copy(f, content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(f.Name(), perm); err != nil {
+		return err
+	}
+	if fi, err := os.Lstat(dst); err == nil && fi.IsDir() {
+		if err = os.RemoveAll(dst); err != nil {
+			return err
+		}
+	}
+	return os.Rename(f.Name(), dst)
+}
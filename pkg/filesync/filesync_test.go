@@ -0,0 +1,135 @@
+package filesync_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"knative.dev/func/pkg/filesync"
+)
+
+// TestSync ensures that a tree is mirrored to the remote side, sending only
+// the entries which changed and removing those which no longer exist.
+func TestSync(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links are not portable")
+	}
+	local, remote := t.TempDir(), filepath.Join(t.TempDir(), "source")
+
+	write(t, local, "a.txt", "a")
+	write(t, local, "dir/b.txt", "b")
+	write(t, local, "dir/sub/c.txt", "c")
+	write(t, local, "ignored.txt", "ignored")
+	if err := os.Symlink("dir/b.txt", filepath.Join(local, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Initial sync sends everything not ignored
+	stats := sync(t, local, remote)
+	if stats.Changed != 6 || stats.Removed != 0 {
+		t.Fatalf("unexpected initial sync %+v", stats)
+	}
+	assertContent(t, remote, "dir/sub/c.txt", "c")
+	if _, err := os.Stat(filepath.Join(remote, "ignored.txt")); !os.IsNotExist(err) {
+		t.Fatal("ignored file was sent")
+	}
+	if lnk, err := os.Readlink(filepath.Join(remote, "link")); err != nil || lnk != "dir/b.txt" {
+		t.Fatalf("unexpected link %q: %v", lnk, err)
+	}
+
+	// Unchanged trees send nothing
+	if stats = sync(t, local, remote); stats.Changed != 0 || stats.Removed != 0 {
+		t.Fatalf("expected nothing to be sent, got %+v", stats)
+	}
+
+	// Changes, removals and replacements of files by directories are mirrored
+	write(t, local, "a.txt", "a2")
+	if err := os.RemoveAll(filepath.Join(local, "dir", "sub")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(local, "link")); err != nil {
+		t.Fatal(err)
+	}
+	write(t, local, "link/d.txt", "d")
+
+	stats = sync(t, local, remote)
+	if stats.Changed != 3 || stats.Removed != 2 || stats.Bytes != 3 {
+		t.Fatalf("unexpected incremental sync %+v", stats)
+	}
+	assertContent(t, remote, "a.txt", "a2")
+	assertContent(t, remote, "dir/b.txt", "b")
+	assertContent(t, remote, "link/d.txt", "d")
+	if _, err := os.Stat(filepath.Join(remote, "dir", "sub")); !os.IsNotExist(err) {
+		t.Fatal("removed directory still exists")
+	}
+}
+
+// TestScan_LinkOutsideRoot ensures that links pointing outside of the tree are
+// rejected.
+func TestScan_LinkOutsideRoot(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links are not portable")
+	}
+	root := t.TempDir()
+	if err := os.Symlink("../outside", filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := filesync.Scan(root, nil); err == nil {
+		t.Fatal("expected an error for a link outside of root")
+	}
+}
+
+// sync the local tree to the remote one over a pair of pipes, as is done with
+// the streams of a pod.
+func sync(t *testing.T, local, remote string) filesync.Stats {
+	t.Helper()
+	m, err := filesync.Scan(local, func(p string) bool { return strings.HasPrefix(p, "ignored") })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := filesync.Receive(remote, inR, outW)
+		_ = inR.CloseWithError(err)
+		_ = outW.CloseWithError(err)
+		done <- err
+	}()
+
+	stats, err := filesync.Send(local, m, outR, inW)
+	_ = inW.CloseWithError(err)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+	return stats
+}
+
+func write(t *testing.T, root, name, content string) {
+	t.Helper()
+	p := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertContent(t *testing.T, root, name, content string) {
+	t.Helper()
+	bb, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bb) != content {
+		t.Fatalf("expected %q to contain %q, got %q", name, content, bb)
+	}
+}
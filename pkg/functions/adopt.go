@@ -0,0 +1,97 @@
+package functions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"knative.dev/func/pkg/scaffolding"
+)
+
+// adoptableRuntimes are those whose existing projects can be adopted.
+var adoptableRuntimes = []string{"go", "node", "python", "typescript"}
+
+// Adopt an existing project as a function by writing its func.yaml and the
+// run data directory (.func), which is added to .gitignore, leaving the
+// project's code untouched.
+//
+// The runtime, if not provided, is inferred from the project's files (e.g.
+// go.mod or package.json), as is the invocation hint ("http" or
+// "cloudevent").  The project's source must implement a function handler
+// signature as detected by the runtime's scaffolding.  Build and deploy
+// defaults are those of the runtime's template in the default repository.
+func (c *Client) Adopt(cfg Function) (f Function, err error) {
+	oldRoot := cfg.Root
+	if cfg.Root, err = filepath.Abs(cfg.Root); err != nil {
+		return cfg, err
+	}
+	if fi, err := os.Stat(cfg.Root); err != nil {
+		return cfg, err
+	} else if !fi.IsDir() {
+		return cfg, fmt.Errorf("%v is not a directory", cfg.Root)
+	}
+	if initialized, err := hasInitializedFunction(cfg.Root); err != nil {
+		return cfg, err
+	} else if initialized {
+		return cfg, fmt.Errorf("function at '%v' already initialized", cfg.Root)
+	}
+	if cfg.Name == "" {
+		cfg.Name = nameFromPath(cfg.Root)
+	}
+
+	// Runtime and invocation hint
+	if cfg.Runtime == "" {
+		if cfg.Runtime, err = scaffolding.DetectRuntime(cfg.Root); err != nil {
+			return cfg, fmt.Errorf("unable to adopt %v. %w", cfg.Root, err)
+		}
+	}
+	if !contains(adoptableRuntimes, cfg.Runtime) {
+		return cfg, fmt.Errorf("%v projects can not be adopted. Supported runtimes are %v", cfg.Runtime, adoptableRuntimes)
+	}
+	invoke := cfg.Invoke
+	if invoke == "" {
+		if invoke, err = scaffolding.DetectInvoke(cfg.Root, cfg.Runtime); err != nil {
+			return cfg, err
+		}
+	}
+
+	// The project must implement a handler.  Runtimes whose signatures can
+	// not yet be detected are verified when built.
+	var notImplemented scaffolding.ErrDetectorNotImplemented
+	if _, err = scaffolding.Detect(cfg.Root, cfg.Runtime, invoke); err != nil && !errors.As(err, &notImplemented) {
+		return cfg, fmt.Errorf("no function handler found in %v. %w", cfg.Root, err)
+	}
+
+	// Defaults from the corresponding template
+	if cfg.Template == "" {
+		cfg.Template = DefaultTemplate
+		if invoke == "cloudevent" {
+			cfg.Template = "cloudevents"
+		}
+	}
+	if invoke != DefaultInvocationFormat {
+		cfg.Invoke = invoke
+	}
+	f = NewFunctionWith(cfg)
+	f.SpecVersion = LastSpecVersion()
+	t, err := c.Templates().Get(f.Runtime, f.Template)
+	if err != nil {
+		return f, err
+	}
+	if t, ok := t.(template); ok {
+		t.configure(&f)
+	}
+
+	// Create a .func directory which is also added to a .gitignore
+	if err = ensureRunDataDir(f.Root); err != nil {
+		return f, err
+	}
+
+	f.Created = time.Now()
+	if err = f.Write(); err != nil {
+		return f, err
+	}
+	return NewFunction(oldRoot)
+}
@@ -0,0 +1,132 @@
+//go:build !integration
+// +build !integration
+
+package functions_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	fn "knative.dev/func/pkg/functions"
+)
+
+// TestAdopt ensures that an existing project can be adopted as a function,
+// with its runtime and invocation hint inferred and its files untouched.
+func TestAdopt(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "existing")
+	files := map[string]string{
+		"go.mod":    "module existing\n",
+		"handle.go": "package existing\n\nimport _ \"github.com/cloudevents/sdk-go/v2\"\n\nfunc Handle() {}\n",
+		"README.md": "existing project\n",
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := fn.New()
+	f, err := client.Adopt(fn.Function{Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name != "existing" || f.Runtime != "go" || f.Invoke != "cloudevent" {
+		t.Fatalf("unexpected function name %q runtime %q invoke %q", f.Name, f.Runtime, f.Invoke)
+	}
+
+	// Only func.yaml (and the ignored run data directory) is written
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if _, ok := files[e.Name()]; !ok && e.Name() != fn.FunctionFile && e.Name() != fn.RunDataDir && e.Name() != ".gitignore" {
+			t.Fatalf("unexpected file written: %v", e.Name())
+		}
+	}
+	bb, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bb), "/"+fn.RunDataDir) {
+		t.Fatalf("expected .gitignore to ignore %v, got:\n%s", fn.RunDataDir, bb)
+	}
+	for name, content := range files {
+		bb, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(bb) != content {
+			t.Fatalf("expected %v to be untouched", name)
+		}
+	}
+
+	// An initialized function can not be adopted again
+	if _, err = client.Adopt(fn.Function{Root: root}); err == nil {
+		t.Fatal("expected adopting an initialized function to error")
+	}
+}
+
+// TestAdopt_TypeScript ensures a TypeScript project, recognized by its
+// tsconfig.json, is adopted with the typescript runtime.
+func TestAdopt_TypeScript(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"package.json":  "{\"name\": \"existing\"}\n",
+		"tsconfig.json": "{}\n",
+		"src/index.ts":  "export function handle(context: any, body: any) { return body }\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := fn.New().Adopt(fn.Function{Root: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Runtime != "typescript" {
+		t.Fatalf("expected runtime typescript, got %q", f.Runtime)
+	}
+}
+
+// TestAdopt_Errors ensures projects which are not recognized, or which do
+// not implement a handler, are not adopted.
+func TestAdopt_Errors(t *testing.T) {
+	client := fn.New()
+
+	// No project files
+	root := t.TempDir()
+	if _, err := client.Adopt(fn.Function{Root: root}); err == nil {
+		t.Fatal("expected an unrecognized project to error")
+	}
+
+	// No handler
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module f\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Adopt(fn.Function{Root: root}); err == nil {
+		t.Fatal("expected a project without a handler to error")
+	}
+	if _, err := os.Stat(filepath.Join(root, fn.FunctionFile)); !os.IsNotExist(err) {
+		t.Fatalf("expected no func.yaml to be written. %v", err)
+	}
+
+	// Runtimes which can not be adopted
+	if _, err := client.Adopt(fn.Function{Root: root, Runtime: "rust"}); err == nil {
+		t.Fatal("expected an unsupported runtime to error")
+	}
+}
@@ -61,24 +61,25 @@ type Platform struct {
 
 // Client for managing function instances.
 type Client struct {
-	repositoriesPath  string            // path to repositories
-	repositoriesURI   string            // repo URI (overrides repositories path)
-	verbose           bool              // print verbose logs
-	builder           Builder           // Builds a runnable image source
-	pusher            Pusher            // Pushes function image to a remote
-	deployer          Deployer          // Deploys or Updates a function
-	runner            Runner            // Runs the function locally
-	remover           Remover           // Removes remote services
-	lister            Lister            // Lists remote services
-	describer         Describer         // Describes function instances
-	dnsProvider       DNSProvider       // Provider of DNS services
-	registry          string            // default registry for OCI image tags
-	repositories      *Repositories     // Repositories management
-	templates         *Templates        // Templates management
-	instances         *InstanceRefs     // Function Instances management
-	transport         http.RoundTripper // Customizable internal transport
-	pipelinesProvider PipelinesProvider // CI/CD pipelines management
-	startTimeout      time.Duration     // default start timeout for all runs
+	repositoriesPath  string                           // path to repositories
+	repositoriesURI   string                           // repo URI (overrides repositories path)
+	verbose           bool                             // print verbose logs
+	builder           Builder                          // Builds a runnable image source
+	pusher            Pusher                           // Pushes function image to a remote
+	deployer          Deployer                         // Deploys or Updates a function
+	runner            Runner                           // Runs the function locally
+	remover           Remover                          // Removes remote services
+	lister            Lister                           // Lists remote services
+	describer         Describer                        // Describes function instances
+	dnsProvider       DNSProvider                      // Provider of DNS services
+	registry          string                           // default registry for OCI image tags
+	repositories      *Repositories                    // Repositories management
+	templates         *Templates                       // Templates management
+	instances         *InstanceRefs                    // Function Instances management
+	transport         http.RoundTripper                // Customizable internal transport
+	pipelinesProvider PipelinesProvider                // CI/CD pipelines management
+	startTimeout      time.Duration                    // default start timeout for all runs
+	secretResolvers   map[string]SecretResolverFactory // Resolvers of secrets for local runs
 }
 
 // Builder of function source to runnable image.
@@ -181,6 +182,23 @@ type Instance struct {
 	Image         string         `json:"image" yaml:"image"`
 	Namespace     string         `json:"namespace" yaml:"namespace"`
 	Subscriptions []Subscription `json:"subscriptions" yaml:"subscriptions"`
+	// LastBuild is the outcome of the newest on-cluster build of the
+	// function, if known.
+	LastBuild *BuildStatus `json:"lastBuild,omitempty" yaml:"lastBuild,omitempty"`
+}
+
+// BuildStatus is the outcome of an on-cluster build of a function.
+type BuildStatus struct {
+	// Succeeded is true if the function was built and deployed.
+	Succeeded bool `json:"succeeded" yaml:"succeeded"`
+	// Reason is a short CamelCase reason of the outcome, ex: "BuildFailed".
+	Reason  string `json:"reason" yaml:"reason"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// PipelineRun is the name of the PipelineRun which built the function.
+	PipelineRun string `json:"pipelineRun,omitempty" yaml:"pipelineRun,omitempty"`
+	// GitRevision is the revision of the sources the function was built from.
+	GitRevision string    `json:"gitRevision,omitempty" yaml:"gitRevision,omitempty"`
+	Time        time.Time `json:"time" yaml:"time"`
 }
 
 // Subscriptions currently active to event sources
@@ -199,9 +217,63 @@ type DNSProvider interface {
 // PipelinesProvider manages lifecyle of CI/CD pipelines used by a function
 type PipelinesProvider interface {
 	Run(context.Context, Function) (string, Function, error)
+	// Rerun the newest pipeline run of the function using the sources it
+	// was run with, rather than uploading them again.
+	Rerun(context.Context, Function) (string, Function, error)
 	Remove(context.Context, Function) error
+	// Generate writes the pipeline resources of the function into the given
+	// directory to be applied by other means, returning the written files.
+	Generate(context.Context, Function, string) ([]string, error)
 	ConfigurePAC(context.Context, Function, any) error
 	RemovePAC(context.Context, Function, any) error
+	// ListRuns returns the pipeline runs of the function, newest first.
+	ListRuns(context.Context, Function) ([]PipelineRun, error)
+	// DescribeRun returns the named pipeline run of the function including
+	// its tasks.  An empty name describes the newest run.
+	DescribeRun(context.Context, Function, string) (PipelineRun, error)
+	// RunLogs returns the logs of the given task of the named pipeline run.
+	// An empty name selects the newest run; an empty task selects all tasks,
+	// or only the failure if the run has failed.
+	RunLogs(ctx context.Context, f Function, name, task string) (string, error)
+}
+
+// PipelineRun is a single run of the CI/CD pipeline of a function.
+type PipelineRun struct {
+	Name      string            `json:"name" yaml:"name"`
+	Namespace string            `json:"namespace" yaml:"namespace"`
+	Status    string            `json:"status" yaml:"status"`
+	Message   string            `json:"message,omitempty" yaml:"message,omitempty"`
+	Started   time.Time         `json:"started,omitempty" yaml:"started,omitempty"`
+	Completed time.Time         `json:"completed,omitempty" yaml:"completed,omitempty"`
+	Tasks     []PipelineTaskRun `json:"tasks,omitempty" yaml:"tasks,omitempty"`
+}
+
+// Duration of the run, up until now if it has not yet completed.
+func (r PipelineRun) Duration() time.Duration {
+	return runDuration(r.Started, r.Completed)
+}
+
+// PipelineTaskRun is the run of a single task of a PipelineRun.
+type PipelineTaskRun struct {
+	Name      string    `json:"name" yaml:"name"`
+	Status    string    `json:"status" yaml:"status"`
+	Started   time.Time `json:"started,omitempty" yaml:"started,omitempty"`
+	Completed time.Time `json:"completed,omitempty" yaml:"completed,omitempty"`
+}
+
+// Duration of the task, up until now if it has not yet completed.
+func (r PipelineTaskRun) Duration() time.Duration {
+	return runDuration(r.Started, r.Completed)
+}
+
+func runDuration(started, completed time.Time) time.Duration {
+	if started.IsZero() {
+		return 0
+	}
+	if completed.IsZero() {
+		return time.Since(started)
+	}
+	return completed.Sub(started)
 }
 
 // New client for function management.
@@ -218,6 +290,7 @@ func New(options ...Option) *Client {
 		pipelinesProvider: &noopPipelinesProvider{},
 		transport:         http.DefaultTransport,
 		startTimeout:      DefaultStartTimeout,
+		secretResolvers:   defaultSecretResolvers(),
 	}
 	c.runner = newDefaultRunner(c, os.Stdout, os.Stderr)
 	for _, o := range options {
@@ -542,9 +615,13 @@ func (c *Client) New(ctx context.Context, cfg Function) (string, Function, error
 // <name> will default to the current working directory.
 // When <name> is provided but <path> is not, a directory <name> is created
 // in the current working directory and used for <path>.
-func (c *Client) Init(cfg Function) (Function, error) {
+func (c *Client) Init(cfg Function, options ...InitOption) (_ Function, err error) {
+	oo := InitOptions{}
+	for _, o := range options {
+		o(&oo)
+	}
+
 	// convert Root path to absolute
-	var err error
 	oldRoot := cfg.Root
 	cfg.Root, err = filepath.Abs(cfg.Root)
 	cfg.SpecVersion = LastSpecVersion()
@@ -552,6 +629,12 @@ func (c *Client) Init(cfg Function) (Function, error) {
 		return cfg, err
 	}
 
+	// Record the state of the root prior to initialization
+	snapshot, err := snapshotRoot(cfg.Root)
+	if err != nil {
+		return cfg, err
+	}
+
 	// Create project root directory, if it doesn't already exist
 	if err = os.MkdirAll(cfg.Root, 0755); err != nil {
 		return cfg, err
@@ -587,6 +670,16 @@ func (c *Client) Init(cfg Function) (Function, error) {
 	// Create a new function (in memory)
 	f := NewFunctionWith(cfg)
 
+	// Should any of the following fail, everything written is removed such
+	// that a partially initialized function is not left behind.
+	defer func() {
+		if err != nil {
+			if rerr := snapshot.restore(); rerr != nil {
+				fmt.Fprintf(os.Stderr, "warning: unable to remove partially initialized function. %v\n", rerr)
+			}
+		}
+	}()
+
 	// Create a .func diretory which is also added to a .gitignore
 	if err = ensureRunDataDir(f.Root); err != nil {
 		return f, err
@@ -609,6 +702,12 @@ func (c *Client) Init(cfg Function) (Function, error) {
 	if err != nil {
 		return f, err
 	}
+
+	// Run the template's post-create hooks
+	if err = c.runPostCreateHooks(context.TODO(), f, oo); err != nil {
+		return f, err
+	}
+
 	// Load the now-initialized function.
 	return NewFunction(oldRoot)
 }
@@ -693,7 +792,8 @@ func (c *Client) Scaffold(ctx context.Context, f Function, dest string) (err err
 	if err != nil {
 		return
 	}
-	return scaffolding.Write(dest, f.Root, f.Runtime, f.Invoke, repo.FS())
+	return scaffolding.Write(dest, f.Root, f.Runtime, f.Invoke, repo.FS(),
+		scaffolding.WithHealthEndpoints(f.Deploy.HealthEndpoints.Readiness, f.Deploy.HealthEndpoints.Liveness))
 }
 
 // printBuildActivity is a helper for ensuring the user gets feedback from
@@ -824,6 +924,24 @@ func (c *Client) RunPipeline(ctx context.Context, f Function) (string, Function,
 	return c.pipelinesProvider.Run(ctx, f)
 }
 
+// RerunPipeline runs the last Pipeline run of the function again, reusing the
+// sources it was run with.  Returned are the default route and the function
+// as with RunPipeline.
+func (c *Client) RerunPipeline(ctx context.Context, f Function) (string, Function, error) {
+	return c.pipelinesProvider.Rerun(ctx, f)
+}
+
+// GeneratePipeline writes the resources of the Pipeline which builds and
+// deploys the function into dir, such that they can be applied by other
+// tooling, for instance with GitOps.  Returned are the written files.
+func (c *Client) GeneratePipeline(ctx context.Context, f Function, dir string) ([]string, error) {
+	// Default function registry to the client's global registry
+	if f.Registry == "" {
+		f.Registry = c.registry
+	}
+	return c.pipelinesProvider.Generate(ctx, f, dir)
+}
+
 // ConfigurePAC generates Pipeline resources on the local filesystem,
 // on the cluster and also on the remote git provider (ie. GitHub, GitLab or BitBucket repo)
 func (c *Client) ConfigurePAC(ctx context.Context, f Function, metadata any) error {
@@ -858,6 +976,24 @@ func (c *Client) ConfigurePAC(ctx context.Context, f Function, metadata any) err
 	return nil
 }
 
+// PipelineRuns returns the runs of the function's pipeline, newest first.
+func (c *Client) PipelineRuns(ctx context.Context, f Function) ([]PipelineRun, error) {
+	return c.pipelinesProvider.ListRuns(ctx, f)
+}
+
+// DescribePipelineRun returns the named run of the function's pipeline,
+// including its tasks.  An empty name describes the newest run.
+func (c *Client) DescribePipelineRun(ctx context.Context, f Function, name string) (PipelineRun, error) {
+	return c.pipelinesProvider.DescribeRun(ctx, f, name)
+}
+
+// PipelineRunLogs returns the logs of a task of the named run of the
+// function's pipeline.  An empty name selects the newest run and an empty
+// task selects all tasks (or only the failure of a failed run).
+func (c *Client) PipelineRunLogs(ctx context.Context, f Function, name, task string) (string, error) {
+	return c.pipelinesProvider.RunLogs(ctx, f, name, task)
+}
+
 // RemovePAC deletes generated Pipeline as Code resources on the local filesystem and on the cluster
 func (c *Client) RemovePAC(ctx context.Context, f Function, metadata any) error {
 
@@ -940,6 +1076,12 @@ func (c *Client) Run(ctx context.Context, f Function, options ...RunOption) (job
 		timeout = oo.StartTimeout
 	}
 
+	// Resolve references to secrets such that the function is run with the
+	// same effective environment as when deployed.
+	if f.Run.Envs, err = c.ResolveEnvs(ctx, f); err != nil {
+		return
+	}
+
 	// Run the function, which returns a Job for use interacting (at arms length)
 	// with that running task (which is likely inside a container process).
 	if job, err = c.runner.Run(ctx, f, timeout); err != nil {
@@ -1071,6 +1213,20 @@ func (c *Client) Invoke(ctx context.Context, root string, target string, m Invok
 	return invoke(ctx, c, f, target, m, c.verbose)
 }
 
+// InvokeLoad sends the invocation message repeatedly to the target instance
+// of the function at root, as a quick check of its latency and throughput.
+// The target is chosen as with Invoke.  The number of requests, concurrency
+// and duration of the run are defined by the load options.
+// Returned is a summary of the latency distribution, status codes and errors.
+func (c *Client) InvokeLoad(ctx context.Context, root string, target string, m InvokeMessage, o LoadOptions) (LoadResult, error) {
+	f, err := NewFunction(root)
+	if err != nil {
+		return LoadResult{}, err
+	}
+	// See invoke_load.go for implementation details
+	return invokeLoad(ctx, c, f, target, m, o)
+}
+
 // Push the image for the named service to the configured registry
 // returns in this order: 1)Function structure 2)bool indicating if push succeeded
 // 3) error
@@ -1357,13 +1513,28 @@ type noopPipelinesProvider struct{}
 func (n *noopPipelinesProvider) Run(ctx context.Context, f Function) (string, Function, error) {
 	return "", f, nil
 }
+func (n *noopPipelinesProvider) Rerun(ctx context.Context, f Function) (string, Function, error) {
+	return "", f, nil
+}
 func (n *noopPipelinesProvider) Remove(ctx context.Context, _ Function) error { return nil }
+func (n *noopPipelinesProvider) Generate(ctx context.Context, _ Function, _ string) ([]string, error) {
+	return nil, nil
+}
 func (n *noopPipelinesProvider) ConfigurePAC(ctx context.Context, _ Function, _ any) error {
 	return nil
 }
 func (n *noopPipelinesProvider) RemovePAC(ctx context.Context, _ Function, _ any) error {
 	return nil
 }
+func (n *noopPipelinesProvider) ListRuns(ctx context.Context, _ Function) ([]PipelineRun, error) {
+	return nil, nil
+}
+func (n *noopPipelinesProvider) DescribeRun(ctx context.Context, _ Function, _ string) (PipelineRun, error) {
+	return PipelineRun{}, nil
+}
+func (n *noopPipelinesProvider) RunLogs(ctx context.Context, _ Function, _, _ string) (string, error) {
+	return "", nil
+}
 
 // DNSProvider
 type noopDNSProvider struct{ output io.Writer }
@@ -2197,3 +2197,61 @@ func TestClient_BuildPopulatesRuntimeImage(t *testing.T) {
 		t.Fatalf("written image in ./.func/built-image '%s' does not match expected '%s'", got, expect)
 	}
 }
+
+// TestClient_InvokeLoad ensures that load generation sends the requested
+// number of invocations and summarizes the status codes received.
+func TestClient_InvokeLoad(t *testing.T) {
+	root, cleanup := Mktemp(t)
+	defer cleanup()
+
+	// A handler which fails every fourth request
+	var count int32
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&count, 1)%4 == 0 {
+			res.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = res.Write([]byte("OK"))
+	})
+	l, err := net.Listen("tcp4", "127.0.0.1:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := http.Server{Handler: handler}
+	go func() {
+		if err = s.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "error serving: %v", err)
+		}
+	}()
+	t.Cleanup(func() { _ = s.Close() })
+
+	client := fn.New()
+	if _, err := client.Init(fn.Function{Runtime: TestRuntime, Root: root}); err != nil {
+		t.Fatal(err)
+	}
+
+	target := "http://" + l.Addr().String()
+	result, err := client.InvokeLoad(context.Background(), root, target, fn.NewInvokeMessage(),
+		fn.LoadOptions{Requests: 20, Concurrency: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Requests != 20 {
+		t.Fatalf("expected 20 requests, got %v", result.Requests)
+	}
+	if result.StatusCodes[http.StatusOK] != 15 || result.StatusCodes[http.StatusInternalServerError] != 5 {
+		t.Fatalf("unexpected status code distribution: %v", result.StatusCodes)
+	}
+	if result.Errors != 5 {
+		t.Fatalf("expected 5 errors, got %v", result.Errors)
+	}
+	if result.Latency.P50 > result.Latency.P99 || result.Latency.Min > result.Latency.Max {
+		t.Fatalf("inconsistent latency distribution: %+v", result.Latency)
+	}
+
+	// Neither a number of requests nor a duration is an error
+	if _, err = client.InvokeLoad(context.Background(), root, target, fn.NewInvokeMessage(), fn.LoadOptions{}); err == nil {
+		t.Fatal("expected an error when neither requests nor duration are provided")
+	}
+}
@@ -9,6 +9,7 @@ import (
 var (
 	ErrEnvironmentNotFound       = errors.New("environment not found")
 	ErrFunctionNotFound          = errors.New("function not found")
+	ErrHooksNotTrusted           = errors.New("template declares post-create hooks which are not trusted")
 	ErrMismatchedName            = errors.New("name passed the function source")
 	ErrNameRequired              = errors.New("name required")
 	ErrNamespaceRequired         = errors.New("namespace required")
@@ -16,9 +17,11 @@ var (
 	ErrNotRunning                = errors.New("function not running")
 	ErrRepositoriesNotDefined    = errors.New("custom template repositories location not specified")
 	ErrRepositoryNotFound        = errors.New("repository not found")
+	ErrRepositoryPinned          = errors.New("repository is pinned")
 	ErrRootRequired              = errors.New("function root path is required")
 	ErrRuntimeNotFound           = errors.New("language runtime not found")
 	ErrRuntimeRequired           = errors.New("language runtime required")
+	ErrSecretNotFound            = errors.New("secret not found")
 	ErrTemplateMissingRepository = errors.New("template name missing repository prefix")
 	ErrTemplateNotFound          = errors.New("template not found")
 	ErrTemplatesNotFound         = errors.New("templates path (runtimes) not found")
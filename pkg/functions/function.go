@@ -69,6 +69,10 @@ type Function struct {
 	// Template for the function.
 	Template string `yaml:"-"`
 
+	// TemplateParams are the values of the parameters declared by the
+	// Template, used when the function is initialized.
+	TemplateParams map[string]string `yaml:"-"`
+
 	// Registry at which to store interstitial containers, in the form
 	// [registry]/[user].
 	Registry string `yaml:"registry,omitempty"`
@@ -105,7 +109,16 @@ type Function struct {
 	// Deploy defines the deployment properties for a function
 	Deploy DeploySpec `yaml:"deploy,omitempty"`
 
+	// Environments are named deployment targets (eg. "staging") which
+	// override select values of the function when deployed to them.
+	Environments map[string]Environment `yaml:"environments,omitempty"`
+
 	Local Local `yaml:"-"`
+
+	// Environment is the named environment the function is configured for
+	// (see ForEnvironment), whose build state is kept separately.  It is not
+	// persisted.
+	Environment string `yaml:"-"`
 }
 
 // KnativeSubscription
@@ -145,6 +158,16 @@ type BuildSpec struct {
 	// on-cluster during when built remotely.
 	RemoteStorageClass string `yaml:"remoteStorageClass,omitempty"`
 
+	// Cache configures how the build cache of remote builds is persisted
+	// between pipeline runs.
+	Cache BuildCache `yaml:"cache,omitempty"`
+
+	// TaskBundle is the Tekton bundle (OCI image) with the Tasks used by
+	// remote builds, overriding the bundle of the func release.  It allows to
+	// use a bundle mirrored into the registry of a disconnected cluster.
+	// The bundle must be referenced by digest.
+	TaskBundle string `yaml:"taskBundle,omitempty"`
+
 	// Image stores last built image name NOT in func.yaml, but instead
 	// in .func/built-image
 	Image string `yaml:"-"`
@@ -153,6 +176,17 @@ type BuildSpec struct {
 	Mounts []MountSpec `yaml:"volumes,omitempty"`
 }
 
+// BuildCache configures the persistent build cache of remote builds.  The
+// cache is always kept on the volume of the pipeline (see PVCSize), where it
+// persists across runs of the pipeline.  The volume is specific to the source
+// of the build, Git or uploaded, such that each has its own cache.
+type BuildCache struct {
+	// Image, when set, is the image the pack builder exports the build cache
+	// to and restores it from, using the registry credentials of the pipeline.
+	// Unlike the volume, it is not removed when the function is deleted.
+	Image string `yaml:"image,omitempty"`
+}
+
 type MountSpec struct {
 	// Path on the local machine
 	Source string `yaml:"hostPath"`
@@ -168,6 +202,10 @@ type RunSpec struct {
 	// Env variables to be set
 	Envs Envs `yaml:"envs,omitempty"`
 
+	// SecretResolvers used in order to resolve references to secrets in
+	// Envs when running the function locally.
+	SecretResolvers []SecretResolverSpec `yaml:"secretResolvers,omitempty"`
+
 	// StartTimeout specifies that this function should have a custom timeout
 	// when starting. This setting is currently respected by the host runner,
 	// with containerized docker runner and deployed Knative service integration
@@ -203,6 +241,10 @@ type DeploySpec struct {
 	ServiceAccountName string `yaml:"serviceAccountName,omitempty"`
 
 	Subscriptions []KnativeSubscription `yaml:"subscriptions,omitempty"`
+
+	// Environments records the deployed state of the function in each of
+	// its named environments.
+	Environments map[string]DeployedEnvironment `yaml:"environments,omitempty"`
 }
 
 // HealthEndpoints specify the liveness and readiness endpoints for a Runtime
@@ -328,6 +370,8 @@ func (f Function) Validate() error {
 		validateOptions(f.Deploy.Options),
 		ValidateLabels(f.Deploy.Labels),
 		validateGit(f.Build.Git),
+		validateEnvironments(f.Environments),
+		validateSecretResolvers(f.Run.SecretResolvers),
 	}
 
 	var b strings.Builder
@@ -514,6 +558,9 @@ func (f Function) Stamp(oo ...stampOption) (err error) {
 	if err = ensureRunDataDir(f.Root); err != nil {
 		return
 	}
+	if err = os.MkdirAll(filepath.Dir(f.runDataPath(BuiltHash)), os.ModePerm); err != nil {
+		return
+	}
 
 	// Cacluate the hash and a logfile of what comprised it
 	var hash, log string
@@ -522,7 +569,7 @@ func (f Function) Stamp(oo ...stampOption) (err error) {
 	}
 
 	// Write out the hash
-	if err = os.WriteFile(filepath.Join(f.Root, RunDataDir, BuiltHash), []byte(hash), os.ModePerm); err != nil {
+	if err = os.WriteFile(f.runDataPath(BuiltHash), []byte(hash), os.ModePerm); err != nil {
 		return
 	}
 
@@ -531,7 +578,7 @@ func (f Function) Stamp(oo ...stampOption) (err error) {
 	if options.journal {
 		logfileName = timestamp(logfileName)
 	}
-	logfile, err := os.Create(filepath.Join(f.Root, RunDataDir, logfileName))
+	logfile, err := os.Create(f.runDataPath(logfileName))
 	if err != nil {
 		return
 	}
@@ -710,7 +757,7 @@ func (f Function) Built() bool {
 // BuildStamp accesses the current (last) build stamp for the function.
 // Unbuilt functions return empty string.
 func (f Function) BuildStamp() string {
-	path := filepath.Join(f.Root, RunDataDir, BuiltHash)
+	path := f.runDataPath(BuiltHash)
 	if _, err := os.Stat(path); err != nil {
 		return ""
 	}
@@ -744,12 +791,15 @@ func (f Function) newLocal() (localConfig Local, err error) {
 // WriteRuntimeBuiltImage writes built image name into runtime metadata
 // directory (.func/) from f.Build.Image
 func (f Function) WriteRuntimeBuiltImage(verbose bool) error {
-	path := filepath.Join(f.Root, RunDataDir, BuiltImage)
+	path := f.runDataPath(BuiltImage)
 
 	// dont write if empty (not built)
 	if f.Build.Image == "" {
 		return nil
 	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
 
 	if verbose {
 		fmt.Printf("Writing built image: '%s' at path: '%s'\n", f.Build.Image, path)
@@ -758,10 +808,21 @@ func (f Function) WriteRuntimeBuiltImage(verbose bool) error {
 	return os.WriteFile(path, []byte(f.Build.Image), os.ModePerm)
 }
 
+// runDataPath returns the path of the named file of the runtime metadata
+// directory (.func/).  The build state of a function configured for a named
+// environment is kept in a directory of the environment, such that building
+// for one environment does not affect the build state of the others.
+func (f Function) runDataPath(name string) string {
+	if f.Environment != "" {
+		return filepath.Join(f.Root, RunDataDir, "environments", f.Environment, name)
+	}
+	return filepath.Join(f.Root, RunDataDir, name)
+}
+
 // getLastBuiltImage reads .func/built-image and returns its value or empty string
 // if the file doesnt exist (not built yet). Other errors are returned as usual.
 func (f Function) getLastBuiltImage() (string, error) {
-	path := filepath.Join(f.Root, RunDataDir, BuiltImage)
+	path := f.runDataPath(BuiltImage)
 	if _, err := os.Stat(path); err != nil {
 		if os.IsNotExist(err) {
 			return "", nil
@@ -0,0 +1,176 @@
+package functions
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Environment defines the overrides applied to a function when it is
+// deployed to a named environment such as "staging" or "prod".  Values which
+// are not defined are inherited from the function.
+type Environment struct {
+	// Namespace into which the function is deployed for this environment.
+	// Required, as environments of a function are distinguished by namespace.
+	Namespace string `yaml:"namespace"`
+
+	// Registry to use for this environment in place of the function's.
+	Registry string `yaml:"registry,omitempty"`
+
+	// Domain to use for this environment in place of the function's.
+	Domain string `yaml:"domain,omitempty"`
+
+	// Envs are merged with the function's environment variables, with those
+	// defined here taking precedence.
+	Envs Envs `yaml:"envs,omitempty"`
+
+	// Scale options to use for this environment in place of the function's.
+	Scale *ScaleOptions `yaml:"scale,omitempty"`
+}
+
+// DeployedEnvironment records the deployed state of a function in a named
+// environment.  It is the per-environment analog of DeploySpec's Namespace
+// and Image.
+type DeployedEnvironment struct {
+	// Namespace into which the function was deployed.
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// Image is the deployed image including sha256
+	Image string `yaml:"image,omitempty"`
+}
+
+// EnvironmentNames returns the sorted names of the function's named
+// environments.
+func (f Function) EnvironmentNames() []string {
+	names := make([]string, 0, len(f.Environments))
+	for name := range f.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ForEnvironment returns the function as configured for the named
+// environment: the environment's overrides are applied and the deployed
+// state (.Deploy.Namespace and .Deploy.Image) is that of the environment, as
+// is the build state (.Build.Image and the build stamp, see Stamp).
+// The empty name and EnvironmentRemote refer to the function itself.
+func (f Function) ForEnvironment(name string) (Function, error) {
+	if name == "" || name == EnvironmentRemote {
+		return f, nil
+	}
+	if name == EnvironmentLocal {
+		return f, fmt.Errorf("the %q environment can not be used for deployments", name)
+	}
+	e, ok := f.Environments[name]
+	if !ok {
+		return f, fmt.Errorf("%w: %q. Defined environments are %v", ErrEnvironmentNotFound, name, f.EnvironmentNames())
+	}
+	if e.Namespace == "" {
+		return f, fmt.Errorf("environment %q does not define a namespace", name)
+	}
+
+	f.Namespace = e.Namespace
+	if e.Registry != "" {
+		f.Registry = e.Registry
+	}
+	if e.Domain != "" {
+		f.Domain = e.Domain
+	}
+	if e.Scale != nil {
+		f.Deploy.Options.Scale = e.Scale
+	}
+	f.Run.Envs = mergeEnvironmentEnvs(f.Run.Envs, e.Envs)
+
+	deployed := f.Deploy.Environments[name]
+	f.Deploy.Namespace = deployed.Namespace
+	f.Deploy.Image = deployed.Image
+
+	// The build state is that of the environment
+	f.Environment = name
+	if f.Root != "" {
+		var err error
+		if f.Build.Image, err = f.getLastBuiltImage(); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+// WithEnvironmentDeployment returns the function with the deployed state of
+// the given function (as returned from deploying a function configured via
+// ForEnvironment) recorded for the named environment.
+func (f Function) WithEnvironmentDeployment(name string, deployed Function) Function {
+	if name == "" || name == EnvironmentRemote {
+		f.Deploy.Namespace = deployed.Deploy.Namespace
+		f.Deploy.Image = deployed.Deploy.Image
+		return f
+	}
+	environments := make(map[string]DeployedEnvironment, len(f.Deploy.Environments)+1)
+	for k, v := range f.Deploy.Environments {
+		environments[k] = v
+	}
+	environments[name] = DeployedEnvironment{
+		Namespace: deployed.Deploy.Namespace,
+		Image:     deployed.Deploy.Image,
+	}
+	f.Deploy.Environments = environments
+	return f
+}
+
+// mergeEnvironmentEnvs returns the base envs with those of the overrides
+// replacing any of the same name, and the remainder appended.
+func mergeEnvironmentEnvs(base, overrides Envs) Envs {
+	merged := make(Envs, 0, len(base)+len(overrides))
+	replaced := make(map[string]bool)
+	for _, e := range base {
+		if e.Name != nil {
+			if o, ok := findEnv(overrides, *e.Name); ok {
+				merged = append(merged, o)
+				replaced[*e.Name] = true
+				continue
+			}
+		}
+		merged = append(merged, e)
+	}
+	for _, o := range overrides {
+		if o.Name == nil || !replaced[*o.Name] {
+			merged = append(merged, o)
+		}
+	}
+	return merged
+}
+
+func findEnv(ee Envs, name string) (Env, bool) {
+	for _, e := range ee {
+		if e.Name != nil && *e.Name == name {
+			return e, true
+		}
+	}
+	return Env{}, false
+}
+
+// validateEnvironments checks that the named environments are correctly set.
+// Returns array of error messages, empty if no errors are found
+func validateEnvironments(environments map[string]Environment) (errors []string) {
+	names := make([]string, 0, len(environments))
+	for name := range environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		e := environments[name]
+		if name == EnvironmentLocal || name == EnvironmentRemote {
+			errors = append(errors, fmt.Sprintf("environment name %q is reserved", name))
+		}
+		if e.Namespace == "" {
+			errors = append(errors, fmt.Sprintf("environment %q does not define a namespace", name))
+		}
+		for _, msg := range ValidateEnvs(e.Envs) {
+			errors = append(errors, fmt.Sprintf("environment %q: %v", name, msg))
+		}
+		for _, msg := range validateOptions(Options{Scale: e.Scale}) {
+			errors = append(errors, fmt.Sprintf("environment %q: %v", name, msg))
+		}
+	}
+	return
+}
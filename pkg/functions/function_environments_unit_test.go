@@ -0,0 +1,179 @@
+//go:build !integration
+// +build !integration
+
+package functions
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestForEnvironment ensures that a named environment's overrides are applied
+// to the function, and that its deployed state is that of the environment.
+func TestForEnvironment(t *testing.T) {
+	var (
+		level   = "LOG_LEVEL"
+		debug   = "debug"
+		info    = "info"
+		other   = "OTHER"
+		value   = "value"
+		replica = int64(3)
+	)
+	f := Function{
+		Name:      "f",
+		Namespace: "default",
+		Registry:  "example.com/alice",
+		Run: RunSpec{Envs: Envs{
+			{Name: &level, Value: &info},
+			{Name: &other, Value: &value},
+		}},
+		Deploy: DeploySpec{
+			Namespace: "default",
+			Image:     "example.com/alice/f@sha256:1",
+			Environments: map[string]DeployedEnvironment{
+				"staging": {Namespace: "staging", Image: "example.com/staging/f@sha256:2"},
+			},
+		},
+		Environments: map[string]Environment{
+			"staging": {
+				Namespace: "staging",
+				Registry:  "example.com/staging",
+				Envs:      Envs{{Name: &level, Value: &debug}},
+				Scale:     &ScaleOptions{Min: &replica},
+			},
+			"prod": {Namespace: "prod"},
+		},
+	}
+
+	// The default is the function itself
+	g, err := f.ForEnvironment("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Namespace != "default" || g.Deploy.Namespace != "default" {
+		t.Fatalf("expected the default environment to be unchanged, got %q/%q", g.Namespace, g.Deploy.Namespace)
+	}
+
+	// Overrides are applied
+	g, err = f.ForEnvironment("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Namespace != "staging" || g.Registry != "example.com/staging" {
+		t.Fatalf("unexpected namespace/registry %q/%q", g.Namespace, g.Registry)
+	}
+	if g.Deploy.Namespace != "staging" || g.Deploy.Image != "example.com/staging/f@sha256:2" {
+		t.Fatalf("unexpected deployed state %q/%q", g.Deploy.Namespace, g.Deploy.Image)
+	}
+	if g.Deploy.Options.Scale == nil || *g.Deploy.Options.Scale.Min != replica {
+		t.Fatal("expected scale override")
+	}
+	if len(g.Run.Envs) != 2 || *g.Run.Envs[0].Value != debug || *g.Run.Envs[1].Value != value {
+		t.Fatalf("unexpected merged envs %v", g.Run.Envs)
+	}
+	if *f.Run.Envs[0].Value != info {
+		t.Fatal("expected the function's envs to be unmodified")
+	}
+
+	// Not yet deployed to the environment
+	g, err = f.ForEnvironment("prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Deploy.Namespace != "" || g.Registry != "example.com/alice" {
+		t.Fatalf("unexpected prod environment %q/%q", g.Deploy.Namespace, g.Registry)
+	}
+
+	// Undefined and reserved environments
+	if _, err = f.ForEnvironment("qa"); !errors.Is(err, ErrEnvironmentNotFound) {
+		t.Fatalf("expected ErrEnvironmentNotFound, got %v", err)
+	}
+	if _, err = f.ForEnvironment(EnvironmentLocal); err == nil {
+		t.Fatal("expected the local environment to be rejected")
+	}
+}
+
+// TestWithEnvironmentDeployment ensures the deployed state of a named
+// environment is recorded without affecting the default deployment.
+func TestWithEnvironmentDeployment(t *testing.T) {
+	f := Function{
+		Name:         "f",
+		Deploy:       DeploySpec{Namespace: "default", Image: "img@sha256:1"},
+		Environments: map[string]Environment{"staging": {Namespace: "staging"}},
+	}
+	deployed := Function{Deploy: DeploySpec{Namespace: "staging", Image: "img@sha256:2"}}
+
+	g := f.WithEnvironmentDeployment("staging", deployed)
+	if g.Deploy.Namespace != "default" || g.Deploy.Image != "img@sha256:1" {
+		t.Fatalf("expected default deployment unchanged, got %q/%q", g.Deploy.Namespace, g.Deploy.Image)
+	}
+	if e := g.Deploy.Environments["staging"]; e.Namespace != "staging" || e.Image != "img@sha256:2" {
+		t.Fatalf("unexpected staging deployment %v", e)
+	}
+	if len(f.Deploy.Environments) != 0 {
+		t.Fatal("expected the original function to be unmodified")
+	}
+
+	// The named environment's instance resolves to its deployed namespace
+	if _, err := New().Instances().Named(context.Background(), f, "staging"); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning prior to deployment, got %v", err)
+	}
+	if _, err := New().Instances().Named(context.Background(), f, "qa"); !errors.Is(err, ErrEnvironmentNotFound) {
+		t.Fatalf("expected ErrEnvironmentNotFound, got %v", err)
+	}
+}
+
+// TestForEnvironment_BuildState ensures that the build state of a named
+// environment is kept separately from that of the function.
+func TestForEnvironment_BuildState(t *testing.T) {
+	f := Function{
+		Root:         t.TempDir(),
+		Registry:     "example.com/alice",
+		Environments: map[string]Environment{"staging": {Namespace: "staging", Registry: "example.com/staging"}},
+	}
+	f.Build.Image = "example.com/alice/f:latest"
+	if err := f.WriteRuntimeBuiltImage(false); err != nil {
+		t.Fatal(err)
+	}
+
+	staging, err := f.ForEnvironment("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staging.Build.Image != "" {
+		t.Fatalf("expected staging not to be built, got %q", staging.Build.Image)
+	}
+	staging.Build.Image = "example.com/staging/f:latest"
+	if err = staging.WriteRuntimeBuiltImage(false); err != nil {
+		t.Fatal(err)
+	}
+	if err = staging.Stamp(); err != nil {
+		t.Fatal(err)
+	}
+
+	if staging, err = f.ForEnvironment("staging"); err != nil {
+		t.Fatal(err)
+	}
+	if staging.Build.Image != "example.com/staging/f:latest" || !staging.Built() {
+		t.Fatalf("expected staging to be built, got %q", staging.Build.Image)
+	}
+	if image, err := f.getLastBuiltImage(); err != nil || image != "example.com/alice/f:latest" {
+		t.Fatalf("expected the built image of the function to be unaffected, got %q (%v)", image, err)
+	}
+	if f.BuildStamp() != "" {
+		t.Fatal("expected the function not to be stamped")
+	}
+}
+
+// TestValidateEnvironments ensures invalid environments are reported.
+func TestValidateEnvironments(t *testing.T) {
+	errs := validateEnvironments(map[string]Environment{
+		"local":   {Namespace: "ns"},
+		"staging": {},
+		"prod":    {Namespace: "prod"},
+	})
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+}
@@ -0,0 +1,361 @@
+package functions
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Secret Resolvers
+// ----------------
+// Environment variables which reference secrets ({{ secret:name:key }} and
+// {{ secret:name }}) are resolved by the cluster when the function is
+// deployed.  When running locally, the function's secret resolvers (see
+// RunSpec.SecretResolvers) are used in their place, such that the function
+// is run with the same effective environment as the deployed service.
+
+const (
+	// SecretResolverFile resolves secrets from a directory containing one
+	// subdirectory per secret, each containing one file per key.  This is the
+	// layout of secrets mounted as volumes.
+	SecretResolverFile = "file"
+
+	// SecretResolverDotEnv resolves secrets from a .env file of KEY=VALUE
+	// lines.
+	SecretResolverDotEnv = "dotenv"
+
+	// SecretResolverKubernetes resolves secrets from the cluster of the
+	// current kubeconfig context.
+	SecretResolverKubernetes = "kubernetes"
+
+	// SecretResolverExec resolves individual secret keys from the output of
+	// a command, such as a password manager's command line helper.
+	SecretResolverExec = "exec"
+)
+
+// SecretResolverSpec configures a resolver of secret references used when
+// running the function locally.
+type SecretResolverSpec struct {
+	// Type of the resolver.
+	Type string `yaml:"type" jsonschema:"enum=file,enum=dotenv,enum=kubernetes,enum=exec"`
+
+	// Path of the secrets directory (file) or .env file (dotenv), relative
+	// to the function root.
+	Path string `yaml:"path,omitempty"`
+
+	// Secret restricts a dotenv resolver to the named secret.  By default
+	// the file's values are used for any secret.
+	Secret string `yaml:"secret,omitempty"`
+
+	// Namespace from which to fetch secrets (kubernetes).  Defaults to the
+	// namespace of the function, or that of the current context.
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// Command to run to resolve a secret key (exec).  The placeholders
+	// {name} and {key} are replaced with the secret name and key; the value
+	// is the command's output, less any trailing newline.
+	// Example: "pass show func/{name}/{key}"
+	Command string `yaml:"command,omitempty"`
+}
+
+// SecretResolver resolves the values of secrets.  Resolvers return an error
+// wrapping ErrSecretNotFound when they do not have the requested secret, in
+// which case the next resolver is consulted.
+type SecretResolver interface {
+	// Resolve the value of a key of the named secret.
+	Resolve(ctx context.Context, name, key string) (string, error)
+
+	// ResolveAll key/value pairs of the named secret.
+	ResolveAll(ctx context.Context, name string) (map[string]string, error)
+}
+
+// SecretResolverFactory constructs a resolver from its configuration for
+// the given function.
+type SecretResolverFactory func(SecretResolverSpec, Function) (SecretResolver, error)
+
+// WithSecretResolver registers the factory of resolvers of the given type,
+// used when running functions locally.  This is how resolvers with external
+// dependencies (such as kubernetes) are provided.
+func WithSecretResolver(typ string, factory SecretResolverFactory) Option {
+	return func(c *Client) {
+		c.secretResolvers[typ] = factory
+	}
+}
+
+// defaultSecretResolvers are those which are available without additional
+// configuration of the client.
+func defaultSecretResolvers() map[string]SecretResolverFactory {
+	return map[string]SecretResolverFactory{
+		SecretResolverFile:   newFileSecretResolver,
+		SecretResolverDotEnv: newDotEnvSecretResolver,
+		SecretResolverExec:   newExecSecretResolver,
+	}
+}
+
+// ResolveEnvs returns the function's environment variables with references
+// to secrets replaced by their values as resolved by the function's secret
+// resolvers, tried in order.  References to local environment variables are
+// also resolved.  If the function defines no secret resolvers, its
+// environment variables are returned as-is.
+func (c *Client) ResolveEnvs(ctx context.Context, f Function) (Envs, error) {
+	if len(f.Run.SecretResolvers) == 0 {
+		return f.Run.Envs, nil
+	}
+	resolvers := make([]SecretResolver, 0, len(f.Run.SecretResolvers))
+	for _, spec := range f.Run.SecretResolvers {
+		factory, ok := c.secretResolvers[spec.Type]
+		if !ok {
+			return nil, fmt.Errorf("secret resolver type %q is not available", spec.Type)
+		}
+		r, err := factory(spec, f)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create %v secret resolver. %w", spec.Type, err)
+		}
+		resolvers = append(resolvers, r)
+	}
+
+	resolved := make(Envs, 0, len(f.Run.Envs))
+	for _, e := range f.Run.Envs {
+		if e.Value == nil {
+			resolved = append(resolved, e)
+			continue
+		}
+		// All key/value pairs of a secret {{ secret:name }}
+		if m := regWholeSecret.FindStringSubmatch(*e.Value); e.Name == nil && len(m) == 2 {
+			values, err := resolveAll(ctx, resolvers, m[1])
+			if err != nil {
+				return nil, err
+			}
+			keys := make([]string, 0, len(values))
+			for k := range values {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				name, value := k, values[k]
+				resolved = append(resolved, Env{Name: &name, Value: &value})
+			}
+			continue
+		}
+		if e.Name == nil {
+			resolved = append(resolved, e)
+			continue
+		}
+		// A single key of a secret {{ secret:name:key }}
+		if m := regKeyFromSecret.FindStringSubmatch(*e.Value); len(m) == 3 {
+			value, err := resolve(ctx, resolvers, m[1], m[2])
+			if err != nil {
+				return nil, err
+			}
+			resolved = append(resolved, Env{Name: e.Name, Value: &value})
+			continue
+		}
+		// A local environment variable {{ env:NAME }}
+		if m := regLocalEnv.FindStringSubmatch(*e.Value); len(m) == 2 {
+			value, ok := os.LookupEnv(m[1])
+			if !ok {
+				return nil, fmt.Errorf("expected environment variable '%v' not found", m[1])
+			}
+			resolved = append(resolved, Env{Name: e.Name, Value: &value})
+			continue
+		}
+		resolved = append(resolved, e)
+	}
+	return resolved, nil
+}
+
+func resolve(ctx context.Context, resolvers []SecretResolver, name, key string) (string, error) {
+	for _, r := range resolvers {
+		value, err := r.Resolve(ctx, name, key)
+		if errors.Is(err, ErrSecretNotFound) {
+			continue
+		}
+		return value, err
+	}
+	return "", fmt.Errorf("%w: key %q of secret %q could not be resolved", ErrSecretNotFound, key, name)
+}
+
+func resolveAll(ctx context.Context, resolvers []SecretResolver, name string) (map[string]string, error) {
+	for _, r := range resolvers {
+		values, err := r.ResolveAll(ctx, name)
+		if errors.Is(err, ErrSecretNotFound) {
+			continue
+		}
+		return values, err
+	}
+	return nil, fmt.Errorf("%w: secret %q could not be resolved", ErrSecretNotFound, name)
+}
+
+// validateSecretResolvers checks that the secret resolvers are correctly set.
+// Returns array of error messages, empty if no errors are found
+func validateSecretResolvers(specs []SecretResolverSpec) (errors []string) {
+	for i, s := range specs {
+		switch s.Type {
+		case SecretResolverFile, SecretResolverDotEnv:
+			if s.Path == "" {
+				errors = append(errors, fmt.Sprintf("secret resolver #%d of type %q requires a path", i, s.Type))
+			}
+		case SecretResolverKubernetes:
+		case SecretResolverExec:
+			if strings.TrimSpace(s.Command) == "" {
+				errors = append(errors, fmt.Sprintf("secret resolver #%d of type %q requires a command", i, s.Type))
+			}
+		default:
+			errors = append(errors, fmt.Sprintf("secret resolver #%d has unrecognized type %q, allowed are %q, %q, %q or %q",
+				i, s.Type, SecretResolverFile, SecretResolverDotEnv, SecretResolverKubernetes, SecretResolverExec))
+		}
+	}
+	return
+}
+
+// resolverPath returns the path of the spec relative to the function root.
+func resolverPath(spec SecretResolverSpec, f Function) string {
+	if filepath.IsAbs(spec.Path) {
+		return spec.Path
+	}
+	return filepath.Join(f.Root, spec.Path)
+}
+
+// fileSecretResolver resolves secrets from a directory of <name>/<key> files.
+type fileSecretResolver struct {
+	dir string
+}
+
+func newFileSecretResolver(spec SecretResolverSpec, f Function) (SecretResolver, error) {
+	return &fileSecretResolver{dir: resolverPath(spec, f)}, nil
+}
+
+func (r *fileSecretResolver) Resolve(_ context.Context, name, key string) (string, error) {
+	bb, err := os.ReadFile(filepath.Join(r.dir, name, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrSecretNotFound
+	}
+	return string(bb), err
+}
+
+func (r *fileSecretResolver) ResolveAll(_ context.Context, name string) (map[string]string, error) {
+	entries, err := os.ReadDir(filepath.Join(r.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrSecretNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		// Skip directories and hidden files, such as the ..data links of
+		// secrets mounted by kubernetes.
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		bb, err := os.ReadFile(filepath.Join(r.dir, name, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		values[e.Name()] = string(bb)
+	}
+	return values, nil
+}
+
+// dotEnvSecretResolver resolves secrets from a .env file.
+type dotEnvSecretResolver struct {
+	path   string
+	secret string
+}
+
+func newDotEnvSecretResolver(spec SecretResolverSpec, f Function) (SecretResolver, error) {
+	return &dotEnvSecretResolver{path: resolverPath(spec, f), secret: spec.Secret}, nil
+}
+
+func (r *dotEnvSecretResolver) Resolve(ctx context.Context, name, key string) (string, error) {
+	values, err := r.ResolveAll(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+func (r *dotEnvSecretResolver) ResolveAll(_ context.Context, name string) (map[string]string, error) {
+	if r.secret != "" && r.secret != name {
+		return nil, ErrSecretNotFound
+	}
+	bb, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrSecretNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return parseDotEnv(bb)
+}
+
+// parseDotEnv parses lines of KEY=VALUE, ignoring blank lines and comments.
+// Values may be quoted, and lines may be prefixed with "export".
+func parseDotEnv(bb []byte) (map[string]string, error) {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(bb))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid .env line %d: expected KEY=VALUE", n)
+		}
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+			v = v[1 : len(v)-1]
+		}
+		values[k] = v
+	}
+	return values, scanner.Err()
+}
+
+// execSecretResolver resolves secret keys from the output of a command.
+type execSecretResolver struct {
+	args []string
+	dir  string
+}
+
+func newExecSecretResolver(spec SecretResolverSpec, f Function) (SecretResolver, error) {
+	args := strings.Fields(spec.Command)
+	if len(args) == 0 {
+		return nil, errors.New("command required")
+	}
+	return &execSecretResolver{args: args, dir: f.Root}, nil
+}
+
+func (r *execSecretResolver) Resolve(ctx context.Context, name, key string) (string, error) {
+	// Placeholders are substituted per argument rather than by way of a
+	// shell such that secret names and keys are never interpreted.
+	args := make([]string, len(r.args))
+	for i, a := range r.args {
+		args[i] = strings.NewReplacer("{name}", name, "{key}", key).Replace(a)
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = r.dir
+	stderr := bytes.Buffer{}
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%v: %w. %v", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// ResolveAll is not supported by exec resolvers, which can not enumerate the
+// keys of a secret.
+func (r *execSecretResolver) ResolveAll(context.Context, string) (map[string]string, error) {
+	return nil, ErrSecretNotFound
+}
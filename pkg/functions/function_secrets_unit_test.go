@@ -0,0 +1,141 @@
+//go:build !integration
+// +build !integration
+
+package functions
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	. "knative.dev/func/pkg/testing"
+)
+
+// TestResolveEnvs ensures that references to secrets are resolved by the
+// function's resolvers, tried in order.
+func TestResolveEnvs(t *testing.T) {
+	root, rm := Mktemp(t)
+	defer rm()
+
+	// A .env file which provides the "db" secret
+	if err := os.WriteFile(filepath.Join(root, ".env"), []byte("# database\nUSER=alice\nexport PASSWORD=\"s3cr3t\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A secrets directory which provides the "api" secret
+	if err := os.MkdirAll(filepath.Join(root, "secrets", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "secrets", "api", "token"), []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LOCAL_VALUE", "local")
+
+	var (
+		user     = "DB_USER"
+		token    = "API_TOKEN"
+		local    = "LOCAL"
+		plain    = "PLAIN"
+		userRef  = "{{ secret:db:USER }}"
+		tokenRef = "{{ secret:api:token }}"
+		localRef = "{{ env:LOCAL_VALUE }}"
+		allRef   = "{{ secret:db }}"
+		value    = "value"
+	)
+	f := Function{
+		Root: root,
+		Run: RunSpec{
+			Envs: Envs{
+				{Name: &user, Value: &userRef},
+				{Name: &token, Value: &tokenRef},
+				{Name: &local, Value: &localRef},
+				{Name: &plain, Value: &value},
+				{Value: &allRef},
+			},
+			SecretResolvers: []SecretResolverSpec{
+				{Type: SecretResolverDotEnv, Path: ".env", Secret: "db"},
+				{Type: SecretResolverFile, Path: "secrets"},
+			},
+		},
+	}
+
+	envs, err := New().ResolveEnvs(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Interpolate(envs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"DB_USER":   "alice",
+		"API_TOKEN": "abc",
+		"LOCAL":     "local",
+		"PLAIN":     "value",
+		"USER":      "alice",
+		"PASSWORD":  "s3cr3t",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("expected %v=%q, got %q", k, v, got[k])
+		}
+	}
+
+	// A secret not provided by any resolver is an error
+	missing := "{{ secret:other:key }}"
+	f.Run.Envs = Envs{{Name: &user, Value: &missing}}
+	if _, err = New().ResolveEnvs(context.Background(), f); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("expected ErrSecretNotFound, got %v", err)
+	}
+
+	// Resolver types not available to the client are an error
+	f.Run.SecretResolvers = []SecretResolverSpec{{Type: SecretResolverKubernetes}}
+	if _, err = New().ResolveEnvs(context.Background(), f); err == nil {
+		t.Fatal("expected an unavailable resolver type to error")
+	}
+}
+
+// TestResolveEnvs_Exec ensures that secret keys can be resolved from the
+// output of a command, with the secret name and key substituted.
+func TestResolveEnvs_Exec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires echo")
+	}
+	var (
+		name = "TOKEN"
+		ref  = "{{ secret:api:token }}"
+	)
+	f := Function{
+		Root: t.TempDir(),
+		Run: RunSpec{
+			Envs:            Envs{{Name: &name, Value: &ref}},
+			SecretResolvers: []SecretResolverSpec{{Type: SecretResolverExec, Command: "echo {name}/{key}"}},
+		},
+	}
+	envs, err := New().ResolveEnvs(context.Background(), f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envs) != 1 || *envs[0].Value != "api/token" {
+		t.Fatalf("unexpected envs %v", envs)
+	}
+}
+
+// TestValidateSecretResolvers ensures invalid resolvers are reported.
+func TestValidateSecretResolvers(t *testing.T) {
+	errs := validateSecretResolvers([]SecretResolverSpec{
+		{Type: SecretResolverDotEnv, Path: ".env"},
+		{Type: SecretResolverFile},
+		{Type: SecretResolverExec},
+		{Type: SecretResolverKubernetes},
+		{Type: "vault"},
+	})
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %v", errs)
+	}
+}
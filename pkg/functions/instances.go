@@ -16,6 +16,8 @@ const (
 // InstanceRefs are point-in-time snapshots of a function's runtime state in
 // a given environment.  By default 'local' and 'remote' environmnts are
 // available when a function is run locally and deployed (respectively).
+// Additional named environments are those defined by the function (see
+// Function.Environments).
 type InstanceRefs struct {
 	client *Client
 }
@@ -37,10 +39,22 @@ func (s *InstanceRefs) Get(ctx context.Context, f Function, environment string)
 	case EnvironmentRemote:
 		return s.Remote(ctx, f.Name, f.Deploy.Namespace)
 	default:
-		// Future versions will support additional ad-hoc named environments, such
-		// as for testing. Local and remote remaining the base cases.
+		return s.Named(ctx, f, environment)
+	}
+}
+
+// Named environment instance details for the function.
+// If the environment is defined by the function but it has not been deployed
+// there, the error returned is ErrNotRunning.
+func (s *InstanceRefs) Named(ctx context.Context, f Function, environment string) (Instance, error) {
+	if _, ok := f.Environments[environment]; !ok {
 		return Instance{}, ErrEnvironmentNotFound
 	}
+	deployed, ok := f.Deploy.Environments[environment]
+	if !ok || deployed.Namespace == "" {
+		return Instance{}, ErrNotRunning
+	}
+	return s.Remote(ctx, f.Name, deployed.Namespace)
 }
 
 // Local instance details for the function
@@ -0,0 +1,278 @@
+package functions
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/uuid"
+)
+
+// DefaultLoadConcurrency is the number of concurrent workers used when
+// generating load against a function and no concurrency is specified.
+const DefaultLoadConcurrency = 1
+
+// LoadOptions define the shape of the load generated by InvokeLoad.
+// At least one of Requests or Duration must be provided.  When both are
+// provided, load generation stops when either limit is reached.
+type LoadOptions struct {
+	// Requests is the total number of requests to send.
+	Requests int
+	// Concurrency is the number of workers sending requests in parallel.
+	Concurrency int
+	// Duration is the maximum amount of time to spend sending requests.
+	Duration time.Duration
+}
+
+// LoadResult is a summary of a load generation run against a function
+// instance.  Latencies are expressed in milliseconds to ease comparison with
+// the scale options of a function (see ScaleOptions).
+type LoadResult struct {
+	Target      string         `json:"target" yaml:"target"`
+	Format      string         `json:"format" yaml:"format"`
+	Concurrency int            `json:"concurrency" yaml:"concurrency"`
+	Requests    int            `json:"requests" yaml:"requests"`
+	Errors      int            `json:"errors" yaml:"errors"`
+	Elapsed     float64        `json:"elapsedSeconds" yaml:"elapsedSeconds"`
+	Throughput  float64        `json:"requestsPerSecond" yaml:"requestsPerSecond"`
+	Latency     LoadLatency    `json:"latencyMs" yaml:"latencyMs"`
+	StatusCodes map[int]int    `json:"statusCodes" yaml:"statusCodes"`
+	ErrorCounts map[string]int `json:"errorCounts,omitempty" yaml:"errorCounts,omitempty"`
+}
+
+// LoadLatency is the latency distribution (in milliseconds) of the requests
+// sent during a load generation run.
+type LoadLatency struct {
+	Min  float64 `json:"min" yaml:"min"`
+	Mean float64 `json:"mean" yaml:"mean"`
+	P50  float64 `json:"p50" yaml:"p50"`
+	P95  float64 `json:"p95" yaml:"p95"`
+	P99  float64 `json:"p99" yaml:"p99"`
+	Max  float64 `json:"max" yaml:"max"`
+}
+
+// loadSample is the outcome of a single request sent during load generation.
+type loadSample struct {
+	latency time.Duration
+	status  int // zero if no response was received
+	err     error
+}
+
+// sender of a single request, returning the status code received.
+type sender func(ctx context.Context) (status int, err error)
+
+// invokeLoad sends repeated invocations of the message to the target
+// function instance as described by the load options, returning a summary
+// of the latencies, status codes and errors observed.
+func invokeLoad(ctx context.Context, c *Client, f Function, target string, m InvokeMessage, o LoadOptions) (r LoadResult, err error) {
+	if o.Requests <= 0 && o.Duration <= 0 {
+		return r, errors.New("load generation requires a number of requests or a duration")
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultLoadConcurrency
+	}
+	if o.Requests > 0 && o.Concurrency > o.Requests {
+		o.Concurrency = o.Requests
+	}
+
+	route, err := invocationRoute(ctx, c, f, target)
+	if err != nil {
+		return
+	}
+
+	format := DefaultInvokeFormat
+	if f.Invoke != "" {
+		format = f.Invoke
+	}
+	if m.Format != "" {
+		format = m.Format
+	}
+
+	var send sender
+	switch format {
+	case "http":
+		send = newPostSender(route, m, c.transport)
+	case "cloudevent":
+		if send, err = newEventSender(route, m, c.transport); err != nil {
+			return
+		}
+	default:
+		return r, fmt.Errorf("format '%v' not supported", format)
+	}
+
+	if c.verbose {
+		fmt.Printf("Sending load to '%v' function at %v\n", format, route)
+	}
+
+	if o.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Duration)
+		defer cancel()
+	}
+
+	var (
+		tickets = make(chan struct{})
+		samples = make(chan loadSample)
+		wg      sync.WaitGroup
+	)
+
+	// Tickets are issued until the requested number of requests is reached
+	// or the context is done (duration elapsed or canceled).
+	go func() {
+		defer close(tickets)
+		for i := 0; o.Requests <= 0 || i < o.Requests; i++ {
+			select {
+			case tickets <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < o.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range tickets {
+				start := time.Now()
+				status, err := send(ctx)
+				if err != nil && ctx.Err() != nil && o.Duration > 0 {
+					// Requests interrupted by the end of the run are not counted.
+					continue
+				}
+				samples <- loadSample{latency: time.Since(start), status: status, err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(samples)
+	}()
+
+	start := time.Now()
+	collected := []loadSample{}
+	for s := range samples {
+		collected = append(collected, s)
+	}
+	r = summarizeLoad(collected, time.Since(start))
+	r.Target = route
+	r.Format = format
+	r.Concurrency = o.Concurrency
+
+	// A run interrupted by the caller is an error; a run ended by the
+	// requested duration elapsing is not.
+	if err = ctx.Err(); errors.Is(err, context.DeadlineExceeded) && o.Duration > 0 {
+		err = nil
+	}
+	return
+}
+
+// summarizeLoad calculates the load result from the individual samples.
+func summarizeLoad(samples []loadSample, elapsed time.Duration) (r LoadResult) {
+	r.Requests = len(samples)
+	r.Elapsed = elapsed.Seconds()
+	r.StatusCodes = make(map[int]int)
+	r.ErrorCounts = make(map[string]int)
+	if elapsed > 0 {
+		r.Throughput = float64(len(samples)) / elapsed.Seconds()
+	}
+
+	latencies := make([]float64, 0, len(samples))
+	var total float64
+	for _, s := range samples {
+		ms := float64(s.latency) / float64(time.Millisecond)
+		latencies = append(latencies, ms)
+		total += ms
+		if s.status != 0 {
+			r.StatusCodes[s.status]++
+		}
+		if s.err != nil {
+			r.Errors++
+			r.ErrorCounts[s.err.Error()]++
+		} else if s.status > 299 {
+			r.Errors++
+		}
+	}
+	if len(latencies) == 0 {
+		return
+	}
+	sort.Float64s(latencies)
+	r.Latency = LoadLatency{
+		Min:  latencies[0],
+		Mean: total / float64(len(latencies)),
+		P50:  percentile(latencies, 50),
+		P95:  percentile(latencies, 95),
+		P99:  percentile(latencies, 99),
+		Max:  latencies[len(latencies)-1],
+	}
+	return
+}
+
+// percentile of the sorted values using the nearest-rank method.
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// newPostSender returns a sender which POSTs the message data to the route.
+func newPostSender(route string, m InvokeMessage, t http.RoundTripper) sender {
+	client := http.Client{
+		Transport: t,
+		Timeout:   time.Minute,
+	}
+	return func(ctx context.Context) (int, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", route, bytes.NewReader(m.Data))
+		if err != nil {
+			return 0, fmt.Errorf("failure to create request: %w", err)
+		}
+		req.Header.Add("Content-Type", m.ContentType)
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode, nil
+	}
+}
+
+// newEventSender returns a sender which sends the message as a CloudEvent to
+// the route.  Each event is assigned a unique ID.
+func newEventSender(route string, m InvokeMessage, t http.RoundTripper) (sender, error) {
+	c, err := cloudevents.NewClientHTTP(
+		cloudevents.WithTarget(route),
+		cloudevents.WithRoundTripper(t))
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) (int, error) {
+		event := cloudevents.NewEvent()
+		event.SetID(uuid.NewString())
+		event.SetSource(m.Source)
+		event.SetType(m.Type)
+		if err := event.SetData(m.ContentType, m.Data); err != nil {
+			return 0, fmt.Errorf("cannot set data: %w", err)
+		}
+		_, result := c.Request(cloudevents.ContextWithTarget(ctx, route), event)
+		var httpResult *cehttp.Result
+		if cloudevents.ResultAs(result, &httpResult) {
+			return httpResult.StatusCode, nil
+		}
+		if cloudevents.IsUndelivered(result) {
+			return 0, result
+		}
+		return http.StatusOK, nil
+	}, nil
+}
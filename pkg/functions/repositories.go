@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/go-git/go-git/v5"
 )
 
 const (
@@ -157,10 +159,31 @@ func (r *Repositories) Get(name string) (repo Repository, err error) {
 	return repo, ErrRepositoryNotFound
 }
 
+// RepositoryAddOption configures the addition of a repository.
+type RepositoryAddOption func(*repositoryAddOptions)
+
+type repositoryAddOptions struct {
+	ref string
+}
+
+// WithRepositoryRef pins the added repository to the given tag or commit.
+// Pinned repositories are not updated (see Update).  Requires the repository
+// be a git repository.
+func WithRepositoryRef(ref string) RepositoryAddOption {
+	return func(o *repositoryAddOptions) {
+		o.ref = ref
+	}
+}
+
 // Add a repository of the given name from the URI.  Name, if not provided,
 // defaults to the repo name (sans optional .git suffix). Returns the final
 // name as added.
-func (r *Repositories) Add(name, uri string) (string, error) {
+func (r *Repositories) Add(name, uri string, options ...RepositoryAddOption) (string, error) {
+	oo := repositoryAddOptions{}
+	for _, o := range options {
+		o(&oo)
+	}
+
 	if r.path == "" {
 		return "", fmt.Errorf("repository %v(%v) not added. "+
 			"No repositories path provided", name, uri)
@@ -178,6 +201,15 @@ func (r *Repositories) Add(name, uri string) (string, error) {
 		return "", fmt.Errorf("repository '%v' already exists", repo.Name)
 	}
 
+	// Pinned repositories are cloned at the given ref.
+	if oo.ref != "" {
+		if err = writePinned(uri, oo.ref, dest); err != nil {
+			_ = os.RemoveAll(dest)
+			return "", fmt.Errorf("failed to write repository: %w", err)
+		}
+		return repo.Name, nil
+	}
+
 	// Instruct the repository to write itself to disk at the given path.
 	// Fails if path exists.
 	err = repo.Write(dest)
@@ -187,6 +219,64 @@ func (r *Repositories) Add(name, uri string) (string, error) {
 	return repo.Name, nil
 }
 
+// Update an installed repository by fetching from its origin and
+// fast-forwarding its checked out branch.  Returned are the revisions
+// before and after the update, which are equal if it was already up to date.
+// Repositories pinned to a tag or commit are not updated, and an error
+// wrapping ErrRepositoryPinned is returned.
+func (r *Repositories) Update(name string) (from, to string, err error) {
+	if r.path == "" {
+		return "", "", fmt.Errorf("repository %v not updated. "+
+			"No repositories path provided", name)
+	}
+	if name == "" {
+		return "", "", errors.New("name is required")
+	}
+	path := filepath.Join(r.path, name)
+	if _, err = os.Stat(path); os.IsNotExist(err) {
+		return "", "", fmt.Errorf("%w: %v", ErrRepositoryNotFound, name)
+	}
+
+	repo, err := git.PlainOpen(path)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return "", "", fmt.Errorf("repository %v is not a git repository and can not be updated", name)
+	} else if err != nil {
+		return
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return
+	}
+	from = head.Hash().String()
+	if ref := repositoryPin(repo); ref != "" {
+		return from, from, fmt.Errorf("%w: %v is pinned to %v", ErrRepositoryPinned, name, ref)
+	}
+	if !head.Name().IsBranch() {
+		return from, from, fmt.Errorf("repository %v has no branch checked out", name)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return
+	}
+	err = wt.Pull(&git.PullOptions{
+		RemoteName:    "origin",
+		ReferenceName: head.Name(),
+		SingleBranch:  true,
+	})
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return from, from, nil
+	} else if errors.Is(err, git.ErrNonFastForwardUpdate) {
+		return from, from, fmt.Errorf("repository %v can not be fast-forwarded; remove and add it again", name)
+	} else if err != nil {
+		return from, from, fmt.Errorf("failed to update repository %v: %w", name, err)
+	}
+	if head, err = repo.Head(); err != nil {
+		return
+	}
+	return from, head.Hash().String(), nil
+}
+
 // Rename a repository
 func (r *Repositories) Rename(from, to string) error {
 	if r.path == "" {
@@ -4,11 +4,28 @@
 package functions_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 
 	fn "knative.dev/func/pkg/functions"
 	. "knative.dev/func/pkg/testing"
@@ -337,6 +354,228 @@ func TestRepositories_URL(t *testing.T) {
 	}
 }
 
+// TestRepositories_Update ensures that an installed repository can be updated
+// to the latest revision of its origin.
+func TestRepositories_Update(t *testing.T) {
+	gitRoot := t.TempDir()
+	source, commit := newTestGitRepo(filepath.Join(gitRoot, "templates"), t)
+	uri := RunGitServer(gitRoot, t) + "/templates/.git"
+
+	root, rm := Mktemp(t)
+	defer rm()
+	client := fn.New(fn.WithRepositoriesPath(root))
+
+	if _, err := client.Repositories().Add("templates", uri); err != nil {
+		t.Fatal(err)
+	}
+	r, err := client.Repositories().Get("templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Revision() != commit {
+		t.Fatalf("expected revision %v, got %v", commit, r.Revision())
+	}
+
+	// Already up to date
+	from, to, err := client.Repositories().Update("templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != commit || to != commit {
+		t.Fatalf("expected no change from %v, got %v..%v", commit, from, to)
+	}
+
+	// A new commit on the origin is fast-forwarded to
+	next := commitTestGitRepo(source, "v2", t)
+	if from, to, err = client.Repositories().Update("templates"); err != nil {
+		t.Fatal(err)
+	}
+	if from != commit || to != next {
+		t.Fatalf("expected update %v..%v, got %v..%v", commit, next, from, to)
+	}
+	bb, err := os.ReadFile(filepath.Join(root, "templates", "go", "http", "handle.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bb) != "v2" {
+		t.Fatalf("expected updated template contents, got %q", bb)
+	}
+
+	// Missing repositories are an error
+	if _, _, err = client.Repositories().Update("missing"); !errors.Is(err, fn.ErrRepositoryNotFound) {
+		t.Fatalf("expected ErrRepositoryNotFound, got %v", err)
+	}
+}
+
+// TestRepositories_AddPinned ensures that a repository can be added pinned to
+// a tag, and that pinned repositories are not updated.
+func TestRepositories_AddPinned(t *testing.T) {
+	gitRoot := t.TempDir()
+	source, commit := newTestGitRepo(filepath.Join(gitRoot, "templates"), t)
+	if _, err := source.CreateTag("v1.0.0", plumbing.NewHash(commit), nil); err != nil {
+		t.Fatal(err)
+	}
+	_ = commitTestGitRepo(source, "v2", t)
+	uri := RunGitServer(gitRoot, t) + "/templates/.git"
+
+	root, rm := Mktemp(t)
+	defer rm()
+	client := fn.New(fn.WithRepositoriesPath(root))
+
+	if _, err := client.Repositories().Add("templates", uri, fn.WithRepositoryRef("v1.0.0")); err != nil {
+		t.Fatal(err)
+	}
+	r, err := client.Repositories().Get("templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Revision() != commit {
+		t.Fatalf("expected pinned revision %v, got %v", commit, r.Revision())
+	}
+	if r.Pinned() != "v1.0.0" {
+		t.Fatalf("expected repository pinned to v1.0.0, got %q", r.Pinned())
+	}
+	if _, _, err = client.Repositories().Update("templates"); !errors.Is(err, fn.ErrRepositoryPinned) {
+		t.Fatalf("expected ErrRepositoryPinned, got %v", err)
+	}
+
+	// Unresolvable refs are an error and leave nothing behind
+	if _, err = client.Repositories().Add("other", uri, fn.WithRepositoryRef("v9.9.9")); err == nil {
+		t.Fatal("expected an unresolvable ref to error")
+	}
+	if _, err = os.Stat(filepath.Join(root, "other")); !os.IsNotExist(err) {
+		t.Fatalf("expected failed add to be removed, got %v", err)
+	}
+}
+
+// newTestGitRepo creates a git repository at path containing a single go/http
+// template, returning the repository and its initial commit.
+func newTestGitRepo(path string, t *testing.T) (*git.Repository, string) {
+	t.Helper()
+	repo, err := git.PlainInit(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.MkdirAll(filepath.Join(path, "go", "http"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(path, "manifest.yaml"), []byte("version: 1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return repo, commitTestGitRepo(repo, "v1", t)
+}
+
+// commitTestGitRepo writes the template's handle.go with the given contents
+// and commits, returning the commit hash.
+func commitTestGitRepo(repo *git.Repository, contents string, t *testing.T) string {
+	t.Helper()
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(wt.Filesystem.Root(), "go", "http", "handle.go"), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = wt.AddGlob("."); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := wt.Commit(contents, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash.String()
+}
+
+// TestRepositories_AddOCI ensures that a repository distributed as an OCI
+// artifact can be added, and is named for the artifact's repository.
+func TestRepositories_AddOCI(t *testing.T) {
+	// A registry containing the repository as a single layer
+	server := httptest.NewServer(registry.New(registry.Logger(log.New(io.Discard, "", 0))))
+	defer server.Close()
+	ref := strings.TrimPrefix(server.URL, "http://") + "/corp/templates:v2"
+
+	buf := bytes.Buffer{}
+	tw := tar.NewWriter(&buf)
+	for _, h := range []*tar.Header{
+		{Name: "manifest.yaml", Mode: 0644, Size: 15},
+		{Name: "go/custom/handle.go", Mode: 0644, Size: 17},
+		{Name: "go/custom/link", Typeflag: tar.TypeSymlink, Linkname: "handle.go"},
+		// Entries outside of the repository are skipped
+		{Name: "..", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "../escaped", Mode: 0644},
+	} {
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		contents := map[string]string{
+			"manifest.yaml":       "version: 2.0.0\n",
+			"go/custom/handle.go": "package function\n",
+		}[h.Name]
+		if _, err := tw.Write([]byte(contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	layer, err := tarball.LayerFromReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag, err := name.NewTag(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = remote.Write(tag, img); err != nil {
+		t.Fatal(err)
+	}
+
+	// Add the repository, which defaults its name to that of the artifact
+	root, rm := Mktemp(t)
+	defer rm()
+	client := fn.New(fn.WithRepositoriesPath(root))
+	n, err := client.Repositories().Add("", "oci://"+ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != "templates" {
+		t.Fatalf("expected repository name 'templates', got %q", n)
+	}
+	r, err := client.Repositories().Get("templates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Version != "2.0.0" {
+		t.Fatalf("expected version 2.0.0, got %q", r.Version)
+	}
+	if _, err = r.Template("go", "custom"); err != nil {
+		t.Fatal(err)
+	}
+	link, err := os.Readlink(filepath.Join(root, "templates", "go", "custom", "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link != "handle.go" {
+		t.Fatalf("expected link to handle.go, got %q", link)
+	}
+
+	// A function can be created from the repository directly
+	client = fn.New(fn.WithRepository("oci://" + ref))
+	fnRoot := t.TempDir()
+	if _, err = client.Init(fn.Function{Root: fnRoot, Runtime: "go", Template: "custom"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(fnRoot, "handle.go")); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // TestRepositories_Missing ensures that a missing repositores directory
 // does not cause an error unless it was explicitly set (zero value indicates
 // no repos should be loaded from os).
@@ -92,6 +92,10 @@ type runtimeConfig struct {
 // runtime parent directory, and is used during template.Write() to set
 // values on the newly written Function.
 type templateConfig struct {
+	// Description of the template, presented when choosing a template with
+	// which to create a function.
+	Description string `yaml:"description,omitempty"`
+
 	// BuildConfig defines builders and buildpacks.  the denormalized view of
 	// members which can be defined per repo or per runtime first.
 	BuildConfig `yaml:",inline"`
@@ -110,6 +114,14 @@ type templateConfig struct {
 	// Invoke defines invocation hints for a functions which is created
 	// from this template prior to being materially modified.
 	Invoke string `yaml:"invoke,omitempty"`
+
+	// Parameters which are provided when creating a function from the
+	// template, with which its files are rendered.  See TemplateParameter.
+	Parameters []TemplateParameter `yaml:"parameters,omitempty"`
+
+	// Hooks are commands run when creating a function from the template.
+	// See TemplateHooks.
+	Hooks TemplateHooks `yaml:"hooks,omitempty"`
 }
 
 // NewRepository creates a repository instance from any of: a path on disk, a
@@ -149,7 +161,8 @@ func (r Repository) FS() filesystem.Filesystem {
 // filesystemFromURI returns a filesystem from the data located at the
 // given URI.  If URI is not provided, indicates the embedded repo should
 // be loaded.  URI can be a remote git repository (http:// https:// etc.),
-// or a local file path (file://) which can be a git repo or a plain directory.
+// a local file path (file://) which can be a git repo or a plain directory,
+// or an OCI artifact in a container registry (oci://).
 func filesystemFromURI(uri string) (f filesystem.Filesystem, err error) {
 	// If not provided, indicates embedded.
 	if uri == "" {
@@ -160,6 +173,11 @@ func filesystemFromURI(uri string) (f filesystem.Filesystem, err error) {
 		return filesystemFromPath(uri)
 	}
 
+	// Repositories distributed as OCI artifacts are pulled from the registry.
+	if isOCIURI(uri) {
+		return filesystemFromOCI(uri)
+	}
+
 	// Attempt to get a filesystem from the uri as a remote repo.
 	f, err = FilesystemFromRepo(uri)
 	if f != nil || err != nil {
@@ -344,6 +362,15 @@ func repositoryName(name, uri string) (string, error) {
 		return name, nil
 	}
 	// URI-derived is second precedence
+	// OCI repositories are named for the last path segment of the artifact's
+	// repository, sans tag or digest.
+	if isOCIURI(uri) {
+		ref, err := ociReference(uri)
+		if err != nil {
+			return "", err
+		}
+		return path.Base(ref.Context().RepositoryStr()), nil
+	}
 	if uri != "" {
 		parsed, err := url.Parse(uri)
 		if err != nil {
@@ -412,6 +439,7 @@ func loadRuntimeConfig(fs filesystem.Filesystem, repoCfg repoConfig, runtime str
 func loadTemplateConfig(fs filesystem.Filesystem, repoCfg repoConfig, runtimeCfg runtimeConfig, runtimeName, templateName string) (tplCfg templateConfig, err error) {
 	// The templateConfig is defaulted to the values from the parent (repo)
 	tplCfg = runtimeCfg.templateConfig
+	tplCfg.Description = "" // describes only the template itself
 
 	// If there is a manifest.yaml at the template level, it can overwrite
 	file, err := fs.Open(path.Join(repoCfg.TemplatesPath, runtimeName, templateName, manifestFile))
@@ -535,13 +563,64 @@ func (r *Repository) Write(dest string) (err error) {
 // effort; returns empty string if the repository is not a git repo or the repo
 // has been mutated beyond recognition on disk (ex: removing the origin remote)
 func (r *Repository) URL() string {
+	repo, err := r.gitRepository()
+	if err != nil {
+		return "" // not a git repository
+	}
+
+	c, err := repo.Config()
+	if err != nil {
+		return "" // Has no .git/config or other error.
+	}
+
+	ref, _ := repo.Head()
+	if _, ok := c.Remotes["origin"]; ok {
+		urls := c.Remotes["origin"].URLs
+		if len(urls) > 0 {
+			// Repositories pinned to a tag or commit have a detached HEAD, and
+			// thus no branch to indicate.
+			if ref == nil || !ref.Name().IsBranch() {
+				return urls[0]
+			}
+			return urls[0] + "#" + ref.Name().Short()
+		}
+	}
+	return ""
+}
+
+// Revision is the commit hash of the repository's checked out HEAD.  Best
+// effort; returns empty string if the repository is not a git repo.
+func (r *Repository) Revision() string {
+	repo, err := r.gitRepository()
+	if err != nil {
+		return ""
+	}
+	ref, err := repo.Head()
+	if err != nil {
+		return ""
+	}
+	return ref.Hash().String()
+}
+
+// Pinned returns the tag or commit to which the repository was pinned when
+// added (see WithRepositoryRef), or empty string if it is not pinned.
+func (r *Repository) Pinned() string {
+	repo, err := r.gitRepository()
+	if err != nil {
+		return ""
+	}
+	return repositoryPin(repo)
+}
+
+// gitRepository opens the repository's git repository on disk.
+func (r *Repository) gitRepository() (*git.Repository, error) {
 	uri := r.uri
 
 	// The default builtin repository is indicated by an empty URI.
 	// It has no remote URL, and without this check the current working directory
 	// would be checked.
 	if uri == "" {
-		return ""
+		return nil, git.ErrRepositoryNotExists
 	}
 
 	// git.PlainOpen does not seem to
@@ -549,22 +628,47 @@ func (r *Repository) URL() string {
 		uri = filepath.FromSlash(r.uri[7:])
 	}
 
-	repo, err := git.PlainOpen(uri)
-	if err != nil {
-		return "" // not a git repository
-	}
+	return git.PlainOpen(uri)
+}
 
+// repositoryPinSection is the section of an installed repository's git
+// config in which the ref to which it is pinned is recorded.
+const repositoryPinSection = "func"
+
+// repositoryPin returns the ref to which the git repository is pinned.
+func repositoryPin(repo *git.Repository) string {
 	c, err := repo.Config()
 	if err != nil {
-		return "" // Has no .git/config or other error.
+		return ""
 	}
+	return c.Raw.Section(repositoryPinSection).Option("ref")
+}
 
-	ref, _ := repo.Head()
-	if _, ok := c.Remotes["origin"]; ok {
-		urls := c.Remotes["origin"].URLs
-		if len(urls) > 0 {
-			return urls[0] + "#" + ref.Name().Short()
-		}
+// writePinned clones the git repository at uri to dest, checking out the
+// given ref (a tag or commit) and recording it as pinned.
+func writePinned(uri, ref, dest string) (err error) {
+	opts := getGitCloneOptions(uri)
+	opts.Depth = 0 // full history, such that any commit can be resolved
+	opts.Tags = git.AllTags
+	repo, err := git.PlainClone(dest, false, opts)
+	if err != nil {
+		return fmt.Errorf("failed to clone repository (pinning requires a git repository): %w", err)
 	}
-	return ""
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return fmt.Errorf("cannot resolve ref %q: %w", ref, err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err = wt.Checkout(&git.CheckoutOptions{Hash: *hash}); err != nil {
+		return fmt.Errorf("cannot checkout %q: %w", ref, err)
+	}
+	c, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	c.Raw.Section(repositoryPinSection).SetOption("ref", ref)
+	return repo.SetConfig(c)
 }
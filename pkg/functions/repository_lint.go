@@ -0,0 +1,246 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"knative.dev/func/pkg/filesystem"
+	"knative.dev/func/pkg/scaffolding"
+)
+
+// LintIssue is a problem found in a template repository.
+type LintIssue struct {
+	// Path within the repository of the runtime, template or manifest to
+	// which the issue pertains.
+	Path string
+
+	// Message describing the issue.
+	Message string
+
+	// Warning issues do not prevent the repository from being used, but
+	// indicate that it could not be checked completely.
+	Warning bool
+}
+
+func (i LintIssue) String() string {
+	if i.Warning {
+		return i.Path + ": warning: " + i.Message
+	}
+	return i.Path + ": " + i.Message
+}
+
+// repoManifest is the schema of the repository-level manifest.yaml.
+type repoManifest struct {
+	repoConfig `yaml:",inline"`
+
+	// SchemaVersion and Runtimes are accepted for compatibility, but unused.
+	SchemaVersion string `yaml:"schema_version,omitempty"`
+	Runtimes      []any  `yaml:"runtimes,omitempty"`
+}
+
+// LintOption configures the linting of a repository.
+type LintOption func(*lintOptions)
+
+type lintOptions struct {
+	builder   Builder
+	buildable func(runtime string) bool
+}
+
+// WithLintBuilder builds each template of the runtimes for which buildable
+// returns true using the given builder, proving that they compile.
+func WithLintBuilder(b Builder, buildable func(runtime string) bool) LintOption {
+	return func(o *lintOptions) {
+		o.builder = b
+		o.buildable = buildable
+	}
+}
+
+// LintRepository checks the template repository at uri for problems which
+// would otherwise only surface when creating or building a function from one
+// of its templates:
+//
+//   - manifest.yaml files at the repository, runtime and template levels must
+//     contain only known fields of the correct type, and valid values.
+//   - every template, when written with its parameters' default values, must
+//     be accepted by the scaffolding of its runtime.  Templates of runtimes
+//     which are not scaffolded can not be checked, which is a warning.
+//   - optionally, every template must build (see WithLintBuilder).
+//
+// An error is returned if the repository can not be loaded at all.
+func LintRepository(ctx context.Context, uri string, options ...LintOption) (issues []LintIssue, err error) {
+	oo := lintOptions{}
+	for _, o := range options {
+		o(&oo)
+	}
+
+	repo, err := NewRepository("", uri)
+	if err != nil {
+		return
+	}
+	fs := repo.FS()
+
+	issues = append(issues, lintManifest(fs, manifestFile, &repoManifest{})...)
+	if len(repo.Runtimes) == 0 {
+		issues = append(issues, LintIssue{Path: repo.TemplatesPath, Message: "repository contains no runtimes"})
+	}
+
+	for _, r := range repo.Runtimes {
+		runtimePath := path.Join(repo.TemplatesPath, r.Name)
+		issues = append(issues, lintManifest(fs, path.Join(runtimePath, manifestFile), &runtimeConfig{})...)
+		if len(r.Templates) == 0 {
+			issues = append(issues, LintIssue{Path: runtimePath, Message: "runtime contains no templates"})
+		}
+		for _, t := range r.Templates {
+			templatePath := path.Join(runtimePath, t.Name())
+			issues = append(issues, lintManifest(fs, path.Join(templatePath, manifestFile), &templateConfig{})...)
+			if err = ctx.Err(); err != nil {
+				return
+			}
+			if err := lintTemplate(ctx, repo, t, oo); err != nil {
+				var w lintWarning
+				issues = append(issues, LintIssue{Path: templatePath, Message: err.Error(), Warning: errors.As(err, &w)})
+			}
+		}
+	}
+	return issues, nil
+}
+
+// lintManifest decodes the manifest at path, if it exists, into the given
+// config, reporting unknown fields, invalid types and invalid values.
+func lintManifest(fs filesystem.Filesystem, path string, cfg any) (issues []LintIssue) {
+	file, err := fs.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	} else if err != nil {
+		return []LintIssue{{Path: path, Message: err.Error()}}
+	}
+	defer file.Close()
+	bb, err := io.ReadAll(file)
+	if err != nil {
+		return []LintIssue{{Path: path, Message: err.Error()}}
+	}
+	if err = yaml.UnmarshalStrict(bb, cfg); err != nil {
+		return []LintIssue{{Path: path, Message: err.Error()}}
+	}
+
+	var tc templateConfig
+	switch c := cfg.(type) {
+	case *repoManifest:
+		tc = c.templateConfig
+	case *runtimeConfig:
+		tc = c.templateConfig
+	case *templateConfig:
+		tc = *c
+	}
+	for _, msg := range lintTemplateConfig(tc) {
+		issues = append(issues, LintIssue{Path: path, Message: msg})
+	}
+	return
+}
+
+// lintTemplateConfig returns a message for each invalid value.
+func lintTemplateConfig(c templateConfig) (msgs []string) {
+	if c.Invoke != "" && c.Invoke != "http" && c.Invoke != "cloudevent" {
+		msgs = append(msgs, fmt.Sprintf("invalid invoke %q. Must be 'http' or 'cloudevent'", c.Invoke))
+	}
+	for _, e := range []string{c.HealthEndpoints.Liveness, c.HealthEndpoints.Readiness} {
+		if e != "" && !strings.HasPrefix(e, "/") {
+			msgs = append(msgs, fmt.Sprintf("health endpoint %q must be an absolute path", e))
+		}
+	}
+	names := map[string]bool{}
+	for i, p := range c.Parameters {
+		if p.Name == "" {
+			msgs = append(msgs, fmt.Sprintf("parameter %d has no name", i))
+			continue
+		}
+		if names[p.Name] {
+			msgs = append(msgs, fmt.Sprintf("parameter %q is declared more than once", p.Name))
+		}
+		names[p.Name] = true
+		if p.Default != "" && len(p.Choices) > 0 && !contains(p.Choices, p.Default) {
+			msgs = append(msgs, fmt.Sprintf("default %q of parameter %q is not one of its choices", p.Default, p.Name))
+		}
+	}
+	for i, h := range c.Hooks.PostCreate {
+		if strings.TrimSpace(h.Command) == "" {
+			msgs = append(msgs, fmt.Sprintf("postCreate hook %d has no command", i))
+		}
+	}
+	return
+}
+
+// lintTemplate writes the template to a temporary directory, checking that
+// it is accepted by its runtime's scaffolding and optionally that it builds.
+func lintTemplate(ctx context.Context, repo Repository, t Template, oo lintOptions) error {
+	root, err := os.MkdirTemp("", "func-lint")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(root)
+
+	// Required parameters without a default are given an example value.
+	params := map[string]string{}
+	for _, p := range t.Parameters() {
+		if p.Default == "" {
+			params[p.Name] = "example"
+			if len(p.Choices) > 0 {
+				params[p.Name] = p.Choices[0]
+			}
+		}
+	}
+	f := Function{
+		Name:           "lint",
+		Root:           filepath.Join(root, "lint"),
+		Runtime:        t.Runtime(),
+		Template:       t.Name(),
+		TemplateParams: params,
+	}
+	if err = t.Write(ctx, &f); err != nil {
+		return fmt.Errorf("cannot write template. %w", err)
+	}
+
+	// Scaffolding is from the repository if it provides it for the runtime,
+	// otherwise the embedded scaffolding used by the builders.
+	scaffoldingFS := EmbeddedTemplatesFS
+	if checkDir(repo.FS(), path.Join(repo.TemplatesPath, f.Runtime, "scaffolding")) == nil {
+		scaffoldingFS = filesystem.NewSubFS(repo.TemplatesPath, repo.FS())
+	}
+	err = scaffolding.Write(filepath.Join(root, "scaffolding"), f.Root, f.Runtime, f.Invoke, scaffoldingFS)
+	var (
+		errNotRecognized     scaffolding.ErrRuntimeNotRecognized
+		errNotImplemented    scaffolding.ErrDetectorNotImplemented
+		runtimeNotScaffolded = errors.As(err, &errNotRecognized) || errors.As(err, &errNotImplemented)
+	)
+	if err != nil && !runtimeNotScaffolded {
+		return fmt.Errorf("not accepted by scaffolding. %w", err)
+	}
+
+	if oo.builder != nil && oo.buildable(f.Runtime) {
+		if err = oo.builder.Build(ctx, f, nil); err != nil {
+			return fmt.Errorf("build failed. %w", err)
+		}
+	}
+	if runtimeNotScaffolded {
+		return lintWarning{fmt.Sprintf("not checked against scaffolding, which does not support the %v runtime", f.Runtime)}
+	}
+	return nil
+}
+
+// lintWarning is returned by lintTemplate for templates which could not be
+// checked completely.
+type lintWarning struct {
+	msg string
+}
+
+func (w lintWarning) Error() string {
+	return w.msg
+}
@@ -0,0 +1,112 @@
+//go:build !integration
+// +build !integration
+
+package functions_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	fn "knative.dev/func/pkg/functions"
+	"knative.dev/func/pkg/mock"
+)
+
+// TestLintRepository_Embedded ensures the embedded repository has no issues
+// other than warnings for the templates of runtimes without scaffolding, and
+// that templates of buildable runtimes are built when requested.
+func TestLintRepository_Embedded(t *testing.T) {
+	built := []string{}
+	builder := mock.NewBuilder()
+	builder.BuildFn = func(f fn.Function) error {
+		built = append(built, f.Runtime+"/"+f.Template)
+		return nil
+	}
+	issues, err := fn.LintRepository(context.Background(), "",
+		fn.WithLintBuilder(builder, func(runtime string) bool { return runtime == "go" }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range issues {
+		if !i.Warning || !(strings.HasPrefix(i.Path, "quarkus/") || strings.HasPrefix(i.Path, "springboot/")) {
+			t.Fatalf("expected only warnings for runtimes without scaffolding, got %v", issues)
+		}
+	}
+	if len(built) != 2 {
+		t.Fatalf("expected the 2 go templates to be built, got %v", built)
+	}
+
+	// Build failures are issues
+	builder.BuildFn = func(fn.Function) error { return errors.New("does not compile") }
+	issues, err = fn.LintRepository(context.Background(), "",
+		fn.WithLintBuilder(builder, func(runtime string) bool { return runtime == "go" }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	failures := []fn.LintIssue{}
+	for _, i := range issues {
+		if !i.Warning {
+			failures = append(failures, i)
+		}
+	}
+	if len(failures) != 2 || !strings.Contains(failures[0].Message, "does not compile") {
+		t.Fatalf("expected 2 build failures, got %v", issues)
+	}
+}
+
+// TestLintRepository_Issues ensures that invalid manifests, runtimes without
+// templates, and templates not accepted by scaffolding are reported, as are
+// templates which can not be checked against scaffolding as warnings.
+func TestLintRepository_Issues(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"manifest.yaml":             "name: lint\nnmae: typo\n",
+		"go/http/manifest.yaml":     "invoke: grpc\nhooks:\n  postCreate:\n  - name: empty\n",
+		"go/http/handle.go":         "package function\n", // no go.mod or handler
+		"python/http/manifest.yaml": "healthEndpoints: {liveness: health}\nparameters:\n- name: a\n  default: x\n  choices: [y]\n- name: a\n",
+		"python/http/func.py":       "",
+		"node/.keep":                "",
+		"quarkus/http/pom.xml":      "<project/>\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issues, err := fn.LintRepository(context.Background(), "file://"+filepath.ToSlash(root))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int{}
+	for _, i := range issues {
+		got[i.Path]++
+		if i.Warning != (i.Path == "quarkus/http") {
+			t.Errorf("unexpected warning %v for %v", i.Warning, i.Path)
+		}
+	}
+	want := map[string]int{
+		"manifest.yaml":             1, // unknown field
+		"go/http/manifest.yaml":     2, // invalid invoke, hook without command
+		"go/http":                   1, // not accepted by scaffolding
+		"python/http/manifest.yaml": 3, // relative endpoint, default not a choice, duplicate
+		"python/http":               1, // not accepted by scaffolding
+		"node":                      1, // no templates
+		"quarkus/http":              1, // not checked by scaffolding
+	}
+	for path, n := range want {
+		if got[path] != n {
+			t.Errorf("expected %v issues for %v, got %v", n, path, got[path])
+		}
+	}
+	if t.Failed() {
+		t.Logf("issues: %v", issues)
+	}
+}
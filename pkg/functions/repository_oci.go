@@ -0,0 +1,137 @@
+package functions
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"knative.dev/func/pkg/filesystem"
+)
+
+// ociScheme prefixes the URI of a template repository distributed as an OCI
+// artifact, for example oci://registry.example.com/templates:v2.  The
+// artifact's layers contain the repository's files, with the repository's
+// root at the root of the layers.  For example, to publish a repository
+// using crane:
+//
+//	tar -C ./templates -czf templates.tar.gz .
+//	crane append -f templates.tar.gz -t registry.example.com/templates:v2
+const ociScheme = "oci://"
+
+// isOCIURI returns true if the uri references an OCI artifact.
+func isOCIURI(uri string) bool {
+	return strings.HasPrefix(uri, ociScheme)
+}
+
+// ociReference parses the image reference of an OCI repository uri.
+func ociReference(uri string) (name.Reference, error) {
+	ref, err := name.ParseReference(strings.TrimPrefix(uri, ociScheme))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI repository reference %q. %w", uri, err)
+	}
+	return ref, nil
+}
+
+// filesystemFromOCI pulls the OCI artifact referenced by uri, authenticating
+// using the default keychain (e.g. ~/.docker/config.json), and returns an
+// in-memory filesystem of the contents of its flattened layers.
+func filesystemFromOCI(uri string) (filesystem.Filesystem, error) {
+	ref, err := ociReference(uri)
+	if err != nil {
+		return nil, err
+	}
+	img, err := remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull repository %v. %w", ref, err)
+	}
+	rc := mutate.Extract(img)
+	defer rc.Close()
+
+	bb, err := zipFromTar(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract repository %v. %w", ref, err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(bb), int64(len(bb)))
+	if err != nil {
+		return nil, err
+	}
+	return filesystem.NewZipFS(archive), nil
+}
+
+// zipFromTar converts a tar stream into a zip archive with an explicit
+// entry for every directory, as expected by the zip filesystem.
+func zipFromTar(r io.Reader) ([]byte, error) {
+	var (
+		buf  = bytes.Buffer{}
+		zw   = zip.NewWriter(&buf)
+		tr   = tar.NewReader(r)
+		dirs = map[string]bool{".": true}
+	)
+	var addDir func(p string) error
+	addDir = func(p string) error {
+		if dirs[p] {
+			return nil
+		}
+		if err := addDir(path.Dir(p)); err != nil {
+			return err
+		}
+		dirs[p] = true
+		h := &zip.FileHeader{Name: p + "/"}
+		h.SetMode(fs.ModeDir | 0755)
+		_, err := zw.CreateHeader(h)
+		return err
+	}
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		p := path.Clean(strings.TrimPrefix(hdr.Name, "/"))
+		if p == "." || p == ".." || strings.HasPrefix(p, "../") {
+			continue
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err = addDir(p); err != nil {
+				return nil, err
+			}
+		case tar.TypeReg, tar.TypeSymlink:
+			if err = addDir(path.Dir(p)); err != nil {
+				return nil, err
+			}
+			h := &zip.FileHeader{Name: p, Method: zip.Deflate}
+			h.SetMode(hdr.FileInfo().Mode())
+			w, err := zw.CreateHeader(h)
+			if err != nil {
+				return nil, err
+			}
+			if hdr.Typeflag == tar.TypeSymlink {
+				_, err = io.WriteString(w, hdr.Linkname)
+			} else {
+				_, err = io.Copy(w, tr)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
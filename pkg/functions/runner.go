@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"time"
 )
 
@@ -146,6 +147,9 @@ func runGo(ctx context.Context, job *Job) (err error) {
 	//   A Cmd with a non-empty Dir field and nil Env now implicitly sets the PWD environment variable for the subprocess to match Dir.
 	//   The new method Cmd.Environ reports the environment that would be used to run the command, including the implicitly set PWD variable.
 	// cmd.Env = append(cmd.Environ(), "PORT="+job.Port) // requires go 1.19
+	if cmd.Env, err = runEnvs(job.Function); err != nil {
+		return
+	}
 	cmd.Env = append(cmd.Env, "PORT="+job.Port, "PWD="+cmd.Dir)
 
 	// Running asynchronously allows for the client Run method to return
@@ -219,6 +223,9 @@ func runPython(ctx context.Context, job *Job) (err error) {
 	//   PWD environment variable for the subprocess to match Dir.
 	//   The new method Cmd.Environ reports the environment that would be used
 	//   to run the command, including the implicitly set PWD variable.
+	if cmd.Env, err = runEnvs(job.Function); err != nil {
+		return
+	}
 	cmd.Env = append(cmd.Env, "PORT="+job.Port, "PWD="+cmd.Dir)
 
 	// Running asynchronously allows for the client Run method to return
@@ -329,3 +336,19 @@ func choosePort(iface, preferredPort string) (string, error) {
 	}
 	return port, nil
 }
+
+// runEnvs returns the function's environment variables, with references to
+// local environment variables interpolated, as a NAME=VALUE slice for use
+// with exec.Cmd.
+func runEnvs(f Function) ([]string, error) {
+	envs, err := Interpolate(f.Run.Envs)
+	if err != nil {
+		return nil, err
+	}
+	ss := make([]string, 0, len(envs))
+	for k, v := range envs {
+		ss = append(ss, k+"="+v)
+	}
+	sort.Strings(ss)
+	return ss, nil
+}
@@ -2,6 +2,7 @@ package functions
 
 import (
 	"context"
+	"fmt"
 	"path"
 
 	"knative.dev/func/pkg/filesystem"
@@ -23,6 +24,13 @@ type Template interface {
 	// to uniquely reference a template which may share a name
 	// with one in another repository.
 	Fullname() string
+	// Description of the template from its manifest.  May be empty.
+	Description() string
+	// Parameters declared by the template, the values of which are provided
+	// via Function.TemplateParams when writing.
+	Parameters() []TemplateParameter
+	// Hooks declared by the template, run when a function is created from it.
+	Hooks() TemplateHooks
 	// Write updates fields of function f and writes project files to path pointed by f.Root.
 	Write(ctx context.Context, f *Function) error
 }
@@ -52,10 +60,38 @@ func (t template) Fullname() string {
 	return t.repository + "/" + t.name
 }
 
+func (t template) Description() string {
+	return t.config.Description
+}
+
 // Write the template source files
 // (all source code except manifest.yaml and scaffolding)
 func (t template) Write(ctx context.Context, f *Function) error {
+	t.configure(f)
+
+	mask := func(p string) bool {
+		_, f := path.Split(p)
+		return f == manifestFile
+	}
+
+	// Templates which declare parameters are rendered rather than copied.
+	if len(t.config.Parameters) > 0 {
+		params, err := ValidateTemplateParams(t.config.Parameters, f.TemplateParams)
+		if err != nil {
+			return err
+		}
+		data := templateData{Name: f.Name, Runtime: f.Runtime, Params: params}
+		return renderFromFS(".", f.Root, filesystem.NewMaskingFS(mask, t.fs), data)
+	}
+	if len(f.TemplateParams) > 0 {
+		return fmt.Errorf("template %v does not declare parameters", t.Fullname())
+	}
 
+	return filesystem.CopyFromFS(".", f.Root, filesystem.NewMaskingFS(mask, t.fs)) // copy everything but manifest.yaml
+}
+
+// configure the function with the values defined by the template.
+func (t template) configure(f *Function) {
 	// Apply fields from the template onto the function itself (Denormalize).
 	// The template is already the denormalized view of repo->runtime->template
 	// so it's values are treated as defaults.
@@ -82,11 +118,4 @@ func (t template) Write(ctx context.Context, f *Function) error {
 	if f.Invoke == "" && t.config.Invoke != "http" {
 		f.Invoke = t.config.Invoke
 	}
-
-	mask := func(p string) bool {
-		_, f := path.Split(p)
-		return f == manifestFile
-	}
-
-	return filesystem.CopyFromFS(".", f.Root, filesystem.NewMaskingFS(mask, t.fs)) // copy everything but manifest.yaml
 }
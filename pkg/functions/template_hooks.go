@@ -0,0 +1,188 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// TemplateHooks are commands declared by a template in its manifest.yaml
+// which are run when creating a function from the template.
+type TemplateHooks struct {
+	// PostCreate hooks are run in order in the function's root after the
+	// template's files, and the function's func.yaml, have been written.
+	// For example 'go mod tidy', 'npm install' or 'git init'.
+	PostCreate []TemplateHook `yaml:"postCreate,omitempty"`
+}
+
+// TemplateHook is a command run when creating a function from a template.
+type TemplateHook struct {
+	// Name of the hook, presented when it is run.  Defaults to the command.
+	Name string `yaml:"name,omitempty"`
+
+	// Command to run, including its arguments, which are space-delimited.
+	// The command is run directly rather than via a shell.
+	Command string `yaml:"command"`
+}
+
+func (h TemplateHook) String() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return h.Command
+}
+
+// Hooks declared by the template.
+func (t template) Hooks() TemplateHooks {
+	return t.config.Hooks
+}
+
+// InitOption configures the initialization of a function.
+type InitOption func(*InitOptions)
+
+// InitOptions are the options with which a function is initialized.
+type InitOptions struct {
+	// NoHooks disables running the template's hooks.
+	NoHooks bool
+
+	// TrustHooks runs the hooks of templates from any repository without
+	// confirmation.
+	TrustHooks bool
+
+	// ConfirmHooks is invoked prior to running the hooks of a template which
+	// is not from the embedded repository.  Hooks are run only if it returns
+	// true.  When not provided, and the hooks are not trusted, initialization
+	// fails with ErrHooksNotTrusted.
+	ConfirmHooks func(t Template, hooks []TemplateHook) (bool, error)
+}
+
+// InitWithoutHooks disables running the template's hooks.
+func InitWithoutHooks() InitOption {
+	return func(o *InitOptions) {
+		o.NoHooks = true
+	}
+}
+
+// InitWithTrustedHooks runs the hooks of templates from any repository
+// without confirmation.
+func InitWithTrustedHooks() InitOption {
+	return func(o *InitOptions) {
+		o.TrustHooks = true
+	}
+}
+
+// InitWithHookConfirmation sets a function used to confirm that the hooks of a
+// template from a repository other than the embedded repository are to be run.
+func InitWithHookConfirmation(confirm func(t Template, hooks []TemplateHook) (bool, error)) InitOption {
+	return func(o *InitOptions) {
+		o.ConfirmHooks = confirm
+	}
+}
+
+// runPostCreateHooks of the function's template, if any, subject to the
+// given options.
+func (c *Client) runPostCreateHooks(ctx context.Context, f Function, oo InitOptions) error {
+	if oo.NoHooks {
+		return nil
+	}
+	t, err := c.Templates().Get(f.Runtime, f.Template)
+	if err != nil {
+		return err
+	}
+	hooks := t.Hooks().PostCreate
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	// Templates from repositories other than the embedded are confirmed.
+	embedded := false
+	repoName, _ := splitTemplateFullname(f.Template)
+	if r, err := c.Repositories().Get(repoName); err == nil {
+		embedded = r.uri == ""
+	}
+	if !embedded && !oo.TrustHooks {
+		if oo.ConfirmHooks == nil {
+			return fmt.Errorf("%w: %v", ErrHooksNotTrusted, t.Fullname())
+		}
+		confirmed, err := oo.ConfirmHooks(t, hooks)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintf(os.Stderr, "Skipping post-create hooks of template %v\n", t.Fullname())
+			return nil
+		}
+	}
+
+	for _, h := range hooks {
+		if err := runTemplateHook(ctx, f, h); err != nil {
+			return fmt.Errorf("post-create hook %q of template %v failed: %w", h, t.Fullname(), err)
+		}
+	}
+	return nil
+}
+
+// runTemplateHook in the function's root, streaming its output.
+func runTemplateHook(ctx context.Context, f Function, h TemplateHook) error {
+	args := strings.Fields(h.Command)
+	if len(args) == 0 {
+		return errors.New("hook has no command")
+	}
+	fmt.Fprintf(os.Stderr, "Running %v\n", h)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = f.Root
+	cmd.Env = append(os.Environ(),
+		"FUNC_NAME="+f.Name,
+		"FUNC_RUNTIME="+f.Runtime)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// rootSnapshot records the entries of a directory such that those added
+// subsequently can be removed.
+type rootSnapshot struct {
+	root    string
+	existed bool
+	entries map[string]bool
+}
+
+// snapshotRoot records the current entries of root, which need not exist.
+func snapshotRoot(root string) (s rootSnapshot, err error) {
+	s = rootSnapshot{root: root, entries: map[string]bool{}}
+	ee, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return
+	}
+	s.existed = true
+	for _, e := range ee {
+		s.entries[e.Name()] = true
+	}
+	return
+}
+
+// restore removes everything added to the root since the snapshot, including
+// the root itself if it did not previously exist.
+func (s rootSnapshot) restore() error {
+	if !s.existed {
+		return os.RemoveAll(s.root)
+	}
+	ee, err := os.ReadDir(s.root)
+	if err != nil {
+		return err
+	}
+	for _, e := range ee {
+		if !s.entries[e.Name()] {
+			if err = os.RemoveAll(filepath.Join(s.root, e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package functions
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"unicode/utf8"
+
+	"knative.dev/func/pkg/filesystem"
+)
+
+// TemplateParameter is a value which a template declares in its manifest.yaml
+// and which is provided when creating a function from that template.
+// Templates which declare parameters are rendered using Go text/template,
+// both file contents and file names, with the following data:
+//
+//	{{ .Name }}            the function's name
+//	{{ .Runtime }}         the function's language runtime
+//	{{ .Params.<name> }}   the value of the named parameter
+//
+// Files (or directories) whose name renders to the empty string are not
+// written, allowing for files to be included conditionally.
+type TemplateParameter struct {
+	// Name of the parameter.
+	Name string `yaml:"name"`
+
+	// Description of the parameter, presented when prompting for its value.
+	Description string `yaml:"description,omitempty"`
+
+	// Default value.  A parameter without a default is required.
+	Default string `yaml:"default,omitempty"`
+
+	// Choices to which the value is restricted, if any.
+	Choices []string `yaml:"choices,omitempty"`
+}
+
+// templateData is the data with which parameterized templates are rendered.
+type templateData struct {
+	Name    string
+	Runtime string
+	Params  map[string]string
+}
+
+// Parameters declared by the template.
+func (t template) Parameters() []TemplateParameter {
+	return t.config.Parameters
+}
+
+// ValidateTemplateParams returns the effective values of the declared
+// parameters given the provided values: defaults are applied, unknown and
+// missing required parameters are an error, as are values which are not one
+// of a parameter's choices.
+func ValidateTemplateParams(declared []TemplateParameter, given map[string]string) (map[string]string, error) {
+	values := make(map[string]string, len(declared))
+	known := make(map[string]bool, len(declared))
+	for _, p := range declared {
+		known[p.Name] = true
+		v, ok := given[p.Name]
+		if !ok {
+			if p.Default == "" {
+				return values, fmt.Errorf("template parameter %q is required", p.Name)
+			}
+			v = p.Default
+		}
+		if len(p.Choices) > 0 && !contains(p.Choices, v) {
+			return values, fmt.Errorf("invalid value %q for template parameter %q. Choices are %v", v, p.Name, p.Choices)
+		}
+		values[p.Name] = v
+	}
+	unknown := []string{}
+	for name := range given {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return values, fmt.Errorf("unknown template parameters %v", unknown)
+	}
+	return values, nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// renderFromFS writes the files of the filesystem rooted at root to dest,
+// rendering each file's path and contents as a Go text/template with the
+// given data.
+func renderFromFS(root, dest string, fsys filesystem.Filesystem, data templateData) error {
+	render := func(name, text string) (string, error) {
+		t, err := texttemplate.New(name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return "", err
+		}
+		b := bytes.Buffer{}
+		if err = t.Execute(&b, data); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+
+	return fs.WalkDir(fsys, root, func(p string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(filepath.FromSlash(root), filepath.FromSlash(p))
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return os.MkdirAll(dest, 0755)
+		}
+
+		// Render the path one segment at a time, skipping those which render
+		// to the empty string.
+		segments := strings.Split(filepath.ToSlash(rel), "/")
+		for i, s := range segments {
+			if segments[i], err = render(p, s); err != nil {
+				return fmt.Errorf("cannot render template file name %q. %w", p, err)
+			}
+			if segments[i] == "" {
+				if de.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+		}
+		target := filepath.Join(dest, filepath.FromSlash(path.Join(segments...)))
+
+		switch {
+		case de.IsDir():
+			return os.MkdirAll(target, 0755)
+		case de.Type()&fs.ModeSymlink != 0:
+			link, err := fsys.Readlink(p)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case de.Type().IsRegular():
+			fi, err := de.Info()
+			if err != nil {
+				return err
+			}
+			bb, err := fs.ReadFile(fsys, p)
+			if err != nil {
+				return err
+			}
+			// Binary files are written as-is.
+			if utf8.Valid(bb) && !bytes.ContainsRune(bb, 0) {
+				s, err := render(p, string(bb))
+				if err != nil {
+					return fmt.Errorf("cannot render template file %q. %w", p, err)
+				}
+				bb = []byte(s)
+			}
+			return os.WriteFile(target, bb, fi.Mode())
+		default:
+			return fmt.Errorf("unsuported file type: %s", de.Type().String())
+		}
+	})
+}
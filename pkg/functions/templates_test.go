@@ -97,6 +97,28 @@ func TestTemplates_Get(t *testing.T) {
 	}
 }
 
+// TestTemplates_Description ensures that a template's description is read
+// from its own manifest and is not inherited by templates without one.
+func TestTemplates_Description(t *testing.T) {
+	client := fn.New(fn.WithRepositoriesPath("testdata/repositories"))
+
+	embedded, err := client.Templates().Get("go", "http")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if embedded.Description() == "" {
+		t.Fatal("expected the embedded template to have a description")
+	}
+
+	extended, err := client.Templates().Get("go", "customTemplateRepo/customTemplate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extended.Description() != "" {
+		t.Fatalf("expected no description, got %q", extended.Description())
+	}
+}
+
 // TestTemplates_Embedded ensures that embedded templates are copied on write.
 func TestTemplates_Embedded(t *testing.T) {
 	// create test directory
@@ -594,3 +616,204 @@ func TestTemplates_InvocationDefault(t *testing.T) {
 		t.Fatalf("expected '%v' invoke format.  Got '%v'", expectedInvoke, f.Invoke)
 	}
 }
+
+// TestTemplates_Parameters ensures that templates which declare parameters
+// are rendered with the provided values (or their defaults), both file
+// contents and file names, and that invalid values are rejected.
+func TestTemplates_Parameters(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("template file names contain characters invalid on windows")
+	}
+	repos := t.TempDir()
+	tpl := filepath.Join(repos, "params", "go", "api")
+	if err := os.MkdirAll(tpl, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"manifest.yaml": `parameters:
+- name: greeting
+  description: The greeting returned
+  default: hello
+- name: db
+  choices: [postgres, none]
+`,
+		"handle.go":             "// {{ .Name }} says {{ .Params.greeting }}\n",
+		"{{ .Params.db }}.conf": "db={{ .Params.db }}\n",
+		`{{ if eq .Params.db "postgres" }}sql{{ end }}`: "",
+	}
+	for name, content := range files {
+		p := filepath.Join(tpl, name)
+		if name == `{{ if eq .Params.db "postgres" }}sql{{ end }}` {
+			if err := os.MkdirAll(p, 0755); err != nil {
+				t.Fatal(err)
+			}
+			p = filepath.Join(p, "001.sql")
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := fn.New(fn.WithRepositoriesPath(repos))
+
+	// The template's parameters are available
+	template, err := client.Templates().Get("go", "params/api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(template.Parameters()) != 2 {
+		t.Fatalf("expected 2 parameters, got %v", template.Parameters())
+	}
+
+	// A required parameter not provided is an error
+	if _, err = client.Init(fn.Function{Root: t.TempDir(), Runtime: "go", Template: "params/api"}); err == nil {
+		t.Fatal("expected missing required parameter to error")
+	}
+	// A value not among the choices is an error
+	if _, err = client.Init(fn.Function{Root: t.TempDir(), Runtime: "go", Template: "params/api",
+		TemplateParams: map[string]string{"db": "mysql"}}); err == nil {
+		t.Fatal("expected invalid choice to error")
+	}
+	// Unknown parameters are an error
+	if _, err = client.Init(fn.Function{Root: t.TempDir(), Runtime: "go", Template: "params/api",
+		TemplateParams: map[string]string{"db": "none", "other": "x"}}); err == nil {
+		t.Fatal("expected unknown parameter to error")
+	}
+
+	// Rendered with defaults and provided values
+	root := filepath.Join(t.TempDir(), "myfunc")
+	if _, err = client.Init(fn.Function{Root: root, Runtime: "go", Template: "params/api",
+		TemplateParams: map[string]string{"db": "none"}}); err != nil {
+		t.Fatal(err)
+	}
+	bb, err := os.ReadFile(filepath.Join(root, "handle.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bb) != "// myfunc says hello\n" {
+		t.Fatalf("unexpected rendered content %q", bb)
+	}
+	if _, err = os.Stat(filepath.Join(root, "none.conf")); err != nil {
+		t.Fatalf("expected rendered file name. %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(root, "sql")); !os.IsNotExist(err) {
+		t.Fatal("expected conditional directory to be skipped")
+	}
+
+	// Conditional directories are included when rendering to a name
+	root = filepath.Join(t.TempDir(), "myfunc")
+	if _, err = client.Init(fn.Function{Root: root, Runtime: "go", Template: "params/api",
+		TemplateParams: map[string]string{"db": "postgres", "greeting": "hi"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(root, "sql", "001.sql")); err != nil {
+		t.Fatalf("expected conditional directory. %v", err)
+	}
+}
+
+// TestTemplates_Hooks ensures that a template's post-create hooks are run
+// after the function is created, subject to confirmation, and that a failing
+// hook leaves no partially initialized function behind.
+func TestTemplates_Hooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks use touch and false")
+	}
+	repos := t.TempDir()
+	for tpl, manifest := range map[string]string{
+		"ok": `hooks:
+  postCreate:
+  - name: mark
+    command: touch hooked
+`,
+		"failing": `hooks:
+  postCreate:
+  - command: "false"
+`,
+	} {
+		dir := filepath.Join(repos, "hooks", "go", tpl)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(manifest), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "handle.go"), []byte("package function\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := fn.New(fn.WithRepositoriesPath(repos))
+
+	// Hooks are run once confirmed, in the function's root
+	confirmed := false
+	root := filepath.Join(t.TempDir(), "myfunc")
+	_, err := client.Init(fn.Function{Root: root, Runtime: "go", Template: "hooks/ok"},
+		fn.InitWithHookConfirmation(func(_ fn.Template, hooks []fn.TemplateHook) (bool, error) {
+			confirmed = true
+			return len(hooks) == 1, nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !confirmed {
+		t.Fatal("expected hooks of a non-default repository to be confirmed")
+	}
+	if _, err = os.Stat(filepath.Join(root, "hooked")); err != nil {
+		t.Fatalf("expected hook to have been run. %v", err)
+	}
+
+	// Hooks are not run when declined, or when disabled
+	for _, option := range []fn.InitOption{
+		fn.InitWithHookConfirmation(func(fn.Template, []fn.TemplateHook) (bool, error) { return false, nil }),
+		fn.InitWithoutHooks(),
+	} {
+		root = filepath.Join(t.TempDir(), "myfunc")
+		if _, err = client.Init(fn.Function{Root: root, Runtime: "go", Template: "hooks/ok"}, option); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = os.Stat(filepath.Join(root, "hooked")); !os.IsNotExist(err) {
+			t.Fatalf("expected hook not to have been run. %v", err)
+		}
+	}
+
+	// Hooks which are neither confirmed nor trusted are refused
+	root = filepath.Join(t.TempDir(), "myfunc")
+	if _, err = client.Init(fn.Function{Root: root, Runtime: "go", Template: "hooks/ok"}); !errors.Is(err, fn.ErrHooksNotTrusted) {
+		t.Fatalf("expected ErrHooksNotTrusted, got %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(root, "hooked")); !os.IsNotExist(err) {
+		t.Fatalf("expected hook not to have been run. %v", err)
+	}
+
+	// Trusted hooks are run without confirmation
+	root = filepath.Join(t.TempDir(), "myfunc")
+	if _, err = client.Init(fn.Function{Root: root, Runtime: "go", Template: "hooks/ok"}, fn.InitWithTrustedHooks()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(root, "hooked")); err != nil {
+		t.Fatalf("expected hook to have been run. %v", err)
+	}
+
+	// A failing hook is an error and the function is removed
+	root = filepath.Join(t.TempDir(), "myfunc")
+	if _, err = client.Init(fn.Function{Root: root, Runtime: "go", Template: "hooks/failing"}, fn.InitWithTrustedHooks()); err == nil {
+		t.Fatal("expected a failing hook to error")
+	}
+	if _, err = os.Stat(root); !os.IsNotExist(err) {
+		t.Fatalf("expected partially initialized function to be removed. %v", err)
+	}
+
+	// Preexisting contents of the root are retained
+	root = t.TempDir()
+	if err = os.WriteFile(filepath.Join(root, ".keep"), []byte{}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Init(fn.Function{Root: root, Runtime: "go", Template: "hooks/failing"}, fn.InitWithTrustedHooks()); err == nil {
+		t.Fatal("expected a failing hook to error")
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != ".keep" {
+		t.Fatalf("expected only preexisting files to remain, got %v", entries)
+	}
+}
@@ -0,0 +1,239 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// WorkspaceFile is the name of the file which defines a workspace of
+	// functions.
+	WorkspaceFile = "func-workspace.yaml"
+
+	// DefaultWorkspaceConcurrency is the number of functions of a workspace
+	// operated upon concurrently when not defined by the workspace.
+	DefaultWorkspaceConcurrency = 4
+)
+
+// Workspace is a set of functions which are managed together, such as
+// the functions of a repository.  A workspace is defined by a WorkspaceFile
+// at its root, which lists the function roots (paths or glob patterns
+// relative to the workspace root).  If no functions are listed, all
+// functions found beneath the workspace root are included.
+//
+// Settings defined on the workspace, such as registry and namespace, cascade
+// to each of its functions which do not define their own.
+type Workspace struct {
+	// Root of the workspace.  Not persisted.
+	Root string `yaml:"-"`
+
+	// Functions are paths or glob patterns (relative to Root) of the
+	// directories containing the workspace's functions.
+	Functions []string `yaml:"functions,omitempty"`
+
+	// Registry to use for functions which do not define their own.
+	Registry string `yaml:"registry,omitempty"`
+
+	// Namespace to use for functions which do not define their own.
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// Concurrency is the maximum number of functions operated upon at once.
+	Concurrency int `yaml:"concurrency,omitempty"`
+}
+
+// NewWorkspace loads the workspace rooted at the given path.  If no
+// WorkspaceFile exists, the zero value workspace (which discovers all
+// functions beneath root) is returned.
+func NewWorkspace(root string) (w Workspace, err error) {
+	if root == "" {
+		if root, err = os.Getwd(); err != nil {
+			return
+		}
+	}
+	if w.Root, err = filepath.Abs(root); err != nil {
+		return
+	}
+	bb, err := os.ReadFile(filepath.Join(w.Root, WorkspaceFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return w, nil
+	} else if err != nil {
+		return
+	}
+	if err = yaml.Unmarshal(bb, &w); err != nil {
+		return w, fmt.Errorf("cannot parse %v. %w", WorkspaceFile, err)
+	}
+	return
+}
+
+// Write the workspace to its WorkspaceFile.
+func (w Workspace) Write() error {
+	bb, err := yaml.Marshal(&w)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(w.Root, WorkspaceFile), bb, 0644)
+}
+
+// Roots returns the absolute, sorted, unique paths of the function roots of
+// the workspace.
+func (w Workspace) Roots() ([]string, error) {
+	seen := make(map[string]bool)
+	roots := []string{}
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			roots = append(roots, path)
+		}
+	}
+
+	if len(w.Functions) == 0 {
+		err := filepath.WalkDir(w.Root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && path != w.Root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			if !d.IsDir() && d.Name() == FunctionFile {
+				add(filepath.Dir(path))
+			}
+			return nil
+		})
+		sort.Strings(roots)
+		return roots, err
+	}
+
+	for _, pattern := range w.Functions {
+		matches, err := filepath.Glob(filepath.Join(w.Root, pattern))
+		if err != nil {
+			return roots, fmt.Errorf("invalid function pattern %q. %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return roots, fmt.Errorf("no functions found matching %q", pattern)
+		}
+		for _, m := range matches {
+			if fi, err := os.Stat(m); err == nil && fi.IsDir() {
+				if _, err := os.Stat(filepath.Join(m, FunctionFile)); err == nil {
+					add(m)
+				}
+			}
+		}
+	}
+	sort.Strings(roots)
+	return roots, nil
+}
+
+// Load the functions of the workspace, with workspace settings applied.
+func (w Workspace) Load() ([]Function, error) {
+	roots, err := w.Roots()
+	if err != nil {
+		return nil, err
+	}
+	ff := make([]Function, 0, len(roots))
+	for _, root := range roots {
+		f, err := NewFunction(root)
+		if err != nil {
+			return ff, err
+		}
+		if !f.Initialized() {
+			return ff, NewErrNotInitialized(root)
+		}
+		ff = append(ff, w.Apply(f))
+	}
+	return ff, nil
+}
+
+// Apply the workspace's settings to the function for those values the
+// function does not define itself.
+func (w Workspace) Apply(f Function) Function {
+	if f.Registry == "" && f.Image == "" {
+		f.Registry = w.Registry
+	}
+	if f.Namespace == "" && f.Deploy.Namespace == "" {
+		f.Namespace = w.Namespace
+	}
+	return f
+}
+
+// ErrWorkspace aggregates the errors encountered operating on the functions
+// of a workspace, keyed by function root.
+type ErrWorkspace struct {
+	Errors map[string]error
+}
+
+func (e ErrWorkspace) Error() string {
+	roots := make([]string, 0, len(e.Errors))
+	for root := range e.Errors {
+		roots = append(roots, root)
+	}
+	sort.Strings(roots)
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "%v of the workspace's functions failed:", len(e.Errors))
+	for _, root := range roots {
+		fmt.Fprintf(&b, "\n  %v: %v", root, e.Errors[root])
+	}
+	return b.String()
+}
+
+// Unwrap the individual errors.
+func (e ErrWorkspace) Unwrap() []error {
+	ee := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		ee = append(ee, err)
+	}
+	return ee
+}
+
+// ForEach runs the task for each function, at most concurrency at a time.
+// All functions are attempted regardless of individual failures.  Returned
+// are the functions as returned by the task (in the order given) and, if any
+// task failed, an ErrWorkspace.
+func ForEach(ctx context.Context, ff []Function, concurrency int, task func(context.Context, Function) (Function, error)) ([]Function, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultWorkspaceConcurrency
+	}
+	var (
+		results = make([]Function, len(ff))
+		errs    = make(map[string]error)
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, concurrency)
+	)
+	for i, f := range ff {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			root := f.Root
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				mu.Lock()
+				results[i], errs[root] = f, ctx.Err()
+				mu.Unlock()
+				return
+			}
+			f, err := task(ctx, f)
+			mu.Lock()
+			results[i] = f
+			if err != nil {
+				errs[root] = err
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return results, ErrWorkspace{Errors: errs}
+	}
+	return results, nil
+}
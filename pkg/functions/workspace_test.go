@@ -0,0 +1,117 @@
+//go:build !integration
+// +build !integration
+
+package functions_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fn "knative.dev/func/pkg/functions"
+	. "knative.dev/func/pkg/testing"
+)
+
+// TestWorkspace_Discovery ensures that a workspace without a list of
+// functions includes all functions beneath its root, and that workspace
+// settings cascade to functions which do not define their own.
+func TestWorkspace_Discovery(t *testing.T) {
+	root, cleanup := Mktemp(t)
+	defer cleanup()
+
+	client := fn.New()
+	if _, err := client.Init(fn.Function{Runtime: TestRuntime, Root: filepath.Join(root, "a")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Init(fn.Function{Runtime: TestRuntime, Root: filepath.Join(root, "nested", "b"), Registry: "example.com/bob"}); err != nil {
+		t.Fatal(err)
+	}
+
+	w := fn.Workspace{Root: root, Registry: TestRegistry, Namespace: TestNamespace}
+	if err := w.Write(); err != nil {
+		t.Fatal(err)
+	}
+	w, err := fn.NewWorkspace(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ff, err := w.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ff) != 2 {
+		t.Fatalf("expected 2 functions, got %v", len(ff))
+	}
+	if ff[0].Name != "a" || ff[1].Name != "b" {
+		t.Fatalf("unexpected functions %q, %q", ff[0].Name, ff[1].Name)
+	}
+	if ff[0].Registry != TestRegistry || ff[0].Namespace != TestNamespace {
+		t.Fatalf("workspace settings not applied: registry %q namespace %q", ff[0].Registry, ff[0].Namespace)
+	}
+	if ff[1].Registry != "example.com/bob" {
+		t.Fatalf("function registry should take precedence, got %q", ff[1].Registry)
+	}
+}
+
+// TestWorkspace_Patterns ensures that only the functions matching the
+// workspace's function patterns are included.
+func TestWorkspace_Patterns(t *testing.T) {
+	root, cleanup := Mktemp(t)
+	defer cleanup()
+
+	client := fn.New()
+	for _, name := range []string{"services/a", "services/b", "other/c"} {
+		if _, err := client.Init(fn.Function{Runtime: TestRuntime, Root: filepath.Join(root, name)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, fn.WorkspaceFile), []byte("functions:\n- services/*\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := fn.NewWorkspace(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots, err := w.Roots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(roots) != 2 || filepath.Base(roots[0]) != "a" || filepath.Base(roots[1]) != "b" {
+		t.Fatalf("unexpected function roots %v", roots)
+	}
+
+	// A pattern which matches nothing is an error
+	w.Functions = []string{"missing/*"}
+	if _, err = w.Roots(); err == nil {
+		t.Fatal("expected an error for a pattern matching no functions")
+	}
+}
+
+// TestWorkspace_ForEach ensures that all functions are attempted and that
+// errors are aggregated per function.
+func TestWorkspace_ForEach(t *testing.T) {
+	ff := []fn.Function{{Root: "/a"}, {Root: "/b"}, {Root: "/c"}}
+	errFailed := errors.New("failed")
+
+	results, err := fn.ForEach(context.Background(), ff, 2, func(_ context.Context, f fn.Function) (fn.Function, error) {
+		f.Name = filepath.Base(f.Root)
+		if f.Root == "/b" {
+			return f, errFailed
+		}
+		return f, nil
+	})
+	var errWorkspace fn.ErrWorkspace
+	if !errors.As(err, &errWorkspace) {
+		t.Fatalf("expected ErrWorkspace, got %v", err)
+	}
+	if len(errWorkspace.Errors) != 1 || !errors.Is(err, errFailed) {
+		t.Fatalf("unexpected errors %v", errWorkspace.Errors)
+	}
+	for i, name := range []string{"a", "b", "c"} {
+		if results[i].Name != name {
+			t.Fatalf("expected result %v to be %q, got %q", i, name, results[i].Name)
+		}
+	}
+}
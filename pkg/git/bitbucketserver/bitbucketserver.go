@@ -0,0 +1,157 @@
+package bitbucketserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type Client struct {
+	BaseURL             string
+	PersonalAccessToken string
+}
+
+type webhook struct {
+	Name          string            `json:"name"`
+	URL           string            `json:"url"`
+	Active        bool              `json:"active"`
+	Events        []string          `json:"events"`
+	Configuration map[string]string `json:"configuration,omitempty"`
+}
+
+// CreateWebHook creates a webhook on the repository of the given project.
+func (c Client) CreateWebHook(ctx context.Context, projectKey, repoSlug, payloadURL, webhookSecret string) error {
+	hooksURL := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/webhooks", c.BaseURL, url.PathEscape(projectKey), url.PathEscape(repoSlug))
+
+	var hooks struct {
+		Values []webhook `json:"values"`
+	}
+	if err := c.do(ctx, http.MethodGet, hooksURL+"?limit=1000", nil, http.StatusOK, &hooks); err != nil {
+		return fmt.Errorf("cannot list bitbucket webhooks: %w", err)
+	}
+	for _, h := range hooks.Values {
+		if h.URL == payloadURL {
+			return fmt.Errorf("hook already exists on repository %v/%v", projectKey, repoSlug)
+		}
+	}
+
+	h := webhook{
+		Name:   "Pipelines as Code",
+		URL:    payloadURL,
+		Active: true,
+		Events: []string{
+			"repo:refs_changed",
+			"pr:opened",
+			"pr:from_ref_updated",
+			"pr:comment:added",
+		},
+		Configuration: map[string]string{
+			"secret": webhookSecret,
+		},
+	}
+	if err := c.do(ctx, http.MethodPost, hooksURL, h, http.StatusCreated, nil); err != nil {
+		return fmt.Errorf("cannot create bitbucket webhook: %w", err)
+	}
+	return nil
+}
+
+func (c Client) do(ctx context.Context, method, reqURL string, body any, expectedStatus int, result any) error {
+	var reqBody io.Reader
+	if body != nil {
+		bb, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(bb)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.PersonalAccessToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != expectedStatus {
+		payload, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("%v %v: status code: %v, error: %s", method, reqURL, res.StatusCode, payload)
+	}
+	if result != nil {
+		return json.NewDecoder(res.Body).Decode(result)
+	}
+	return nil
+}
+
+// ParseRepoURL returns the base URL of the server along with the project key
+// and repository slug for either the clone URL of a repository
+// (https://host/scm/PROJECT/repo.git) or its web URL
+// (https://host/projects/PROJECT/repos/repo/browse).
+func ParseRepoURL(repoURL string) (baseURL, projectKey, repoSlug string, err error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("cannot parse git repo url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", "", fmt.Errorf("bitbucket repository url %q is not a http(s) url", repoURL)
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	var prefix string
+	var parts []string
+	if i := strings.Index(path, "/scm/"); i >= 0 {
+		prefix, parts = path[:i], strings.Split(path[i+len("/scm/"):], "/")
+	} else if i = strings.Index(path, "/projects/"); i >= 0 {
+		prefix, parts = path[:i], strings.Split(path[i+len("/projects/"):], "/")
+		if len(parts) >= 3 && parts[1] == "repos" {
+			parts = []string{parts[0], parts[2]}
+		}
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("invalid bitbucket repository url %q, needs to be of format 'https://host/scm/PROJECT/repo.git'", repoURL)
+	}
+
+	baseURL = u.Scheme + "://" + u.Host + prefix
+	return baseURL, strings.ToUpper(parts[0]), strings.TrimSuffix(parts[1], ".git"), nil
+}
+
+// RepositoryURL returns the web URL of the repository, as reported in the
+// webhook events of the server.
+func RepositoryURL(baseURL, projectKey, repoSlug string) string {
+	return fmt.Sprintf("%s/projects/%s/repos/%s", baseURL, projectKey, repoSlug)
+}
+
+// Detect returns whether the server at baseURL is a Bitbucket Server (Data
+// Center) instance, as indicated by its application properties.
+func Detect(ctx context.Context, baseURL string) bool {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/rest/api/1.0/application-properties", nil)
+	if err != nil {
+		return false
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return false
+	}
+	var props struct {
+		DisplayName string `json:"displayName"`
+	}
+	return json.NewDecoder(res.Body).Decode(&props) == nil && strings.HasPrefix(props.DisplayName, "Bitbucket")
+}
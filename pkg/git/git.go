@@ -9,19 +9,23 @@ import (
 
 	"github.com/openshift-pipelines/pipelines-as-code/pkg/formatting"
 
+	"knative.dev/func/pkg/git/bitbucketserver"
+	"knative.dev/func/pkg/git/gitea"
 	"knative.dev/func/pkg/git/github"
 	"knative.dev/func/pkg/git/gitlab"
 )
 
 const (
-	GitHubProvider    = "github"
-	GitLabProvider    = "gitlab"
-	BitBucketProvider = "bitbucket-cloud"
+	GitHubProvider          = "github"
+	GitLabProvider          = "gitlab"
+	GiteaProvider           = "gitea"
+	BitBucketProvider       = "bitbucket-cloud"
+	BitbucketServerProvider = "bitbucket-server"
 )
 
 type SupportedProviders []string
 
-var SupportedProvidersList = SupportedProviders{GitHubProvider, GitLabProvider}
+var SupportedProvidersList = SupportedProviders{GitHubProvider, GitLabProvider, GiteaProvider, BitbucketServerProvider}
 
 func (sp SupportedProviders) PrettyString() string {
 	var b strings.Builder
@@ -43,12 +47,62 @@ func GitProviderName(url string) (string, error) {
 		return GitHubProvider, nil
 	case strings.Contains(url, "gitlab"):
 		return GitLabProvider, nil
+	case strings.Contains(url, "gitea"):
+		return GiteaProvider, nil
+	case strings.Contains(url, "/scm/"):
+		// clone URLs of Bitbucket Server have the form https://host/scm/PROJECT/repo.git
+		return BitbucketServerProvider, nil
 	case strings.Contains(url, "bitbucket-cloud"):
 		//return BitBucketProvider, nil
 	}
 	return "", fmt.Errorf("runtime for url %q is not supported, please use one of supported runtimes: %s", url, SupportedProvidersList.PrettyString())
 }
 
+// DetectGitProvider returns the name of the Git provider hosting the
+// repository at url.  When the name can not be determined from the url alone,
+// as is usual for self-hosted instances, the server is asked whether it is
+// a Gitea or Bitbucket Server instance.
+func DetectGitProvider(ctx context.Context, gitRepoURL string) (string, error) {
+	provider, err := GitProviderName(gitRepoURL)
+	if err == nil {
+		return provider, nil
+	}
+
+	u, perr := url.Parse(gitRepoURL)
+	if perr != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", err
+	}
+	baseURL := u.Scheme + "://" + u.Host
+	if bitbucketserver.Detect(ctx, baseURL) {
+		return BitbucketServerProvider, nil
+	}
+	if gitea.Detect(ctx, baseURL) {
+		return GiteaProvider, nil
+	}
+	return "", err
+}
+
+// PACRepositoryURLs returns the URL of the repository as expected by the
+// Pipelines as Code Repository resource for the given provider, along with the
+// URL of the provider's API, which is empty when not required.
+func PACRepositoryURLs(provider, gitRepoURL string) (repoURL, providerURL string, err error) {
+	switch provider {
+	case GiteaProvider:
+		u, err := url.Parse(gitRepoURL)
+		if err != nil {
+			return "", "", fmt.Errorf("cannot parse git repo url: %w", err)
+		}
+		return strings.TrimSuffix(gitRepoURL, ".git"), u.Scheme + "://" + u.Host, nil
+	case BitbucketServerProvider:
+		baseURL, projectKey, repoSlug, err := bitbucketserver.ParseRepoURL(gitRepoURL)
+		if err != nil {
+			return "", "", err
+		}
+		return bitbucketserver.RepositoryURL(baseURL, projectKey, repoSlug), baseURL + "/rest", nil
+	}
+	return gitRepoURL, "", nil
+}
+
 // RepoOwnerAndNameFromUrl for input url returns repo owner and repo name
 // eg. for github.com/foo/bar returns 'foo' and 'bar'
 func RepoOwnerAndNameFromUrl(url string) (string, string, error) {
@@ -71,10 +125,14 @@ func RepoOwnerAndNameFromUrl(url string) (string, string, error) {
 	return repoOwner, repoName, nil
 }
 
-func CreateWebHook(ctx context.Context, gitRepoURL, webHookTarget, webHookSecret, personalAccessToken string) error {
-	providerName, err := GitProviderName(gitRepoURL)
-	if err != nil {
-		return err
+// CreateWebHook creates a webhook with the given target and secret on the
+// repository.  If provider is empty, it is determined from the url.
+func CreateWebHook(ctx context.Context, provider, gitRepoURL, webHookTarget, webHookSecret, personalAccessToken string) error {
+	var err error
+	if provider == "" {
+		if provider, err = GitProviderName(gitRepoURL); err != nil {
+			return err
+		}
 	}
 
 	u, err := url.Parse(gitRepoURL)
@@ -82,8 +140,11 @@ func CreateWebHook(ctx context.Context, gitRepoURL, webHookTarget, webHookSecret
 		return fmt.Errorf("cannot parse git repo url: %w", err)
 	}
 
-	var cli providerClient
-	switch providerName {
+	var (
+		cli                 providerClient
+		repoOwner, repoName string
+	)
+	switch provider {
 	case GitHubProvider:
 		cli = github.Client{
 			PersonalAccessToken: personalAccessToken,
@@ -93,11 +154,28 @@ func CreateWebHook(ctx context.Context, gitRepoURL, webHookTarget, webHookSecret
 			BaseURL:             u.Scheme + "://" + u.Host,
 			PersonalAccessToken: personalAccessToken,
 		}
+	case GiteaProvider:
+		cli = gitea.Client{
+			BaseURL:             u.Scheme + "://" + u.Host,
+			PersonalAccessToken: personalAccessToken,
+		}
+	case BitbucketServerProvider:
+		var baseURL string
+		if baseURL, repoOwner, repoName, err = bitbucketserver.ParseRepoURL(gitRepoURL); err != nil {
+			return err
+		}
+		cli = bitbucketserver.Client{
+			BaseURL:             baseURL,
+			PersonalAccessToken: personalAccessToken,
+		}
+	default:
+		return fmt.Errorf("git provider %q is not supported, please use one of supported providers: %s", provider, SupportedProvidersList.PrettyString())
 	}
 
-	repoOwner, repoName, err := RepoOwnerAndNameFromUrl(gitRepoURL)
-	if err != nil {
-		return err
+	if repoOwner == "" {
+		if repoOwner, repoName, err = RepoOwnerAndNameFromUrl(gitRepoURL); err != nil {
+			return err
+		}
 	}
 
 	err = cli.CreateWebHook(ctx, repoOwner, repoName, webHookTarget, webHookSecret)
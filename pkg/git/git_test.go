@@ -1,6 +1,13 @@
 package git
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
 
 func TestGetRepoOwnerFromGHURL(t *testing.T) {
 	tests := []struct {
@@ -73,6 +80,18 @@ func TestGitProviderName(t *testing.T) {
 			wantProvider: GitLabProvider,
 			wantErr:      false,
 		},
+		{
+			name:         "Gitea",
+			url:          "https://gitea.example.com/foo/bar.git",
+			wantProvider: GiteaProvider,
+			wantErr:      false,
+		},
+		{
+			name:         "Bitbucket Server",
+			url:          "https://git.example.com/scm/FOO/bar.git",
+			wantProvider: BitbucketServerProvider,
+			wantErr:      false,
+		},
 		{
 			name: "Bitbucket Cloud - not supported",
 			url:  "https://bitbucket.com/foo/bar",
@@ -105,3 +124,137 @@ func TestGitProviderName(t *testing.T) {
 		})
 	}
 }
+
+// fakeProvider is a Git provider API serving the given endpoints, recording
+// the webhooks created.
+type fakeProvider struct {
+	*httptest.Server
+	hooks []map[string]any
+}
+
+func newFakeProvider(t *testing.T, endpoints map[string]any, hooksPath, hooksKey string) *fakeProvider {
+	t.Helper()
+	p := &fakeProvider{}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == hooksPath {
+			if !strings.HasSuffix(r.Header.Get("Authorization"), "s3cr3t-token") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			switch r.Method {
+			case http.MethodGet:
+				var res any = p.hooks
+				if hooksKey != "" {
+					res = map[string]any{hooksKey: p.hooks}
+				}
+				_ = json.NewEncoder(w).Encode(res)
+			case http.MethodPost:
+				hook := map[string]any{}
+				if err := json.NewDecoder(r.Body).Decode(&hook); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				p.hooks = append(p.hooks, hook)
+				w.WriteHeader(http.StatusCreated)
+			}
+			return
+		}
+		if res, ok := endpoints[r.URL.Path]; ok {
+			_ = json.NewEncoder(w).Encode(res)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(p.Close)
+	return p
+}
+
+// TestCreateWebHook_Gitea ensures that a self-hosted Gitea is detected and
+// that a webhook is created on it only once.
+func TestCreateWebHook_Gitea(t *testing.T) {
+	srv := newFakeProvider(t, map[string]any{
+		"/api/v1/version": map[string]string{"version": "1.21.0"},
+	}, "/api/v1/repos/foo/bar/hooks", "")
+	repoURL := srv.URL + "/foo/bar.git"
+
+	provider, err := DetectGitProvider(context.Background(), repoURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider != GiteaProvider {
+		t.Fatalf("expected provider %q, got %q", GiteaProvider, provider)
+	}
+
+	if err = CreateWebHook(context.Background(), provider, repoURL, "https://pac.example.com", "hook-secret", "s3cr3t-token"); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.hooks) != 1 {
+		t.Fatalf("expected one webhook, got %v", srv.hooks)
+	}
+	config, _ := srv.hooks[0]["config"].(map[string]any)
+	if config["url"] != "https://pac.example.com" || config["secret"] != "hook-secret" {
+		t.Fatalf("unexpected webhook config %v", config)
+	}
+
+	err = CreateWebHook(context.Background(), provider, repoURL, "https://pac.example.com", "hook-secret", "s3cr3t-token")
+	if err == nil || !strings.Contains(err.Error(), "hook already exists") {
+		t.Fatalf("expected an error for an existing webhook, got %v", err)
+	}
+}
+
+// TestCreateWebHook_BitbucketServer ensures that a Bitbucket Server is
+// detected and that a webhook is created on the repository of the project.
+func TestCreateWebHook_BitbucketServer(t *testing.T) {
+	srv := newFakeProvider(t, map[string]any{
+		"/rest/api/1.0/application-properties": map[string]string{"displayName": "Bitbucket"},
+	}, "/rest/api/1.0/projects/FOO/repos/bar/webhooks", "values")
+	repoURL := srv.URL + "/projects/FOO/repos/bar/browse"
+
+	provider, err := DetectGitProvider(context.Background(), repoURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider != BitbucketServerProvider {
+		t.Fatalf("expected provider %q, got %q", BitbucketServerProvider, provider)
+	}
+
+	if err = CreateWebHook(context.Background(), provider, repoURL, "https://pac.example.com", "hook-secret", "s3cr3t-token"); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.hooks) != 1 || srv.hooks[0]["url"] != "https://pac.example.com" {
+		t.Fatalf("unexpected webhooks %v", srv.hooks)
+	}
+}
+
+// TestDetectGitProvider_Unknown ensures that a server which is neither Gitea
+// nor Bitbucket Server is not supported.
+func TestDetectGitProvider_Unknown(t *testing.T) {
+	srv := newFakeProvider(t, nil, "/", "")
+	if _, err := DetectGitProvider(context.Background(), srv.URL+"/foo/bar.git"); err == nil {
+		t.Fatal("expected an error for an unknown provider")
+	}
+}
+
+func TestPACRepositoryURLs(t *testing.T) {
+	tests := []struct {
+		provider        string
+		url             string
+		wantRepoURL     string
+		wantProviderURL string
+	}{
+		{GitHubProvider, "https://github.com/foo/bar.git", "https://github.com/foo/bar.git", ""},
+		{GiteaProvider, "https://gitea.example.com/foo/bar.git", "https://gitea.example.com/foo/bar", "https://gitea.example.com"},
+		{BitbucketServerProvider, "https://example.com/bitbucket/scm/foo/bar.git", "https://example.com/bitbucket/projects/FOO/repos/bar", "https://example.com/bitbucket/rest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			repoURL, providerURL, err := PACRepositoryURLs(tt.provider, tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repoURL != tt.wantRepoURL || providerURL != tt.wantProviderURL {
+				t.Errorf("PACRepositoryURLs() = %q, %q, want %q, %q", repoURL, providerURL, tt.wantRepoURL, tt.wantProviderURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type Client struct {
+	BaseURL             string
+	PersonalAccessToken string
+}
+
+type hook struct {
+	ID     int64             `json:"id,omitempty"`
+	Type   string            `json:"type"`
+	Active bool              `json:"active"`
+	Events []string          `json:"events"`
+	Config map[string]string `json:"config"`
+}
+
+func (c Client) CreateWebHook(ctx context.Context, repoOwner, repoName, payloadURL, webhookSecret string) error {
+	hooksURL := fmt.Sprintf("%s/api/v1/repos/%s/%s/hooks", c.BaseURL, url.PathEscape(repoOwner), url.PathEscape(repoName))
+
+	// Gitea does not reject duplicate webhooks, so check for an existing one
+	var hooks []hook
+	if err := c.do(ctx, http.MethodGet, hooksURL, nil, http.StatusOK, &hooks); err != nil {
+		return fmt.Errorf("cannot list gitea webhooks: %w", err)
+	}
+	for _, h := range hooks {
+		if h.Config["url"] == payloadURL {
+			return fmt.Errorf("hook already exists on repository %v/%v", repoOwner, repoName)
+		}
+	}
+
+	h := hook{
+		Type:   "gitea",
+		Active: true,
+		Events: []string{
+			"issue_comment",
+			"pull_request",
+			"push",
+		},
+		Config: map[string]string{
+			"url":          payloadURL,
+			"content_type": "json",
+			"secret":       webhookSecret,
+		},
+	}
+	if err := c.do(ctx, http.MethodPost, hooksURL, h, http.StatusCreated, nil); err != nil {
+		return fmt.Errorf("cannot create gitea webhook: %w", err)
+	}
+	return nil
+}
+
+func (c Client) do(ctx context.Context, method, reqURL string, body any, expectedStatus int, result any) error {
+	var reqBody io.Reader
+	if body != nil {
+		bb, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(bb)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "token "+c.PersonalAccessToken)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != expectedStatus {
+		payload, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("%v %v: status code: %v, error: %s", method, reqURL, res.StatusCode, payload)
+	}
+	if result != nil {
+		return json.NewDecoder(res.Body).Decode(result)
+	}
+	return nil
+}
+
+// Detect returns whether the server at baseURL is a Gitea instance, as
+// indicated by its version endpoint.
+func Detect(ctx context.Context, baseURL string) bool {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/api/v1/version", nil)
+	if err != nil {
+		return false
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return false
+	}
+	var version struct {
+		Version string `json:"version"`
+	}
+	return json.NewDecoder(res.Body).Decode(&version) == nil && version.Version != ""
+}
@@ -76,12 +76,44 @@ var TarImage = "ghcr.io/knative/func-utils:v2"
 
 // UploadToVolume uploads files (passed in form of tar stream) into volume.
 func UploadToVolume(ctx context.Context, content io.Reader, claimName, namespace string) error {
-	return runWithVolumeMounted(ctx, TarImage, []string{"sh", "-c", "umask 0000 && exec tar -xmf -"}, content, claimName, namespace)
+	return runWithVolumeMounted(ctx, TarImage, []string{"sh", "-c", "umask 0000 && exec tar -xmf -"}, content, nil, claimName, namespace)
+}
+
+// SyncToVolume incrementally updates the directory dir of the volume.  The
+// given exchange function is run with the output and input of a pod which
+// speaks the remote side of the filesync protocol for the directory.
+func SyncToVolume(ctx context.Context, dir, claimName, namespace string, exchange func(podOutput io.Reader, podInput io.Writer) error) error {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		err := runWithVolumeMounted(ctx, TarImage, []string{"sync", dir}, inR, outW, claimName, namespace)
+		if err == nil {
+			err = io.EOF
+		}
+		_ = outW.CloseWithError(err)
+		_ = inR.CloseWithError(err)
+		done <- err
+	}()
+
+	err := exchange(outR, inW)
+	_ = inW.CloseWithError(err)
+	runErr := <-done
+	if errors.Is(runErr, io.EOF) {
+		runErr = nil
+	}
+	if runErr != nil {
+		// The failure of the pod is more telling than that of the exchange.
+		return runErr
+	}
+	return err
 }
 
 // Runs a pod with given image, command and stdin
 // while having the volume mounted and working directory set to it.
-func runWithVolumeMounted(ctx context.Context, podImage string, podCommand []string, podInput io.Reader, claimName, namespace string) error {
+// Output of the pod is written to podOutput if not nil.
+func runWithVolumeMounted(ctx context.Context, podImage string, podCommand []string, podInput io.Reader, podOutput io.Writer, claimName, namespace string) error {
 	var err error
 
 	cliConf := GetClientConfig()
@@ -205,7 +237,11 @@ func runWithVolumeMounted(ctx context.Context, podImage string, podCommand []str
 	}()
 
 	var outBuff tsBuff
-	err = attach(ctx, client.CoreV1().RESTClient(), restConf, podName, namespace, podInput, &outBuff, &outBuff)
+	var out io.Writer = &outBuff
+	if podOutput != nil {
+		out = podOutput
+	}
+	err = attach(ctx, client.CoreV1().RESTClient(), restConf, podName, namespace, podInput, out, &outBuff)
 	if err != nil {
 		return fmt.Errorf("cannot attach stdio to the pod: %w", err)
 	}
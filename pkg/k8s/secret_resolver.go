@@ -0,0 +1,63 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	fn "knative.dev/func/pkg/functions"
+)
+
+// SecretResolver resolves secrets referenced by a function's environment
+// variables from the cluster of the current kubeconfig context, for use when
+// running the function locally.
+type SecretResolver struct {
+	namespace string
+}
+
+// NewSecretResolver is a fn.SecretResolverFactory for resolvers of type
+// fn.SecretResolverKubernetes.  Secrets are fetched from the namespace of the
+// spec, or that of the function if not specified, or that of the current
+// context if neither define one.
+func NewSecretResolver(spec fn.SecretResolverSpec, f fn.Function) (fn.SecretResolver, error) {
+	namespace := spec.Namespace
+	if namespace == "" {
+		namespace = f.Deploy.Namespace
+	}
+	if namespace == "" {
+		namespace = f.Namespace
+	}
+	return &SecretResolver{namespace: namespace}, nil
+}
+
+// Resolve the value of a key of the named secret.
+func (r *SecretResolver) Resolve(ctx context.Context, name, key string) (string, error) {
+	values, err := r.ResolveAll(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("%w: secret %q has no key %q", fn.ErrSecretNotFound, name, key)
+	}
+	return value, nil
+}
+
+// ResolveAll key/value pairs of the named secret.
+func (r *SecretResolver) ResolveAll(ctx context.Context, name string) (map[string]string, error) {
+	secret, err := GetSecret(ctx, name, r.namespace)
+	if k8serrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %v", fn.ErrSecretNotFound, err)
+	} else if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(secret.Data)+len(secret.StringData))
+	for k, v := range secret.Data {
+		values[k] = string(v)
+	}
+	for k, v := range secret.StringData {
+		values[k] = v
+	}
+	return values, nil
+}
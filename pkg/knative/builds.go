@@ -0,0 +1,178 @@
+package knative
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	fn "knative.dev/func/pkg/functions"
+	"knative.dev/func/pkg/k8s"
+	fnlabels "knative.dev/func/pkg/k8s/labels"
+)
+
+// Annotations of the Knative Service of a function, and of the Events
+// recorded for it, identifying the on-cluster build it was deployed from.
+const (
+	GitRevisionAnnotation    = "function.knative.dev/git-revision"
+	PipelineRunAnnotation    = "function.knative.dev/pipeline-run"
+	BuildTimestampAnnotation = "function.knative.dev/build-timestamp"
+)
+
+// Reasons of the Events recording the outcome of on-cluster builds.
+const (
+	ReasonDeployed     = "Deployed"
+	ReasonDeployFailed = "DeployFailed"
+	ReasonBuildFailed  = "BuildFailed"
+)
+
+const eventSourceComponent = "func"
+
+// BuildAnnotations returns the annotations of the Knative Service of a
+// function deployed by an on-cluster build.
+func BuildAnnotations(b fn.BuildStatus) map[string]string {
+	aa := map[string]string{
+		BuildTimestampAnnotation: b.Time.UTC().Format(time.RFC3339),
+	}
+	if b.PipelineRun != "" {
+		aa[PipelineRunAnnotation] = b.PipelineRun
+	}
+	if b.GitRevision != "" {
+		aa[GitRevisionAnnotation] = b.GitRevision
+	}
+	return aa
+}
+
+// RecordBuild records the outcome of an on-cluster build of the named function
+// as an Event on its Knative Service, such that failures are visible even if
+// nobody watched the build, as is the case for builds triggered by Pipelines
+// as Code.  The Service need not exist, as when the first build fails.
+func RecordBuild(ctx context.Context, name, namespace string, b fn.BuildStatus) error {
+	client, namespace, err := k8s.NewClientAndResolvedNamespace(namespace)
+	if err != nil {
+		return err
+	}
+
+	// The UID ties the Event to the current Service, for instance for it to
+	// be shown by 'kubectl describe'
+	var uid types.UID
+	servingClient, err := NewServingClient(namespace)
+	if err != nil {
+		return err
+	}
+	service, err := servingClient.GetService(ctx, name)
+	if err == nil {
+		uid = service.UID
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	_, err = client.CoreV1().Events(namespace).Create(ctx, newBuildEvent(name, namespace, uid, b), metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("cannot record the build of function %q: %w", name, err)
+	}
+	return nil
+}
+
+// lastBuild returns the outcome of the newest on-cluster build of the function
+// of the given Service, or nil if it was not deployed by one.  If the Events
+// can not be listed, the outcome recorded by the annotations of the Service
+// is returned along with the error.
+func lastBuild(ctx context.Context, service *v1.Service) (*fn.BuildStatus, error) {
+	client, err := k8s.NewKubernetesClientset()
+	if err != nil {
+		return lastBuildStatus(service, nil), err
+	}
+	events, err := client.CoreV1().Events(service.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", fnlabels.FunctionNameKey, service.Name),
+	})
+	// Listing Events is commonly not permitted, in which case the annotations
+	// are used without further notice
+	if errors.IsForbidden(err) {
+		events, err = &corev1.EventList{}, nil
+	}
+	if err != nil {
+		return lastBuildStatus(service, nil), err
+	}
+	return lastBuildStatus(service, events.Items), nil
+}
+
+// newBuildEvent returns the Event recording the given build of the named
+// function.
+func newBuildEvent(name, namespace string, uid types.UID, b fn.BuildStatus) *corev1.Event {
+	eventType := corev1.EventTypeNormal
+	if !b.Succeeded {
+		eventType = corev1.EventTypeWarning
+	}
+	t := metav1.NewTime(b.Time)
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: name + "-",
+			Namespace:    namespace,
+			Labels:       map[string]string{fnlabels.FunctionNameKey: name},
+			Annotations:  BuildAnnotations(b),
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       "Service",
+			Name:       name,
+			Namespace:  namespace,
+			UID:        uid,
+		},
+		Reason:         b.Reason,
+		Message:        b.Message,
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: eventSourceComponent},
+		FirstTimestamp: t,
+		LastTimestamp:  t,
+		Count:          1,
+	}
+}
+
+// lastBuildStatus returns the outcome of the newest build out of the build
+// annotations of the Service and the recorded Events.  Events expire after
+// a while, whereas the annotations persist, but only record successful builds.
+func lastBuildStatus(service *v1.Service, events []corev1.Event) *fn.BuildStatus {
+	var last *fn.BuildStatus
+
+	if ts, ok := service.Annotations[BuildTimestampAnnotation]; ok {
+		t, err := time.Parse(time.RFC3339, ts)
+		if err == nil {
+			last = &fn.BuildStatus{
+				Succeeded:   true,
+				Reason:      ReasonDeployed,
+				PipelineRun: service.Annotations[PipelineRunAnnotation],
+				GitRevision: service.Annotations[GitRevisionAnnotation],
+				Time:        t,
+			}
+		}
+	}
+
+	var newest *corev1.Event
+	for i, e := range events {
+		if e.Source.Component != eventSourceComponent {
+			continue
+		}
+		if newest == nil || newest.LastTimestamp.Before(&e.LastTimestamp) {
+			newest = &events[i]
+		}
+	}
+	// The Event recording a successful build has the same timestamp as the
+	// annotations, which are preferred
+	if newest != nil && (last == nil || newest.LastTimestamp.Time.After(last.Time)) {
+		last = &fn.BuildStatus{
+			Succeeded:   newest.Type == corev1.EventTypeNormal,
+			Reason:      newest.Reason,
+			Message:     newest.Message,
+			PipelineRun: newest.Annotations[PipelineRunAnnotation],
+			GitRevision: newest.Annotations[GitRevisionAnnotation],
+			Time:        newest.LastTimestamp.Time,
+		}
+	}
+	return last
+}
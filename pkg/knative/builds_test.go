@@ -0,0 +1,88 @@
+//go:build !integration
+// +build !integration
+
+package knative
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	fn "knative.dev/func/pkg/functions"
+)
+
+// Test_lastBuildStatus ensures that the newest build is determined out of the
+// annotations of the Service and the recorded Events.
+func Test_lastBuildStatus(t *testing.T) {
+	deployed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	service := &v1.Service{ObjectMeta: metav1.ObjectMeta{
+		Name: "testfunc",
+		Annotations: BuildAnnotations(fn.BuildStatus{
+			PipelineRun: "testfunc-run-1",
+			GitRevision: "0123abc",
+			Time:        deployed,
+		}),
+	}}
+	event := func(run string, t time.Time, succeeded bool) corev1.Event {
+		return *newBuildEvent("testfunc", "default", "", fn.BuildStatus{
+			Succeeded:   succeeded,
+			Reason:      ReasonBuildFailed,
+			PipelineRun: run,
+			Time:        t,
+		})
+	}
+
+	tests := []struct {
+		name    string
+		service *v1.Service
+		events  []corev1.Event
+		want    *fn.BuildStatus
+	}{
+		{
+			name:    "not built on cluster",
+			service: &v1.Service{},
+		},
+		{
+			name:    "deployed",
+			service: service,
+			events:  []corev1.Event{event("testfunc-run-0", deployed.Add(-time.Hour), false)},
+			want:    &fn.BuildStatus{Succeeded: true, Reason: ReasonDeployed, PipelineRun: "testfunc-run-1", GitRevision: "0123abc", Time: deployed},
+		},
+		{
+			name:    "failed after deploy",
+			service: service,
+			events: []corev1.Event{
+				event("testfunc-run-3", deployed.Add(2*time.Hour), false),
+				event("testfunc-run-2", deployed.Add(time.Hour), false),
+			},
+			want: &fn.BuildStatus{Reason: ReasonBuildFailed, PipelineRun: "testfunc-run-3", Time: deployed.Add(2 * time.Hour)},
+		},
+		{
+			name:    "never deployed",
+			service: &v1.Service{},
+			events:  []corev1.Event{event("testfunc-run-1", deployed, false)},
+			want:    &fn.BuildStatus{Reason: ReasonBuildFailed, PipelineRun: "testfunc-run-1", Time: deployed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lastBuildStatus(tt.service, tt.events)
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+			if got == nil {
+				return
+			}
+			if !got.Time.Equal(tt.want.Time) {
+				t.Errorf("expected time %v, got %v", tt.want.Time, got.Time)
+			}
+			got.Time = tt.want.Time
+			if *got != *tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
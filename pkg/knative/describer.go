@@ -3,6 +3,7 @@ package knative
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	clientservingv1 "knative.dev/client/pkg/serving/v1"
@@ -67,6 +68,13 @@ func (d *Describer) Describe(ctx context.Context, name, namespace string) (descr
 	description.Route = primaryRouteURL
 	description.Routes = routeURLs
 
+	// The Events the last build is partly derived from are not essential to
+	// the description of a function
+	if description.LastBuild, err = lastBuild(ctx, service); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: unable to get the last build of function %q: %v\n", name, err)
+		err = nil
+	}
+
 	triggers, err := eventingClient.ListTriggers(ctx)
 	// IsNotFound -- Eventing is probably not installed on the cluster
 	if err != nil && !errors.IsNotFound(err) {
@@ -10,12 +10,22 @@ import (
 type PipelinesProvider struct {
 	RunInvoked          bool
 	RunFn               func(fn.Function) (string, fn.Function, error)
+	RerunInvoked        bool
+	RerunFn             func(fn.Function) (string, fn.Function, error)
 	RemoveInvoked       bool
 	RemoveFn            func(fn.Function) error
+	GenerateInvoked     bool
+	GenerateFn          func(fn.Function, string) ([]string, error)
 	ConfigurePACInvoked bool
 	ConfigurePACFn      func(fn.Function) error
 	RemovePACInvoked    bool
 	RemovePACFn         func(fn.Function) error
+	ListRunsInvoked     bool
+	ListRunsFn          func(fn.Function) ([]fn.PipelineRun, error)
+	DescribeRunInvoked  bool
+	DescribeRunFn       func(fn.Function, string) (fn.PipelineRun, error)
+	RunLogsInvoked      bool
+	RunLogsFn           func(f fn.Function, name, task string) (string, error)
 }
 
 func NewPipelinesProvider() *PipelinesProvider {
@@ -48,9 +58,14 @@ func NewPipelinesProvider() *PipelinesProvider {
 			return "", f, nil
 
 		},
+		RerunFn:        func(f fn.Function) (string, fn.Function, error) { return "", f, nil },
 		RemoveFn:       func(fn.Function) error { return nil },
+		GenerateFn:     func(fn.Function, string) ([]string, error) { return nil, nil },
 		ConfigurePACFn: func(fn.Function) error { return nil },
 		RemovePACFn:    func(fn.Function) error { return nil },
+		ListRunsFn:     func(fn.Function) ([]fn.PipelineRun, error) { return nil, nil },
+		DescribeRunFn:  func(fn.Function, string) (fn.PipelineRun, error) { return fn.PipelineRun{}, nil },
+		RunLogsFn:      func(fn.Function, string, string) (string, error) { return "", nil },
 	}
 }
 
@@ -59,11 +74,21 @@ func (p *PipelinesProvider) Run(ctx context.Context, f fn.Function) (string, fn.
 	return p.RunFn(f)
 }
 
+func (p *PipelinesProvider) Rerun(ctx context.Context, f fn.Function) (string, fn.Function, error) {
+	p.RerunInvoked = true
+	return p.RerunFn(f)
+}
+
 func (p *PipelinesProvider) Remove(ctx context.Context, f fn.Function) error {
 	p.RemoveInvoked = true
 	return p.RemoveFn(f)
 }
 
+func (p *PipelinesProvider) Generate(ctx context.Context, f fn.Function, dir string) ([]string, error) {
+	p.GenerateInvoked = true
+	return p.GenerateFn(f, dir)
+}
+
 func (p *PipelinesProvider) ConfigurePAC(ctx context.Context, f fn.Function, metadata any) error {
 	p.ConfigurePACInvoked = true
 	return p.ConfigurePACFn(f)
@@ -73,3 +98,18 @@ func (p *PipelinesProvider) RemovePAC(ctx context.Context, f fn.Function, metada
 	p.RemovePACInvoked = true
 	return p.RemovePACFn(f)
 }
+
+func (p *PipelinesProvider) ListRuns(ctx context.Context, f fn.Function) ([]fn.PipelineRun, error) {
+	p.ListRunsInvoked = true
+	return p.ListRunsFn(f)
+}
+
+func (p *PipelinesProvider) DescribeRun(ctx context.Context, f fn.Function, name string) (fn.PipelineRun, error) {
+	p.DescribeRunInvoked = true
+	return p.DescribeRunFn(f, name)
+}
+
+func (p *PipelinesProvider) RunLogs(ctx context.Context, f fn.Function, name, task string) (string, error) {
+	p.RunLogsInvoked = true
+	return p.RunLogsFn(f, name, task)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/pkg/errors"
 
+	fnbuilders "knative.dev/func/pkg/builders"
 	fn "knative.dev/func/pkg/functions"
 	"knative.dev/func/pkg/scaffolding"
 )
@@ -49,12 +50,27 @@ var builders = map[string]languageBuilder{
 	"python": pythonBuilder{},
 }
 
+// DefaultToolchainImages are the images, per runtime, which provide the
+// language toolchain used when the builder runs in-cluster rather than on
+// the local host.  They can be overridden per function via the "host" key
+// of the function's builderImages.
+var DefaultToolchainImages = map[string]string{
+	"go":     "docker.io/library/golang:1.23",
+	"python": defaultPythonBase,
+}
+
 // IsSupported is for UX.
 func IsSupported(runtime string) bool {
 	_, ok := builders[runtime]
 	return ok
 }
 
+// ToolchainImage returns the toolchain image to use when building the given
+// function remotely.
+func ToolchainImage(f fn.Function) (string, error) {
+	return fnbuilders.Image(f, fnbuilders.Host, DefaultToolchainImages)
+}
+
 type imageLayer struct {
 	Descriptor v1.Descriptor
 	Layer      v1.Layer
@@ -249,7 +265,10 @@ func scaffold(job buildJob) (err error) {
 		job.buildDir(),       // desintation for scaffolding
 		job.function.Root,    // source to be scaffolded
 		job.function.Runtime, // scaffolding language to write
-		job.function.Invoke, repo.FS())
+		job.function.Invoke, repo.FS(),
+		scaffolding.WithHealthEndpoints( // serve configured health checks
+			job.function.Deploy.HealthEndpoints.Readiness,
+			job.function.Deploy.HealthEndpoints.Liveness))
 }
 
 // containerize the full service which consists of the scaffolded Function,
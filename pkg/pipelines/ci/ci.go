@@ -0,0 +1,267 @@
+// Package ci generates workflows for hosted CI services which build and deploy
+// a function using the func CLI itself, as an alternative to Tekton pipelines
+// run on the cluster.
+package ci
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"knative.dev/func/pkg/docker"
+	fn "knative.dev/func/pkg/functions"
+)
+
+const (
+	GitHub = "github"
+	GitLab = "gitlab"
+)
+
+// Providers are the supported CI services.
+var Providers = []string{GitHub, GitLab}
+
+// FuncDownloadURL is where workflows download the func CLI from.
+var FuncDownloadURL = "https://github.com/knative/func/releases/latest/download/func_linux_amd64"
+
+// ErrWorkflowExists indicates that the workflow file was not written because
+// one exists already.
+var ErrWorkflowExists = errors.New("workflow file exists already")
+
+// ErrUnknownProvider indicates that workflows can not be generated for the
+// requested CI service.
+type ErrUnknownProvider struct {
+	Provider string
+}
+
+func (e ErrUnknownProvider) Error() string {
+	return fmt.Sprintf("unknown CI provider %q, supported are: %s", e.Provider, strings.Join(Providers, ", "))
+}
+
+// FileName returns the name of the workflow file for the function, as
+// expected by the given CI service within the directory it reads it from.
+func FileName(f fn.Function, provider string) (string, error) {
+	switch provider {
+	case GitHub:
+		return "func-" + f.Name + ".yaml", nil
+	case GitLab:
+		return ".gitlab-ci.yml", nil
+	}
+	return "", ErrUnknownProvider{provider}
+}
+
+// Dir returns the directory the given CI service reads the workflow of the
+// function from, which is relative to the root of the Git repository
+// containing the function.
+func Dir(f fn.Function, provider string) (string, error) {
+	root, _ := repositoryRoot(f)
+	switch provider {
+	case GitHub:
+		return filepath.Join(root, ".github", "workflows"), nil
+	case GitLab:
+		return root, nil
+	}
+	return "", ErrUnknownProvider{provider}
+}
+
+// repositoryRoot returns the root of the Git repository containing the
+// function and the function's path within it.  A function not within a Git
+// repository yet is presumed to be at the path of its Git context directory
+// within the repository, with the repository root being unknown.
+func repositoryRoot(f fn.Function) (root, path string) {
+	for dir := f.Root; ; {
+		// .git is a file rather than a directory in worktrees and submodules
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			if path, err = filepath.Rel(dir, f.Root); err == nil {
+				return dir, filepath.ToSlash(path)
+			}
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return f.Root, filepath.ToSlash(filepath.Clean(f.Build.Git.ContextDir))
+}
+
+type workflowData struct {
+	Name      string
+	Path      string
+	Branch    string
+	Builder   string
+	Registry  string
+	Namespace string
+	FuncURL   string
+
+	// RegistryHost is the registry to log in to, empty for Docker Hub.
+	RegistryHost string
+	// RegistryAuthKey is the key of the registry in a docker config.json.
+	RegistryAuthKey string
+}
+
+// Write the workflow for the given CI service which, on each push to the
+// function's Git revision (default "main"), builds the function and deploys
+// it with the func CLI from the function's path within its Git repository.
+// The workflow is written into dir, see Dir, returning the path of the
+// written file.  An existing workflow is only overwritten when force is set.
+func Write(f fn.Function, provider, dir string, force bool) (string, error) {
+	var tmpl string
+	switch provider {
+	case GitHub:
+		tmpl = gitHubWorkflow
+	case GitLab:
+		tmpl = gitLabWorkflow
+	default:
+		return "", ErrUnknownProvider{provider}
+	}
+
+	// The image, if set, takes precedence over the registry
+	image := f.Image
+	if image == "" {
+		var err error
+		if image, err = f.ImageName(); err != nil {
+			return "", err
+		}
+	}
+	registry, err := docker.GetRegistry(image)
+	if err != nil {
+		return "", fmt.Errorf("problem in resolving image registry name: %v", err)
+	}
+
+	_, path := repositoryRoot(f)
+	data := workflowData{
+		Name:            f.Name,
+		Path:            path,
+		Branch:          f.Build.Git.Revision,
+		Builder:         f.Build.Builder,
+		Registry:        f.Registry,
+		Namespace:       f.Namespace,
+		FuncURL:         FuncDownloadURL,
+		RegistryHost:    registry,
+		RegistryAuthKey: registry,
+	}
+	if data.Branch == "" {
+		data.Branch = "main"
+	}
+	if data.Namespace == "" {
+		data.Namespace = f.Deploy.Namespace
+	}
+	if registry == name.DefaultRegistry {
+		data.RegistryHost = ""
+		data.RegistryAuthKey = authn.DefaultAuthKey
+	}
+
+	t, err := template.New(provider).Delims("[[", "]]").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("error parsing workflow template: %v", err)
+	}
+
+	fileName, _ := FileName(f, provider)
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("error creating workflow path: %v", err)
+	}
+	p := filepath.Join(dir, fileName)
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+	file, err := os.OpenFile(p, flags, 0644)
+	if errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("%w: %v", ErrWorkflowExists, p)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error creating workflow: %v", err)
+	}
+	defer file.Close()
+
+	if err = t.Execute(file, data); err != nil {
+		return "", fmt.Errorf("error executing workflow template: %v", err)
+	}
+	return p, nil
+}
+
+const gitHubWorkflow = `# Builds and deploys the function "[[.Name]]" on each push to [[.Branch]].
+#
+# Requires the repository secrets REGISTRY_USERNAME and REGISTRY_PASSWORD with
+# push access to the registry, and KUBECONFIG with the contents of a
+# kubeconfig file granting access to the cluster.
+name: [[.Name]]
+on:
+  push:
+    branches:
+      - "[[.Branch]]"
+[[- if ne .Path "."]]
+    paths:
+      - "[[.Path]]/**"
+[[- end]]
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    defaults:
+      run:
+        working-directory: "[[.Path]]"
+    steps:
+      - uses: actions/checkout@v4
+      - name: Install func
+        run: |
+          mkdir -p "$HOME/.local/bin"
+          curl -sSLf -o "$HOME/.local/bin/func" [[.FuncURL]]
+          chmod +x "$HOME/.local/bin/func"
+          echo "$HOME/.local/bin" >> "$GITHUB_PATH"
+      - name: Log in to the registry
+        uses: docker/login-action@v3
+        with:
+[[- if .RegistryHost]]
+          registry: [[.RegistryHost]]
+[[- end]]
+          username: ${{ secrets.REGISTRY_USERNAME }}
+          password: ${{ secrets.REGISTRY_PASSWORD }}
+      - name: Configure cluster access
+        run: |
+          mkdir -p "$HOME/.kube"
+          echo "$KUBECONFIG_CONTENT" > "$HOME/.kube/config"
+        env:
+          KUBECONFIG_CONTENT: ${{ secrets.KUBECONFIG }}
+      - name: Build
+        run: func build[[if .Builder]] --builder [[.Builder]][[end]][[if .Registry]] --registry [[.Registry]][[end]]
+      - name: Deploy
+        run: func deploy --build=false[[if .Namespace]] --namespace [[.Namespace]][[end]]
+`
+
+const gitLabWorkflow = `# Builds and deploys the function "[[.Name]]" on each push to [[.Branch]].
+#
+# Requires the CI/CD variables REGISTRY_USERNAME and REGISTRY_PASSWORD with
+# push access to the registry, and a variable KUBECONFIG of type "File" with
+# a kubeconfig granting access to the cluster.
+[[.Name]]-deploy:
+  image: debian:bookworm-slim
+  services:
+    - docker:27-dind
+  variables:
+    DOCKER_HOST: tcp://docker:2375
+    DOCKER_TLS_CERTDIR: ""
+  rules:
+    - if: $CI_COMMIT_BRANCH == "[[.Branch]]"
+[[- if ne .Path "."]]
+      changes:
+        - "[[.Path]]/**/*"
+[[- end]]
+  before_script:
+    - apt-get update && apt-get install -y --no-install-recommends ca-certificates curl
+    - curl -sSLf -o /usr/local/bin/func [[.FuncURL]] && chmod +x /usr/local/bin/func
+    - |
+      mkdir -p "$HOME/.docker"
+      AUTH=$(printf '%s:%s' "$REGISTRY_USERNAME" "$REGISTRY_PASSWORD" | base64 -w0)
+      echo "{\"auths\":{\"[[.RegistryAuthKey]]\":{\"auth\":\"$AUTH\"}}}" > "$HOME/.docker/config.json"
+  script:
+    - cd "$CI_PROJECT_DIR/[[.Path]]"
+    - func build[[if .Builder]] --builder [[.Builder]][[end]][[if .Registry]] --registry [[.Registry]][[end]]
+    - func deploy --build=false[[if .Namespace]] --namespace [[.Namespace]][[end]]
+`
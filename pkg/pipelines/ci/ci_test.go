@@ -0,0 +1,124 @@
+package ci_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	fn "knative.dev/func/pkg/functions"
+	"knative.dev/func/pkg/pipelines/ci"
+)
+
+// TestWrite ensures that the workflow of each provider is valid yaml which
+// builds and deploys the function from its path within the Git repository.
+func TestWrite(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	f := fn.Function{
+		Name:      "myfunc",
+		Root:      filepath.Join(repo, "functions", "myfunc"),
+		Registry:  "ghcr.io/alice",
+		Namespace: "prod",
+		Build: fn.BuildSpec{
+			Builder: "pack",
+			Git:     fn.Git{Revision: "release"},
+		},
+	}
+
+	for _, provider := range ci.Providers {
+		t.Run(provider, func(t *testing.T) {
+			dir, err := ci.Dir(f, provider)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(dir, repo) || strings.HasPrefix(dir, f.Root) {
+				t.Fatalf("expected the workflow in the repository root, got %v", dir)
+			}
+			p, err := ci.Write(f, provider, dir, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bb, err := os.ReadFile(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var workflow map[string]any
+			if err = yaml.Unmarshal(bb, &workflow); err != nil {
+				t.Fatalf("workflow is not valid yaml: %v\n%s", err, bb)
+			}
+			for _, s := range []string{
+				`functions/myfunc"`,
+				`func build --builder pack --registry ghcr.io/alice`,
+				`func deploy --build=false --namespace prod`,
+				`"release"`,
+				"ghcr.io",
+			} {
+				if !strings.Contains(string(bb), s) {
+					t.Errorf("expected %v to contain %q, got:\n%s", filepath.Base(p), s, bb)
+				}
+			}
+		})
+	}
+}
+
+// TestWrite_Image ensures that the workflow of a function with an image but
+// no registry logs in to the registry of the image, and builds without a
+// registry.
+func TestWrite_Image(t *testing.T) {
+	f := fn.Function{Name: "myfunc", Root: t.TempDir(), Image: "quay.io/alice/myfunc:latest"}
+	for _, provider := range ci.Providers {
+		t.Run(provider, func(t *testing.T) {
+			p, err := ci.Write(f, provider, t.TempDir(), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bb, err := os.ReadFile(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(bb), "quay.io") {
+				t.Errorf("expected the workflow to log in to quay.io, got:\n%s", bb)
+			}
+			if strings.Contains(string(bb), "--registry") {
+				t.Errorf("expected the workflow to build without --registry, got:\n%s", bb)
+			}
+		})
+	}
+}
+
+// TestWrite_Exists ensures that an existing workflow is only overwritten when
+// forced.
+func TestWrite_Exists(t *testing.T) {
+	f := fn.Function{Name: "myfunc", Root: t.TempDir(), Registry: "ghcr.io/alice"}
+	dir := t.TempDir()
+	p := filepath.Join(dir, ".gitlab-ci.yml")
+	if err := os.WriteFile(p, []byte("stages: [test]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ci.Write(f, ci.GitLab, dir, false); !errors.Is(err, ci.ErrWorkflowExists) {
+		t.Fatalf("expected ErrWorkflowExists, got %v", err)
+	}
+	if bb, _ := os.ReadFile(p); string(bb) != "stages: [test]\n" {
+		t.Fatalf("expected the existing workflow to be retained, got:\n%s", bb)
+	}
+	if _, err := ci.Write(f, ci.GitLab, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	if bb, _ := os.ReadFile(p); !strings.Contains(string(bb), "myfunc-deploy") {
+		t.Fatalf("expected the workflow to be overwritten, got:\n%s", bb)
+	}
+}
+
+// TestWrite_UnknownProvider ensures that an unknown provider is an error.
+func TestWrite_UnknownProvider(t *testing.T) {
+	f := fn.Function{Name: "myfunc", Registry: "ghcr.io/alice"}
+	if _, err := ci.Write(f, "jenkins", t.TempDir(), false); err == nil {
+		t.Fatal("expected an error for an unknown provider")
+	}
+}
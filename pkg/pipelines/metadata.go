@@ -9,6 +9,8 @@ type PacMetadata struct {
 	RegistryServer   string
 
 	GitProvider string
+	// GitUser owns the PersonalAccessToken, required by Bitbucket Server
+	GitUser string
 
 	PersonalAccessToken string
 	WebhookSecret       string
@@ -0,0 +1,143 @@
+package tekton
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"gopkg.in/yaml.v3"
+
+	fn "knative.dev/func/pkg/functions"
+)
+
+// TaskBundle is the Tekton bundle (an OCI image) with the Tasks of this
+// release of func, pinned by digest.  It is set when building release
+// binaries.  When set, Pipelines reference the Tasks from the bundle instead
+// of embedding them, which allows mirroring the bundle into the registry of a
+// disconnected cluster.  It can be overridden per function with
+// build.taskBundle of func.yaml.
+var TaskBundle = ""
+
+// Tekton bundle layer annotations, see
+// https://tekton.dev/docs/pipelines/pipelines/#tekton-bundles
+const (
+	bundleAPIVersionAnnotation = "dev.tekton.image.apiVersion"
+	bundleKindAnnotation       = "dev.tekton.image.kind"
+	bundleNameAnnotation       = "dev.tekton.image.name"
+)
+
+// getTaskBundle returns the bundle the Tasks of the function's Pipeline are
+// referenced from, or an empty string if they are embedded.
+func getTaskBundle(f fn.Function) string {
+	if f.Build.TaskBundle != "" {
+		return f.Build.TaskBundle
+	}
+	return TaskBundle
+}
+
+// bundleTaskRef returns a reference to the named Task in the bundle,
+// indented for the Pipeline templates in place of an embedded task spec.
+func bundleTaskRef(bundle, name string) string {
+	return `taskRef:
+        resolver: bundles
+        params:
+          - name: bundle
+            value: ` + bundle + `
+          - name: name
+            value: ` + name + `
+          - name: kind
+            value: task`
+}
+
+// gitCloneBundleTaskRef returns the fetch-sources Task of the Pipeline
+// templates referencing git-clone from the bundle instead of the Tekton Hub.
+func gitCloneBundleTaskRef(bundle string) string {
+	return `- name: fetch-sources
+      params:
+        - name: url
+          value: $(params.gitRepository)
+        - name: revision
+          value: $(params.gitRevision)
+      ` + bundleTaskRef(bundle, "git-clone") + `
+      workspaces:
+        - name: output
+          workspace: source-workspace`
+}
+
+// PublishTaskBundle pushes the Tasks used by func, including git-clone, as a
+// Tekton bundle to the given image reference, returning the reference pinned
+// by the digest of the pushed bundle.
+func PublishTaskBundle(ctx context.Context, ref string, opts ...remote.Option) (string, error) {
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid task bundle reference %q: %w", ref, err)
+	}
+
+	img := empty.Image
+	for _, task := range []string{getBuildpackTask(), getS2ITask(), getHostTask(), getDeployTask(), getScaffoldTask(), getReportTask(), getGitCloneTask()} {
+		var res map[string]any
+		if err = yaml.Unmarshal([]byte(task), &res); err != nil {
+			return "", err
+		}
+		metadata, _ := res["metadata"].(map[string]any)
+		taskName, _ := metadata["name"].(string)
+		content, err := json.Marshal(res)
+		if err != nil {
+			return "", err
+		}
+
+		layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+			return bundleLayerContent(taskName, content)
+		})
+		if err != nil {
+			return "", err
+		}
+		img, err = mutate.Append(img, mutate.Addendum{
+			Layer: layer,
+			Annotations: map[string]string{
+				bundleAPIVersionAnnotation: "v1",
+				bundleKindAnnotation:       "task",
+				bundleNameAnnotation:       taskName,
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	opts = append([]remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(authn.DefaultKeychain)}, opts...)
+	if err = remote.Write(r, img, opts...); err != nil {
+		return "", fmt.Errorf("cannot push task bundle: %w", err)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+	return r.Context().Digest(digest.String()).String(), nil
+}
+
+// bundleLayerContent returns a tar stream with the single resource of a
+// bundle layer.
+func bundleLayerContent(name string, content []byte) (io.ReadCloser, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(content); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(&buf), nil
+}
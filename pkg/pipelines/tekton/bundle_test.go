@@ -0,0 +1,82 @@
+package tekton
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// TestPublishTaskBundle ensures that the tasks are pushed as a Tekton bundle
+// with a layer per task, and that the returned reference is pinned by digest.
+func TestPublishTaskBundle(t *testing.T) {
+	srv := httptest.NewServer(registry.New())
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	ref, err := PublishTaskBundle(context.Background(), host+"/knative/func-tasks:test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := name.NewDigest(ref)
+	if err != nil {
+		t.Fatalf("expected a reference pinned by digest, got %q: %v", ref, err)
+	}
+
+	img, err := remote.Image(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := map[string]bool{}
+	for i, desc := range manifest.Layers {
+		if desc.Annotations[bundleKindAnnotation] != "task" || desc.Annotations[bundleAPIVersionAnnotation] != "v1" {
+			t.Fatalf("unexpected layer annotations %v", desc.Annotations)
+		}
+		taskName := desc.Annotations[bundleNameAnnotation]
+		tasks[taskName] = true
+
+		rc, err := layers[i].Uncompressed()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr := tar.NewReader(rc)
+		if _, err = tr.Next(); err != nil {
+			t.Fatal(err)
+		}
+		var task struct {
+			Kind     string
+			Metadata struct{ Name string }
+		}
+		if err = json.NewDecoder(tr).Decode(&task); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = tr.Next(); err != io.EOF {
+			t.Fatalf("expected a single resource in layer of %q", taskName)
+		}
+		rc.Close()
+		if task.Kind != "Task" || task.Metadata.Name != taskName {
+			t.Fatalf("layer of %q contains %v %q", taskName, task.Kind, task.Metadata.Name)
+		}
+	}
+	for _, taskName := range []string{"func-buildpacks", "func-s2i", "func-host", "func-deploy", "func-scaffold", "func-report", "git-clone"} {
+		if !tasks[taskName] {
+			t.Errorf("bundle is missing task %q", taskName)
+		}
+	}
+}
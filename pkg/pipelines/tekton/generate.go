@@ -0,0 +1,185 @@
+package tekton
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"knative.dev/func/pkg/builders"
+	fn "knative.dev/func/pkg/functions"
+)
+
+// ErrGitRequired indicates that the function has no Git repository configured
+// from which generated pipelines could fetch its sources.
+var ErrGitRequired = errors.New("a Git repository is required for generated pipelines, set it with 'func config git set' or in build.git.url of func.yaml")
+
+const (
+	pvcFileName = "pvc.yaml"
+
+	pvcTemplate = `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  labels:
+    {{range $key, $value := .Labels -}}
+     "{{$key}}": "{{$value}}"
+    {{end}}
+  name: {{.Name}}
+spec:
+  accessModes:
+    - ReadWriteOnce
+  resources:
+    requests:
+      storage: {{.Size}}
+  {{- if .StorageClass}}
+  storageClassName: {{.StorageClass}}
+  {{- end}}
+`
+)
+
+// Generate writes the Tasks, Pipeline, PipelineRun and PersistentVolumeClaim
+// of the function's on-cluster build as files into dir, such that they can be
+// applied by GitOps tooling rather than by func itself.  The Pipeline always
+// fetches the sources from the function's Git repository and references the
+// Tasks by name, or from the task bundle if any, instead of embedding them.
+// The PipelineRun has a fixed name, as GitOps tooling can not apply objects
+// with a generated one.  The registry credentials are expected in the Secret
+// named in the PipelineRun, which is not generated.  Returned are the paths of
+// the written files.
+func (pp *PipelinesProvider) Generate(ctx context.Context, f fn.Function, dir string) ([]string, error) {
+	var err error
+
+	if err = validatePipeline(f); err != nil {
+		return nil, err
+	}
+	if f.Build.Git.URL == "" {
+		return nil, ErrGitRequired
+	}
+
+	if f.Deploy.Image == "" {
+		if f.Deploy.Image = f.Image; f.Deploy.Image == "" {
+			if f.Deploy.Image, err = f.ImageName(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	labels, err := f.LabelsMap()
+	if err != nil {
+		return nil, err
+	}
+	if pp.decorator != nil {
+		labels = pp.decorator.UpdateLabels(f, labels)
+	}
+
+	var tasks []string
+	var pipelineTemplate, runTemplate string
+	switch f.Build.Builder {
+	case builders.Pack:
+		tasks = []string{getBuildpackTask(), getScaffoldTask(), getDeployTask(), getReportTask()}
+		pipelineTemplate, runTemplate = packPipelineTemplate, packRunTemplate
+	case builders.S2I:
+		tasks = []string{getS2ITask(), getScaffoldTask(), getDeployTask(), getReportTask()}
+		pipelineTemplate, runTemplate = s2iPipelineTemplate, s2iRunTemplate
+	case builders.Host:
+		tasks = []string{getHostTask(), getDeployTask(), getReportTask()}
+		pipelineTemplate, runTemplate = hostPipelineTemplate, hostRunTemplate
+	default:
+		return nil, builders.ErrBuilderNotSupported{Builder: f.Build.Builder}
+	}
+
+	// Tasks of a task bundle are referenced from it rather than written
+	ref, gitCloneTaskRef := taskRef, taskGitCloneTaskRef
+	if bundle := getTaskBundle(f); bundle != "" {
+		ref = func(name string) string { return bundleTaskRef(bundle, name) }
+		gitCloneTaskRef = gitCloneBundleTaskRef(bundle)
+		tasks = nil
+	}
+
+	pipelineData := templateData{
+		FunctionName:          f.Name,
+		Annotations:           f.Deploy.Annotations,
+		Labels:                labels,
+		PipelineName:          getPipelineName(f),
+		RunAfterFetchSources:  runAfterFetchSourcesRef,
+		FetchedGitRevision:    fetchedGitRevisionRef,
+		GitCloneTaskRef:       gitCloneTaskRef,
+		FuncBuildpacksTaskRef: ref("func-buildpacks"),
+		FuncS2iTaskRef:        ref("func-s2i"),
+		FuncHostTaskRef:       ref("func-host"),
+		FuncDeployTaskRef:     ref("func-deploy"),
+		FuncScaffoldTaskRef:   ref("func-scaffold"),
+		FuncReportTaskRef:     ref("func-report"),
+	}
+
+	pvcSize := DefaultPersistentVolumeClaimSize
+	if f.Build.PVCSize != "" {
+		if pvcSize, err = resource.ParseQuantity(f.Build.PVCSize); err != nil {
+			return nil, fmt.Errorf("PVC size value could not be parsed. %w", err)
+		}
+	}
+	pvcData := struct {
+		Name         string
+		Labels       map[string]string
+		Size         string
+		StorageClass string
+	}{getPipelinePvcName(f), labels, pvcSize.String(), f.Build.RemoteStorageClass}
+
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("error creating pipeline resources path: %v", err)
+	}
+
+	var files []string
+	write := func(fileName, fileTemplate string, data any) error {
+		tmpl, err := template.New(fileName).Parse(fileTemplate)
+		if err != nil {
+			return fmt.Errorf("error parsing pipeline template: %v", err)
+		}
+		p := filepath.Join(dir, fileName)
+		file, err := os.Create(p)
+		if err != nil {
+			return fmt.Errorf("error creating pipeline resources: %v", err)
+		}
+		defer file.Close()
+		if err = tmpl.Execute(file, data); err != nil {
+			return fmt.Errorf("error executing template: %v", err)
+		}
+		files = append(files, p)
+		return nil
+	}
+
+	for _, task := range tasks {
+		name, err := getTaskName(task)
+		if err != nil {
+			return nil, err
+		}
+		// Tasks are written as they are, not as templates
+		if err = write("task-"+name+".yaml", "{{.}}", task); err != nil {
+			return nil, err
+		}
+	}
+	if err = write(pipelineFileName, pipelineTemplate, pipelineData); err != nil {
+		return nil, err
+	}
+	// GitOps tooling applies objects by name, which is thus fixed
+	runData := newRunTemplateData(f, labels)
+	runData.PipelineRunName = getPipelineRunName(f)
+	runData.FixedPipelineRunName = true
+	if err = write(pipelineRunFilenane, runTemplate, runData); err != nil {
+		return nil, err
+	}
+	if err = write(pvcFileName, pvcTemplate, pvcData); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// taskRef returns a reference to the named Task, indented for the Pipeline
+// templates in place of an embedded task spec.
+func taskRef(name string) string {
+	return "taskRef:\n        kind: Task\n        name: " + name
+}
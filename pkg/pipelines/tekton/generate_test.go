@@ -0,0 +1,175 @@
+package tekton
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"knative.dev/func/pkg/builders"
+	fn "knative.dev/func/pkg/functions"
+)
+
+// TestGenerate ensures that the pipeline resources of each builder are
+// written as valid manifests, and that the Pipeline only references Tasks
+// which are written alongside it.
+func TestGenerate(t *testing.T) {
+	for _, builder := range []string{builders.Pack, builders.S2I, builders.Host} {
+		t.Run(builder, func(t *testing.T) {
+			dir := t.TempDir()
+			f := fn.Function{
+				Name:     "testfunc",
+				Runtime:  "go",
+				Registry: TestRegistry,
+				Build: fn.BuildSpec{
+					Builder: builder,
+					Git:     fn.Git{URL: "https://example.com/alice/testfunc.git"},
+				},
+			}
+
+			files, err := NewPipelinesProvider().Generate(context.Background(), f, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			kinds := map[string]int{}
+			tasks := map[string]bool{}
+			claims := map[string]bool{}
+			var refs []string
+			for _, file := range files {
+				bb, err := os.ReadFile(file)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var res struct {
+					Kind     string
+					Metadata struct {
+						Name         string
+						GenerateName string `yaml:"generateName"`
+					}
+					Spec struct {
+						Tasks []struct {
+							TaskRef struct{ Name string } `yaml:"taskRef"`
+						}
+						Finally []struct {
+							Name    string
+							TaskRef struct{ Name string } `yaml:"taskRef"`
+							Params  []struct{ Name, Value string }
+						}
+						Workspaces []struct {
+							PersistentVolumeClaim struct {
+								ClaimName string `yaml:"claimName"`
+							} `yaml:"persistentVolumeClaim"`
+						}
+					}
+				}
+				if err = yaml.Unmarshal(bb, &res); err != nil {
+					t.Fatalf("%v is not valid yaml: %v", filepath.Base(file), err)
+				}
+				kinds[res.Kind]++
+				switch res.Kind {
+				case "Task":
+					tasks[res.Metadata.Name] = true
+				case "Pipeline":
+					for _, task := range res.Spec.Tasks {
+						refs = append(refs, task.TaskRef.Name)
+					}
+					for _, task := range res.Spec.Finally {
+						refs = append(refs, task.TaskRef.Name)
+						// Failures are reported with the commit fetched
+						for _, p := range task.Params {
+							if task.Name == "report" && p.Name == "gitRevision" && p.Value != fetchedGitRevisionRef {
+								t.Errorf("expected the report of %v, got %q", fetchedGitRevisionRef, p.Value)
+							}
+						}
+					}
+				case "PipelineRun":
+					// GitOps tooling can not apply objects without a name
+					if res.Metadata.Name == "" || res.Metadata.GenerateName != "" {
+						t.Errorf("expected the PipelineRun to be named, got name %q, generateName %q", res.Metadata.Name, res.Metadata.GenerateName)
+					}
+					// Tekton's affinity assistant permits a single claim
+					for _, ws := range res.Spec.Workspaces {
+						if claim := ws.PersistentVolumeClaim.ClaimName; claim != "" {
+							claims[claim] = true
+						}
+					}
+				}
+			}
+
+			for _, kind := range []string{"Pipeline", "PipelineRun", "PersistentVolumeClaim"} {
+				if kinds[kind] != 1 {
+					t.Errorf("expected one %v, got %v", kind, kinds[kind])
+				}
+			}
+			if len(claims) != 1 {
+				t.Errorf("expected the PipelineRun to bind a single claim, got %v", claims)
+			}
+			for _, ref := range refs {
+				// git-clone is resolved from the Tekton hub
+				if ref != "" && !tasks[ref] {
+					t.Errorf("pipeline references task %q which was not generated", ref)
+				}
+			}
+		})
+	}
+}
+
+// TestGenerate_TaskBundle ensures that Tasks of a task bundle are referenced
+// from it rather than being written.
+func TestGenerate_TaskBundle(t *testing.T) {
+	dir := t.TempDir()
+	bundle := "registry.example.com/knative/func-tasks@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	f := fn.Function{
+		Name:     "testfunc",
+		Runtime:  "go",
+		Registry: TestRegistry,
+		Build: fn.BuildSpec{
+			Builder:    builders.Pack,
+			Git:        fn.Git{URL: "https://example.com/alice/testfunc.git"},
+			TaskBundle: bundle,
+		},
+	}
+
+	files, err := NewPipelinesProvider().Generate(context.Background(), f, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if strings.HasPrefix(filepath.Base(file), "task-") {
+			t.Fatalf("unexpected task file %v", file)
+		}
+	}
+	bb, err := os.ReadFile(filepath.Join(dir, pipelineFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"resolver: bundles", "value: " + bundle, "value: func-buildpacks", "value: git-clone"} {
+		if !strings.Contains(string(bb), s) {
+			t.Fatalf("expected pipeline to contain %q, got:\n%s", s, bb)
+		}
+	}
+	// git-clone is referenced from the bundle as well
+	if strings.Contains(string(bb), "resolver: hub") {
+		t.Fatalf("expected no task to be resolved from the hub, got:\n%s", bb)
+	}
+}
+
+// TestGenerate_GitRequired ensures that generating requires a Git repository
+// the pipeline can fetch the sources from.
+func TestGenerate_GitRequired(t *testing.T) {
+	f := fn.Function{
+		Name:     "testfunc",
+		Runtime:  "go",
+		Registry: TestRegistry,
+		Build:    fn.BuildSpec{Builder: builders.Pack},
+	}
+	_, err := NewPipelinesProvider().Generate(context.Background(), f, t.TempDir())
+	if !errors.Is(err, ErrGitRequired) {
+		t.Fatalf("expected ErrGitRequired, got %v", err)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	gitignore "github.com/sabhiram/go-gitignore"
@@ -24,12 +25,15 @@ import (
 	"github.com/tektoncd/cli/pkg/taskrun"
 	v1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	pipelineClient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/typed/pipeline/v1"
+	"golang.org/x/term"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 
 	"knative.dev/func/pkg/docker"
+	"knative.dev/func/pkg/filesync"
 	fn "knative.dev/func/pkg/functions"
 	"knative.dev/func/pkg/k8s"
 	fnlabels "knative.dev/func/pkg/k8s/labels"
@@ -51,9 +55,12 @@ type Opt func(*PipelinesProvider)
 
 type pacURLCallback = func() (string, error)
 
+type cancelConfirmCallback = func(pipelineRun string) (bool, error)
+
 type PipelinesProvider struct {
 	verbose             bool
 	getPacURL           pacURLCallback
+	confirmCancel       cancelConfirmCallback
 	credentialsProvider docker.CredentialsProvider
 	decorator           PipelineDecorator
 }
@@ -82,6 +89,14 @@ func WithPacURLCallback(getPacURL pacURLCallback) Opt {
 	}
 }
 
+// WithCancelConfirmation sets the callback asked whether a PipelineRun should
+// be cancelled on the cluster when the context of Run is cancelled.
+func WithCancelConfirmation(confirmCancel cancelConfirmCallback) Opt {
+	return func(pp *PipelinesProvider) {
+		pp.confirmCancel = confirmCancel
+	}
+}
+
 func NewPipelinesProvider(opts ...Opt) *PipelinesProvider {
 	pp := &PipelinesProvider{
 		getPacURL: func() (string, error) {
@@ -91,6 +106,19 @@ func NewPipelinesProvider(opts ...Opt) *PipelinesProvider {
 			}, &url, survey.WithValidator(survey.Required))
 			return url, e
 		},
+		confirmCancel: func(pipelineRun string) (bool, error) {
+			// Without a terminal to ask, cancel such that an abandoned run does
+			// not race the next deployment.
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				return true, nil
+			}
+			cancel := true
+			e := survey.AskOne(&survey.Confirm{
+				Message: fmt.Sprintf("Cancel pipeline run %q on the cluster?", pipelineRun),
+				Default: true,
+			}, &cancel)
+			return cancel, e
+		},
 	}
 
 	for _, opt := range opts {
@@ -159,11 +187,8 @@ func (pp *PipelinesProvider) Run(ctx context.Context, f fn.Function) (string, fn
 
 	if f.Build.Git.URL == "" {
 		// Use direct upload to PVC if Git is not set up.
-		content := sourcesAsTarStream(f)
-		defer content.Close()
-		err = k8s.UploadToVolume(ctx, content, getPipelinePvcName(f), namespace)
-		if err != nil {
-			return "", f, fmt.Errorf("cannot upload sources to the PVC: %w", err)
+		if err = pp.uploadSources(ctx, f, namespace); err != nil {
+			return "", f, err
 		}
 	}
 
@@ -217,23 +242,108 @@ func (pp *PipelinesProvider) Run(ctx context.Context, f fn.Function) (string, fn
 		return "", f, fmt.Errorf("problem in listing pipeline runs: %v", err)
 	}
 
-	err = pp.watchPipelineRunProgress(ctx, newestPipelineRun, namespace)
+	return pp.awaitPipelineRun(ctx, client, f, namespace, newestPipelineRun)
+}
+
+// Rerun the newest PipelineRun of the function with the same parameters and
+// workspaces, such that the sources previously uploaded to the PVC (or the same
+// Git revision) are built and deployed again without uploading them.
+// Returned is the final url and the function as with Run.
+func (pp *PipelinesProvider) Rerun(ctx context.Context, f fn.Function) (string, fn.Function, error) {
+	namespace := f.Namespace
+	if namespace == "" {
+		namespace = f.Deploy.Namespace
+	}
+	if namespace == "" {
+		return "", f, fn.ErrNamespaceRequired
+	}
+	f.Deploy.Namespace = namespace
+
+	client, err := NewTektonClient(namespace)
+	if err != nil {
+		return "", f, err
+	}
+
+	last, err := findNewestPipelineRunWithRetry(ctx, f, namespace, client)
+	if err != nil {
+		return "", f, fmt.Errorf("no previous pipeline run to retry: %w", err)
+	}
+	if !last.IsDone() {
+		return "", f, fmt.Errorf("previous pipeline run %q has not finished yet", last.Name)
+	}
+	for _, ws := range last.Spec.Workspaces {
+		if ws.PersistentVolumeClaim == nil {
+			continue
+		}
+		if _, err = k8s.GetPersistentVolumeClaim(ctx, ws.PersistentVolumeClaim.ClaimName, namespace); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return "", f, fmt.Errorf("the volume %q of pipeline run %q no longer exists, deploy without retrying", ws.PersistentVolumeClaim.ClaimName, last.Name)
+			}
+			return "", f, fmt.Errorf("problem in retrieving volume of pipeline run: %v", err)
+		}
+	}
+
+	for _, p := range last.Spec.Params {
+		if p.Name == "imageName" {
+			f.Deploy.Image = p.Value.StringVal
+		}
+	}
+	if f.Deploy.Image == "" {
+		if f.Deploy.Image, err = f.ImageName(); err != nil {
+			return "", f, err
+		}
+	}
+
+	pr, err := client.PipelineRuns(namespace).Create(ctx, rerunOf(last), metav1.CreateOptions{})
+	if err != nil {
+		return "", f, fmt.Errorf("problem in creating pipeline run: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Retrying pipeline run %q as %q\n", last.Name, pr.Name)
+
+	return pp.awaitPipelineRun(ctx, client, f, namespace, pr)
+}
+
+// rerunOf returns a new PipelineRun with the labels, annotations and spec of
+// the given one.
+func rerunOf(pr *v1.PipelineRun) *v1.PipelineRun {
+	generateName := pr.GenerateName
+	if generateName == "" {
+		generateName = pr.Name + "-"
+	}
+	spec := pr.Spec.DeepCopy()
+	spec.Status = ""
+	return &v1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: generateName,
+			Labels:       pr.Labels,
+			Annotations:  pr.Annotations,
+		},
+		Spec: *spec,
+	}
+}
+
+// awaitPipelineRun watches the started PipelineRun until it is done, returning
+// the url of the deployed function.  Should the context be cancelled, the
+// PipelineRun is cancelled on the cluster as well, once confirmed.
+func (pp *PipelinesProvider) awaitPipelineRun(ctx context.Context, client *pipelineClient.TektonV1Client, f fn.Function, namespace string, pr *v1.PipelineRun) (string, fn.Function, error) {
+	err := pp.watchPipelineRunProgress(ctx, pr, namespace)
 	if err != nil {
 		if !errors.Is(err, context.Canceled) {
 			return "", f, fmt.Errorf("problem in watching started pipeline run: %v", err)
 		}
-		// TODO replace deletion with pipeline-run cancellation
-		_ = client.PipelineRuns(namespace).Delete(context.TODO(), newestPipelineRun.Name, metav1.DeleteOptions{})
+		if err = pp.cancelPipelineRun(client, namespace, pr.Name); err != nil {
+			return "", f, err
+		}
 		return "", f, fmt.Errorf("pipeline run cancelled: %w", context.Canceled)
 	}
 
-	newestPipelineRun, err = client.PipelineRuns(namespace).Get(ctx, newestPipelineRun.Name, metav1.GetOptions{})
+	pr, err = client.PipelineRuns(namespace).Get(ctx, pr.Name, metav1.GetOptions{})
 	if err != nil {
 		return "", f, fmt.Errorf("problem in retriving pipeline run status: %v", err)
 	}
 
-	if newestPipelineRun.Status.GetCondition(apis.ConditionSucceeded).Status == corev1.ConditionFalse {
-		message := getFailedPipelineRunLog(ctx, client, newestPipelineRun, namespace)
+	if pr.Status.GetCondition(apis.ConditionSucceeded).Status == corev1.ConditionFalse {
+		message := getFailedPipelineRunLog(ctx, client, pr, namespace)
 		return "", f, fmt.Errorf("function pipeline run has failed with message: \n\n%s", message)
 	}
 
@@ -260,17 +370,93 @@ func (pp *PipelinesProvider) Run(ctx context.Context, f fn.Function) (string, fn
 	return ksvc.Status.URL.String(), f, nil
 }
 
-// Creates tar stream with the function sources as they were in "./source" directory.
-func sourcesAsTarStream(f fn.Function) *io.PipeReader {
-	ignored := func(p string) bool { return strings.HasPrefix(p, ".git") }
-	if gi, err := gitignore.CompileIgnoreFile(filepath.Join(f.Root, ".gitignore")); err == nil {
-		ignored = func(p string) bool {
-			if strings.HasPrefix(p, ".git") {
+// cancelPipelineRun asks for confirmation and then gracefully cancels the
+// PipelineRun, such that it does not race a subsequent deployment.  Declining
+// leaves the run to complete on the cluster.
+func (pp *PipelinesProvider) cancelPipelineRun(client *pipelineClient.TektonV1Client, namespace, name string) error {
+	confirmed, err := pp.confirmCancel(name)
+	if err != nil && !errors.Is(err, terminal.InterruptErr) {
+		return fmt.Errorf("pipeline run cancelled locally: %w", err)
+	}
+	// An interrupted prompt is taken as a repeated request to cancel
+	if !confirmed && err == nil {
+		fmt.Fprintf(os.Stderr, "Pipeline run %q continues on the cluster\n", name)
+		return nil
+	}
+
+	// The original context is done, allow some time to reach the cluster.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	patch := fmt.Sprintf(`{"spec":{"status":%q}}`, v1.PipelineRunSpecStatusCancelled)
+	_, err = client.PipelineRuns(namespace).Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("problem in cancelling pipeline run %q: %v", name, err)
+	}
+	fmt.Fprintf(os.Stderr, "Pipeline run %q cancelled\n", name)
+	return nil
+}
+
+// uploadSources updates the function's sources in the "./source" directory of
+// its PVC, sending only the files which changed since the previous upload and
+// removing those which no longer exist.  Should the incremental upload not be
+// possible, for instance with an older utilities image, all sources are
+// uploaded instead.
+func (pp *PipelinesProvider) uploadSources(ctx context.Context, f fn.Function, namespace string) error {
+	local, err := filesync.Scan(f.Root, sourcesIgnored(f))
+	if err != nil {
+		return fmt.Errorf("cannot read function sources: %w", err)
+	}
+
+	var stats filesync.Stats
+	err = k8s.SyncToVolume(ctx, "source", getPipelinePvcName(f), namespace, func(podOutput io.Reader, podInput io.Writer) (err error) {
+		stats, err = filesync.Send(f.Root, local, podOutput, podInput)
+		return
+	})
+	if err == nil {
+		if pp.verbose {
+			fmt.Fprintf(os.Stderr, "Uploaded %d changed source entries (%d bytes), removed %d\n", stats.Changed, stats.Bytes, stats.Removed)
+		}
+		return nil
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("cannot upload sources to the PVC: %w", ctx.Err())
+	}
+	fmt.Fprintf(os.Stderr, "Warning: incremental upload of sources failed, uploading all sources: %v\n", err)
+
+	content := sourcesAsTarStream(f)
+	defer content.Close()
+	if err = k8s.UploadToVolume(ctx, content, getPipelinePvcName(f), namespace); err != nil {
+		return fmt.Errorf("cannot upload sources to the PVC: %w", err)
+	}
+	return nil
+}
+
+// sourcesIgnored returns whether a path of the function's sources, relative
+// to its root, is excluded from upload: the Git metadata and those paths
+// matched by either .gitignore or .funcignore.
+func sourcesIgnored(f fn.Function) func(string) bool {
+	var matchers []*gitignore.GitIgnore
+	for _, name := range []string{".gitignore", ".funcignore"} {
+		if gi, err := gitignore.CompileIgnoreFile(filepath.Join(f.Root, name)); err == nil {
+			matchers = append(matchers, gi)
+		}
+	}
+	return func(p string) bool {
+		if strings.HasPrefix(p, ".git") {
+			return true
+		}
+		for _, gi := range matchers {
+			if gi.MatchesPath(p) {
 				return true
 			}
-			return gi.MatchesPath(p)
 		}
+		return false
 	}
+}
+
+// Creates tar stream with the function sources as they were in "./source" directory.
+func sourcesAsTarStream(f fn.Function) *io.PipeReader {
+	ignored := sourcesIgnored(f)
 
 	pr, pw := io.Pipe()
 
@@ -524,10 +710,7 @@ func getFailedPipelineRunLog(ctx context.Context, client *pipelineClient.TektonV
 
 // findNewestPipelineRunWithRetry tries to find newest Pipeline Run for the input function
 func findNewestPipelineRunWithRetry(ctx context.Context, f fn.Function, namespace string, client *pipelineClient.TektonV1Client) (*v1.PipelineRun, error) {
-	l := k8slabels.SelectorFromSet(k8slabels.Set(map[string]string{fnlabels.FunctionNameKey: f.Name}))
-	listOptions := metav1.ListOptions{
-		LabelSelector: l.String(),
-	}
+	listOptions := pipelineRunsListOptions(f)
 
 	var newestPipelineRun *v1.PipelineRun
 	for attempt := 1; attempt <= 3; attempt++ {
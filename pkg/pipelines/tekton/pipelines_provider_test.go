@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	v1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -145,3 +146,58 @@ func Test_createPipelinePersistentVolumeClaim(t *testing.T) {
 		})
 	}
 }
+
+func Test_rerunOf(t *testing.T) {
+	last := &v1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:         "f-pack-upload-pipeline-run-abcde",
+			GenerateName: "f-pack-upload-pipeline-run-",
+			Labels:       map[string]string{"function.knative.dev/name": "f"},
+		},
+		Spec: v1.PipelineRunSpec{
+			PipelineRef: &v1.PipelineRef{Name: "f-pack-upload-pipeline"},
+			Status:      v1.PipelineRunSpecStatusCancelled,
+			Workspaces: []v1.WorkspaceBinding{{
+				Name:                  "source-workspace",
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "f-pvc"},
+			}},
+		},
+	}
+
+	pr := rerunOf(last)
+	if pr.Name != "" || pr.GenerateName != last.GenerateName {
+		t.Errorf("expected a generated name with prefix %q, got name %q prefix %q", last.GenerateName, pr.Name, pr.GenerateName)
+	}
+	if pr.Spec.Status != "" {
+		t.Errorf("expected the cancellation of the last run not to be carried over, got %q", pr.Spec.Status)
+	}
+	if pr.Spec.PipelineRef.Name != "f-pack-upload-pipeline" || pr.Spec.Workspaces[0].PersistentVolumeClaim.ClaimName != "f-pvc" {
+		t.Errorf("expected the pipeline and source volume of the last run, got %+v", pr.Spec)
+	}
+	if last.Spec.Status != v1.PipelineRunSpecStatusCancelled {
+		t.Error("the last run was modified")
+	}
+}
+
+func Test_sourcesIgnored(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("bin/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".funcignore"), []byte("assets/*.mp4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ignored := sourcesIgnored(fn.Function{Root: root})
+	for p, want := range map[string]bool{
+		".git/config":     true,
+		"bin/app":         true,
+		"assets/big.mp4":  true,
+		"assets/logo.png": false,
+		"handle.go":       false,
+	} {
+		if got := ignored(p); got != want {
+			t.Errorf("ignored(%q) = %v, want %v", p, got, want)
+		}
+	}
+}
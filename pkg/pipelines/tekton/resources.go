@@ -11,6 +11,7 @@ import (
 	"knative.dev/func/pkg/builders/buildpacks"
 	"knative.dev/func/pkg/builders/s2i"
 	fn "knative.dev/func/pkg/functions"
+	"knative.dev/func/pkg/oci"
 )
 
 func deletePipelines(ctx context.Context, namespace string, listOptions metav1.ListOptions) (err error) {
@@ -44,6 +45,8 @@ func deletePipelineRuns(ctx context.Context, namespace string, listOptions metav
 func getBuilderImage(f fn.Function) (name string) {
 	if f.Build.Builder == builders.S2I {
 		name, _ = s2i.BuilderImage(f, builders.S2I)
+	} else if f.Build.Builder == builders.Host {
+		name, _ = oci.ToolchainImage(f)
 	} else {
 		name, _ = buildpacks.BuilderImage(f, builders.Pack)
 	}
@@ -64,6 +67,10 @@ func getPipelineRunGenerateName(f fn.Function) string {
 	return fmt.Sprintf("%s-run-", getPipelineName(f))
 }
 
+func getPipelineRunName(f fn.Function) string {
+	return fmt.Sprintf("%s-run", getPipelineName(f))
+}
+
 func getPipelineSecretName(f fn.Function) string {
 	return fmt.Sprintf("%s-secret", getPipelineName(f))
 }
@@ -11,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	fn "knative.dev/func/pkg/functions"
+	"knative.dev/func/pkg/git"
 	"knative.dev/func/pkg/k8s"
 	"knative.dev/func/pkg/pipelines"
 	"knative.dev/func/pkg/pipelines/tekton/pac"
@@ -47,6 +48,11 @@ func ensurePACRepositoryExists(ctx context.Context, f fn.Function, namespace str
 		return err
 	}
 
+	repoURL, providerURL, err := git.PACRepositoryURLs(metadata.GitProvider, f.Build.Git.URL)
+	if err != nil {
+		return err
+	}
+
 	repoName := getPipelineRepositoryName(f)
 	repo := v1alpha1.Repository{
 		ObjectMeta: metav1.ObjectMeta{
@@ -55,9 +61,11 @@ func ensurePACRepositoryExists(ctx context.Context, f fn.Function, namespace str
 			Annotations: f.Deploy.Annotations,
 		},
 		Spec: v1alpha1.RepositorySpec{
-			URL: f.Build.Git.URL,
+			URL: repoURL,
 			GitProvider: &v1alpha1.GitProvider{
 				Type: metadata.GitProvider,
+				URL:  providerURL,
+				User: metadata.GitUser,
 				Secret: &v1alpha1.Secret{
 					Name: getPipelineSecretName(f),
 				},
@@ -0,0 +1,250 @@
+package tekton
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	pipelineClient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/typed/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"knative.dev/pkg/apis"
+
+	fn "knative.dev/func/pkg/functions"
+	"knative.dev/func/pkg/k8s"
+	fnlabels "knative.dev/func/pkg/k8s/labels"
+)
+
+// ListRuns returns the PipelineRuns of the function, newest first.
+func (pp *PipelinesProvider) ListRuns(ctx context.Context, f fn.Function) ([]fn.PipelineRun, error) {
+	namespace, err := runsNamespace(f)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewTektonClient(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, err := client.PipelineRuns(namespace).List(ctx, pipelineRunsListOptions(f))
+	if err != nil {
+		return nil, fmt.Errorf("problem in listing pipeline runs: %v", err)
+	}
+
+	runs := make([]fn.PipelineRun, 0, len(prs.Items))
+	for i := range prs.Items {
+		runs = append(runs, toPipelineRun(&prs.Items[i]))
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		// Runs not yet started are the newest
+		if runs[i].Started.IsZero() != runs[j].Started.IsZero() {
+			return runs[i].Started.IsZero()
+		}
+		return runs[i].Started.After(runs[j].Started)
+	})
+	return runs, nil
+}
+
+// DescribeRun returns the named PipelineRun of the function including the
+// status of each of its tasks.  If name is empty, the newest run is described.
+func (pp *PipelinesProvider) DescribeRun(ctx context.Context, f fn.Function, name string) (fn.PipelineRun, error) {
+	namespace, err := runsNamespace(f)
+	if err != nil {
+		return fn.PipelineRun{}, err
+	}
+	client, err := NewTektonClient(namespace)
+	if err != nil {
+		return fn.PipelineRun{}, err
+	}
+	pr, err := getPipelineRun(ctx, client, f, namespace, name)
+	if err != nil {
+		return fn.PipelineRun{}, err
+	}
+
+	run := toPipelineRun(pr)
+	trs, err := getTaskRuns(ctx, client, pr, namespace)
+	if err != nil {
+		return run, err
+	}
+	for _, tr := range trs {
+		task := fn.PipelineTaskRun{
+			Name:   tr.Labels[pipelineTaskLabel],
+			Status: conditionStatus(tr.Status.GetCondition(apis.ConditionSucceeded)),
+		}
+		if tr.Status.StartTime != nil {
+			task.Started = tr.Status.StartTime.Time
+		}
+		if tr.Status.CompletionTime != nil {
+			task.Completed = tr.Status.CompletionTime.Time
+		}
+		run.Tasks = append(run.Tasks, task)
+	}
+	return run, nil
+}
+
+// RunLogs returns the logs of the given task of the named PipelineRun, all
+// of its tasks if task is empty.  If the run has failed and no task is given
+// only the log of the failure is returned.  If name is empty, the newest run
+// is used.
+func (pp *PipelinesProvider) RunLogs(ctx context.Context, f fn.Function, name, task string) (string, error) {
+	namespace, err := runsNamespace(f)
+	if err != nil {
+		return "", err
+	}
+	client, err := NewTektonClient(namespace)
+	if err != nil {
+		return "", err
+	}
+	pr, err := getPipelineRun(ctx, client, f, namespace, name)
+	if err != nil {
+		return "", err
+	}
+
+	if task == "" && pr.Status.GetCondition(apis.ConditionSucceeded).IsFalse() {
+		return getFailedPipelineRunLog(ctx, client, pr, namespace), nil
+	}
+
+	trs, err := getTaskRuns(ctx, client, pr, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	found := false
+	for _, tr := range trs {
+		taskName := tr.Labels[pipelineTaskLabel]
+		if task != "" && task != taskName {
+			continue
+		}
+		found = true
+		for _, s := range tr.Status.Steps {
+			prefix := fmt.Sprintf("[%s : %s] ", taskName, s.Name)
+			logs, err := k8s.GetPodLogs(ctx, namespace, tr.Status.PodName, s.Container)
+			if err != nil {
+				fmt.Fprintf(&b, "%slogs unavailable: %v\n", prefix, err)
+				continue
+			}
+			for _, line := range strings.SplitAfter(logs, "\n") {
+				if line == "" {
+					continue
+				}
+				b.WriteString(prefix + line)
+				if !strings.HasSuffix(line, "\n") {
+					b.WriteString("\n")
+				}
+			}
+		}
+	}
+	if task != "" && !found {
+		return "", fmt.Errorf("task %q not found in pipeline run %q", task, pr.Name)
+	}
+	return b.String(), nil
+}
+
+// pipelineTaskLabel is set by Tekton on each TaskRun to the name of the task
+// within its Pipeline.
+const pipelineTaskLabel = "tekton.dev/pipelineTask"
+
+// runsNamespace returns the namespace in which to look for PipelineRuns of
+// the function: either its requested or its currently deployed namespace.
+func runsNamespace(f fn.Function) (string, error) {
+	if f.Namespace != "" {
+		return f.Namespace, nil
+	}
+	if f.Deploy.Namespace != "" {
+		return f.Deploy.Namespace, nil
+	}
+	return "", fn.ErrNamespaceRequired
+}
+
+func pipelineRunsListOptions(f fn.Function) metav1.ListOptions {
+	l := k8slabels.SelectorFromSet(k8slabels.Set(map[string]string{fnlabels.FunctionNameKey: f.Name}))
+	return metav1.ListOptions{
+		LabelSelector: l.String(),
+	}
+}
+
+// getPipelineRun returns the named PipelineRun, ensuring it belongs to the
+// function, or the newest PipelineRun of the function if name is empty.
+func getPipelineRun(ctx context.Context, client *pipelineClient.TektonV1Client, f fn.Function, namespace, name string) (*v1.PipelineRun, error) {
+	if name == "" {
+		return findNewestPipelineRunWithRetry(ctx, f, namespace, client)
+	}
+	pr, err := client.PipelineRuns(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("problem in retriving pipeline run: %v", err)
+	}
+	if pr.Labels[fnlabels.FunctionNameKey] != f.Name {
+		return nil, fmt.Errorf("pipeline run %q does not belong to function %q", name, f.Name)
+	}
+	return pr, nil
+}
+
+// getTaskRuns returns the TaskRuns of the PipelineRun in order of execution.
+func getTaskRuns(ctx context.Context, client *pipelineClient.TektonV1Client, pr *v1.PipelineRun, namespace string) ([]*v1.TaskRun, error) {
+	var trs []*v1.TaskRun
+	for _, ref := range pr.Status.ChildReferences {
+		if ref.Kind != "" && ref.Kind != "TaskRun" {
+			continue
+		}
+		tr, err := client.TaskRuns(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting TaskRun %s: %v", ref.Name, err)
+		}
+		if tr.Labels[pipelineTaskLabel] == "" {
+			if tr.Labels == nil {
+				tr.Labels = map[string]string{}
+			}
+			tr.Labels[pipelineTaskLabel] = ref.PipelineTaskName
+		}
+		trs = append(trs, tr)
+	}
+	sort.SliceStable(trs, func(i, j int) bool {
+		si, sj := trs[i].Status.StartTime, trs[j].Status.StartTime
+		if si == nil || sj == nil {
+			return sj == nil && si != nil
+		}
+		return si.Before(sj)
+	})
+	return trs, nil
+}
+
+func toPipelineRun(pr *v1.PipelineRun) fn.PipelineRun {
+	cond := pr.Status.GetCondition(apis.ConditionSucceeded)
+	run := fn.PipelineRun{
+		Name:      pr.Name,
+		Namespace: pr.Namespace,
+		Status:    conditionStatus(cond),
+	}
+	if cond != nil && cond.IsFalse() {
+		run.Message = cond.Message
+	}
+	if pr.Status.StartTime != nil {
+		run.Started = pr.Status.StartTime.Time
+	}
+	if pr.Status.CompletionTime != nil {
+		run.Completed = pr.Status.CompletionTime.Time
+	}
+	return run
+}
+
+// conditionStatus returns a short status such as "Succeeded", "Failed" or
+// "Running" from the Succeeded condition of a Tekton run.
+func conditionStatus(cond *apis.Condition) string {
+	if cond == nil {
+		return "Pending"
+	}
+	if cond.Reason != "" {
+		return cond.Reason
+	}
+	switch cond.Status {
+	case corev1.ConditionTrue:
+		return "Succeeded"
+	case corev1.ConditionFalse:
+		return "Failed"
+	}
+	return "Running"
+}
@@ -0,0 +1,71 @@
+package tekton
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	fn "knative.dev/func/pkg/functions"
+)
+
+func Test_conditionStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		cond *apis.Condition
+		want string
+	}{
+		{"no condition", nil, "Pending"},
+		{"reason", &apis.Condition{Status: corev1.ConditionFalse, Reason: "Cancelled"}, "Cancelled"},
+		{"succeeded", &apis.Condition{Status: corev1.ConditionTrue}, "Succeeded"},
+		{"failed", &apis.Condition{Status: corev1.ConditionFalse}, "Failed"},
+		{"running", &apis.Condition{Status: corev1.ConditionUnknown}, "Running"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conditionStatus(tt.cond); got != tt.want {
+				t.Errorf("conditionStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_toPipelineRun(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	pr := &v1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "run", Namespace: "ns"},
+		Status: v1.PipelineRunStatus{
+			Status: duckv1.Status{Conditions: duckv1.Conditions{{
+				Type:    apis.ConditionSucceeded,
+				Status:  corev1.ConditionFalse,
+				Reason:  "Failed",
+				Message: "task build failed",
+			}}},
+			PipelineRunStatusFields: v1.PipelineRunStatusFields{
+				StartTime:      &metav1.Time{Time: start},
+				CompletionTime: &metav1.Time{Time: start.Add(2 * time.Minute)},
+			},
+		},
+	}
+
+	got := toPipelineRun(pr)
+	want := fn.PipelineRun{
+		Name:      "run",
+		Namespace: "ns",
+		Status:    "Failed",
+		Message:   "task build failed",
+		Started:   start,
+		Completed: start.Add(2 * time.Minute),
+	}
+	if got.Name != want.Name || got.Namespace != want.Namespace || got.Status != want.Status ||
+		got.Message != want.Message || !got.Started.Equal(want.Started) || !got.Completed.Equal(want.Completed) {
+		t.Fatalf("toPipelineRun() = %+v, want %+v", got, want)
+	}
+	if got.Duration() != 2*time.Minute {
+		t.Fatalf("expected a duration of 2m, got %v", got.Duration())
+	}
+}
@@ -8,7 +8,7 @@ import (
 var DeployerImage = "ghcr.io/knative/func-utils:v2"
 
 func getBuildpackTask() string {
-	return `apiVersion: tekton.dev/v1
+	return fmt.Sprintf(`apiVersion: tekton.dev/v1
 kind: Task
 metadata:
   name: func-buildpacks
@@ -52,6 +52,12 @@ spec:
       type: array
       description: Environment variables to set during _build-time_.
       default: []
+    - name: BUILDPACKS
+      type: array
+      description: >-
+        Buildpacks to use instead of the builder's default order, either as ids of buildpacks
+        in the builder or as buildpackage images.
+      default: []
     - name: RUN_IMAGE
       description: Reference to a run image to use.
       default: ""
@@ -158,6 +164,34 @@ spec:
         - name: empty-dir
           mountPath: /emptyDir
 
+    ############################################
+    ##### Added part for Knative Functions #####
+    ############################################
+    - name: install
+      image: %s
+      command: ["install", "/func-bin", "buildpacks"]
+      volumeMounts:
+        - name: func-bin
+          mountPath: /func-bin
+
+    - name: buildpacks
+      image: $(params.BUILDER_IMAGE)
+      imagePullPolicy: Always
+      command: ["/func-bin/buildpacks", "--target", "/cnb-custom"]
+      args: ["$(params.BUILDPACKS[*])"]
+      env:
+        - name: DOCKER_CONFIG
+          value: $(workspaces.dockerconfig.path)
+      volumeMounts:
+        - name: func-bin
+          mountPath: /func-bin
+        - name: cnb-custom
+          mountPath: /cnb-custom
+      securityContext:
+        runAsUser: 1001
+        runAsGroup: 0
+    ############################################
+
     - name: create
       image: $(params.BUILDER_IMAGE)
       imagePullPolicy: Always
@@ -167,6 +201,10 @@ spec:
           value: $(workspaces.dockerconfig.path)
       args:
         - "-app=$(workspaces.source.path)/$(params.SOURCE_SUBPATH)"
+        ##########################################################################
+        #####  "-buildpacks" and "-order" have been added for Knative Functions
+        - "-buildpacks=/cnb-custom/buildpacks"
+        - "-order=/cnb-custom/order.toml"
         - "-cache-dir=$(workspaces.cache.path)"
         - "-cache-image=$(params.CACHE_IMAGE)"
         - "-uid=$(params.USER_ID)"
@@ -183,6 +221,8 @@ spec:
           mountPath: /layers
         - name: $(params.PLATFORM_DIR)
           mountPath: /platform
+        - name: cnb-custom
+          mountPath: /cnb-custom
       securityContext:
         runAsUser: 1001
         #################################################################
@@ -237,7 +277,11 @@ spec:
       emptyDir: {}
     - name: layers-dir
       emptyDir: {}
-`
+    - name: func-bin
+      emptyDir: {}
+    - name: cnb-custom
+      emptyDir: {}
+`, DeployerImage)
 }
 
 func getS2ITask() string {
@@ -374,6 +418,88 @@ spec:
 `, DeployerImage)
 }
 
+func getHostTask() string {
+	return fmt.Sprintf(`apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: func-host
+  labels:
+    app.kubernetes.io/version: "0.1"
+  annotations:
+    tekton.dev/pipelines.minVersion: "0.17.0"
+    tekton.dev/categories: Image Build
+    tekton.dev/tags: image-build
+    tekton.dev/platforms: "linux/amd64"
+spec:
+  description: >-
+    The Knative Functions Host task builds source into a multi-arch container image
+    and pushes it to a registry, using the same OCI builder as a local build with
+    the "host" builder.
+
+    The func-util binary is installed into the language toolchain image given
+    by BUILDER_IMAGE, where it then builds and pushes the function image.
+
+  params:
+    - name: IMAGE
+      description: Reference of the image that will be produced.
+    - name: REGISTRY
+      description: The registry associated with the function image.
+      default: ""
+    - name: PATH_CONTEXT
+      description: The location of the function project within the source workspace.
+      default: .
+    - name: BUILDER_IMAGE
+      description: The language toolchain image in which the build runs.
+  workspaces:
+    - name: source
+      description: Directory where function source is located.
+    - name: cache
+      description: Directory where toolchain caches are stored.
+      optional: true
+    - name: dockerconfig
+      description: >-
+        An optional workspace that allows providing a .docker/config.json file
+        for the pusher to access the container registry.
+        The file should be placed at the root of the Workspace with name config.json.
+      optional: true
+  results:
+    - name: IMAGE_DIGEST
+      description: Digest of the image just built.
+  steps:
+    - name: install
+      image: %s
+      command: ["install", "/func-bin", "host-build"]
+      volumeMounts:
+        - mountPath: /func-bin
+          name: func-bin
+    - name: build
+      image: $(params.BUILDER_IMAGE)
+      workingDir: $(workspaces.source.path)
+      env:
+        - name: GOCACHE
+          value: $(workspaces.cache.path)/go-build
+        - name: GOMODCACHE
+          value: $(workspaces.cache.path)/go-mod
+        - name: PIP_CACHE_DIR
+          value: $(workspaces.cache.path)/pip
+      script: |
+        #!/bin/sh
+        set -e
+        [ "$(workspaces.dockerconfig.bound)" = "true" ] && export DOCKER_CONFIG="$(workspaces.dockerconfig.path)"
+        exec /func-bin/host-build \
+          --path "$(params.PATH_CONTEXT)" \
+          --image "$(params.IMAGE)" \
+          --registry "$(params.REGISTRY)" \
+          --digest-file "$(results.IMAGE_DIGEST.path)"
+      volumeMounts:
+        - mountPath: /func-bin
+          name: func-bin
+  volumes:
+    - emptyDir: {}
+      name: func-bin
+`, DeployerImage)
+}
+
 func getDeployTask() string {
 	return fmt.Sprintf(`apiVersion: tekton.dev/v1
 kind: Task
@@ -396,6 +522,12 @@ spec:
     - name: image
       description: Container image to be deployed
       default: ""
+    - name: pipelineRun
+      description: Name of the PipelineRun deploying the function
+      default: ""
+    - name: gitRevision
+      description: Git revision the function was built from
+      default: ""
   workspaces:
     - name: source
       description: The workspace containing the function project
@@ -403,9 +535,177 @@ spec:
     - name: func-deploy
       image: "%s"
       command: ["deploy", "$(params.path)", "$(params.image)"]
+      env:
+        - name: FUNC_PIPELINE_RUN
+          value: "$(params.pipelineRun)"
+        - name: FUNC_GIT_REVISION
+          value: "$(params.gitRevision)"
+`, DeployerImage)
+}
+
+// getReportTask returns the Task recording a failed build of a function as an
+// Event on the function, as the deploy Task is not run then.
+func getReportTask() string {
+	return fmt.Sprintf(`apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: func-report
+  labels:
+    app.kubernetes.io/version: "0.1"
+  annotations:
+    tekton.dev/pipelines.minVersion: "0.12.1"
+    tekton.dev/categories: CLI
+    tekton.dev/tags: cli
+    tekton.dev/platforms: "linux/amd64"
+spec:
+  description: >-
+    This Task records a failed build of a function as an Event on the function
+  params:
+    - name: name
+      description: Name of the function
+    - name: pipelineRun
+      description: Name of the failed PipelineRun
+      default: ""
+    - name: gitRevision
+      description: Git revision the function was built from
+      default: ""
+  steps:
+    - name: func-report
+      image: "%s"
+      command: ["report", "$(params.name)"]
+      env:
+        - name: FUNC_PIPELINE_RUN
+          value: "$(params.pipelineRun)"
+        - name: FUNC_GIT_REVISION
+          value: "$(params.gitRevision)"
 `, DeployerImage)
 }
 
+// getGitCloneTask returns the Task fetching the sources of a function from
+// Git.  Pipelines otherwise resolve git-clone from the Tekton Hub, whereas
+// this Task is published with the task bundle, such that Pipelines using the
+// bundle need no access to the hub.  It is compatible with git-clone 0.4.
+func getGitCloneTask() string {
+	return `apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: git-clone
+  labels:
+    app.kubernetes.io/version: "0.4"
+  annotations:
+    tekton.dev/pipelines.minVersion: "0.21.0"
+    tekton.dev/categories: Git
+    tekton.dev/tags: git
+    tekton.dev/platforms: "linux/amd64,linux/s390x,linux/ppc64le,linux/arm64"
+spec:
+  description: >-
+    This Task clones a Git repository into the output Workspace, recording
+    the fetched commit as a result.
+  workspaces:
+    - name: output
+      description: The Git repository will be cloned onto the volume backing this Workspace.
+  params:
+    - name: url
+      description: Repository URL to clone from.
+      type: string
+    - name: revision
+      description: Revision to checkout. (branch, tag, sha, ref, etc...)
+      type: string
+      default: ""
+    - name: refspec
+      description: Refspec to fetch before checking out revision.
+      default: ""
+    - name: submodules
+      description: Initialize and fetch git submodules.
+      type: string
+      default: "true"
+    - name: depth
+      description: Perform a shallow clone, fetching only the most recent N commits.
+      type: string
+      default: "1"
+    - name: sslVerify
+      description: Set the http.sslVerify global git config. Setting this to false is not advised unless you are sure that you trust your git remote.
+      type: string
+      default: "true"
+    - name: subdirectory
+      description: Subdirectory inside the output Workspace to clone the repo into.
+      type: string
+      default: ""
+    - name: deleteExisting
+      description: Clean out the contents of the destination directory if it already exists before cloning.
+      type: string
+      default: "true"
+    - name: gitInitImage
+      description: The image providing the git-init binary that this Task runs.
+      type: string
+      default: "gcr.io/tekton-releases/github.com/tektoncd/pipeline/cmd/git-init:v0.21.0"
+  results:
+    - name: commit
+      description: The precise commit SHA that was fetched by this Task.
+    - name: url
+      description: The precise URL that was fetched by this Task.
+  steps:
+    - name: clone
+      image: "$(params.gitInitImage)"
+      env:
+        - name: PARAM_URL
+          value: $(params.url)
+        - name: PARAM_REVISION
+          value: $(params.revision)
+        - name: PARAM_REFSPEC
+          value: $(params.refspec)
+        - name: PARAM_SUBMODULES
+          value: $(params.submodules)
+        - name: PARAM_DEPTH
+          value: $(params.depth)
+        - name: PARAM_SSL_VERIFY
+          value: $(params.sslVerify)
+        - name: PARAM_SUBDIRECTORY
+          value: $(params.subdirectory)
+        - name: PARAM_DELETE_EXISTING
+          value: $(params.deleteExisting)
+        - name: WORKSPACE_OUTPUT_PATH
+          value: $(workspaces.output.path)
+      script: |
+        #!/usr/bin/env sh
+        set -eu
+
+        CHECKOUT_DIR="${WORKSPACE_OUTPUT_PATH}/${PARAM_SUBDIRECTORY}"
+
+        cleandir() {
+          # Delete any existing contents of the repo directory if it exists.
+          #
+          # We don't just "rm -rf ${CHECKOUT_DIR}" because ${CHECKOUT_DIR} might be "/"
+          # or the root of a mounted volume.
+          if [ -d "${CHECKOUT_DIR}" ] ; then
+            # Delete non-hidden files and directories
+            rm -rf "${CHECKOUT_DIR:?}"/*
+            # Delete files and directories starting with . but excluding ..
+            rm -rf "${CHECKOUT_DIR}"/.[!.]*
+            # Delete files and directories starting with .. plus any other character
+            rm -rf "${CHECKOUT_DIR}"/..?*
+          fi
+        }
+
+        if [ "${PARAM_DELETE_EXISTING}" = "true" ] ; then
+          cleandir
+        fi
+
+        /ko-app/git-init \
+          -url="${PARAM_URL}" \
+          -revision="${PARAM_REVISION}" \
+          -refspec="${PARAM_REFSPEC}" \
+          -path="${CHECKOUT_DIR}" \
+          -sslVerify="${PARAM_SSL_VERIFY}" \
+          -submodules="${PARAM_SUBMODULES}" \
+          -depth="${PARAM_DEPTH}"
+        cd "${CHECKOUT_DIR}"
+        RESULT_SHA="$(git rev-parse HEAD)"
+        printf "%s" "${RESULT_SHA}" > "$(results.commit.path)"
+        printf "%s" "${PARAM_URL}" > "$(results.url.path)"
+`
+}
+
 func getScaffoldTask() string {
 	return fmt.Sprintf(`apiVersion: tekton.dev/v1
 kind: Task
@@ -435,7 +735,7 @@ spec:
 
 // GetClusterTasks returns multi-document yaml containing tekton tasks used by func.
 func GetClusterTasks() string {
-	tasks := getBuildpackTask() + "\n---\n" + getS2ITask() + "\n---\n" + getDeployTask() + "\n---\n" + getScaffoldTask()
+	tasks := getBuildpackTask() + "\n---\n" + getS2ITask() + "\n---\n" + getHostTask() + "\n---\n" + getDeployTask() + "\n---\n" + getScaffoldTask() + "\n---\n" + getReportTask()
 	tasks = strings.Replace(tasks, "kind: Task", "kind: ClusterTask", -1)
 	tasks = strings.ReplaceAll(tasks, "apiVersion: tekton.dev/v1", "apiVersion: tekton.dev/v1beta1")
 	return tasks
@@ -63,6 +63,7 @@ const (
           workspace: source-workspace`
 	runAfterFetchSourcesRef = `runAfter:
         - fetch-sources`
+	fetchedGitRevisionRef = "$(tasks.fetch-sources.results.commit)"
 
 	// S2I related properties
 	defaultS2iImageScriptsUrl = "image:///usr/libexec/s2i"
@@ -81,12 +82,20 @@ type templateData struct {
 	Registry      string
 	BuilderImage  string
 	BuildEnvs     []string
+	Buildpacks    []string
 
 	PipelineName    string
 	PipelineRunName string
 	PvcName         string
 	SecretName      string
 
+	// FixedPipelineRunName is whether PipelineRunName is the name of the
+	// PipelineRun rather than the prefix of a generated name
+	FixedPipelineRunName bool
+
+	// Image the build cache is persisted in, besides the pipeline volume
+	CacheImage string
+
 	// The branch or tag we are targeting with Pipelines (ie: main, refs/tags/*)
 	PipelinesTargetBranch string
 
@@ -98,11 +107,15 @@ type templateData struct {
 	GitCloneTaskRef       string
 	FuncBuildpacksTaskRef string
 	FuncS2iTaskRef        string
+	FuncHostTaskRef       string
 	FuncDeployTaskRef     string
 	FuncScaffoldTaskRef   string
+	FuncReportTaskRef     string
 
 	// Reference for build task - whether it should run after fetch-sources task or not
 	RunAfterFetchSources string
+	// Revision of the sources fetched by the fetch-sources task, if any
+	FetchedGitRevision string
 
 	PipelineYamlURL string
 
@@ -119,23 +132,12 @@ func createPipelineTemplatePAC(f fn.Function, labels map[string]string) error {
 		Labels:               labels,
 		PipelineName:         getPipelineName(f),
 		RunAfterFetchSources: runAfterFetchSourcesRef,
+		FetchedGitRevision:   fetchedGitRevisionRef,
 		GitCloneTaskRef:      taskGitClonePACTaskRef,
 	}
 
-	for _, val := range []struct {
-		ref   string
-		field *string
-	}{
-		{getBuildpackTask(), &data.FuncBuildpacksTaskRef},
-		{getS2ITask(), &data.FuncS2iTaskRef},
-		{getDeployTask(), &data.FuncDeployTaskRef},
-		{getScaffoldTask(), &data.FuncScaffoldTaskRef},
-	} {
-		ts, err := getTaskSpec(val.ref)
-		if err != nil {
-			return err
-		}
-		*val.field = ts
+	if err := setTaskRefs(f, &data); err != nil {
+		return err
 	}
 
 	var template string
@@ -143,6 +145,8 @@ func createPipelineTemplatePAC(f fn.Function, labels map[string]string) error {
 		template = packPipelineTemplate
 	} else if f.Build.Builder == builders.S2I {
 		template = s2iPipelineTemplate
+	} else if f.Build.Builder == builders.Host {
+		template = hostPipelineTemplate
 	} else {
 		return builders.ErrBuilderNotSupported{Builder: f.Build.Builder}
 	}
@@ -184,6 +188,13 @@ func createPipelineRunTemplatePAC(f fn.Function, labels map[string]string) error
 		image = f.Image
 	}
 
+	// git-clone is fetched from the Tekton Hub, unless the Pipeline
+	// references it from the task bundle
+	gitCloneTaskRef := taskGitCloneRef
+	if getTaskBundle(f) != "" {
+		gitCloneTaskRef = ""
+	}
+
 	data := templateData{
 		FunctionName:  f.Name,
 		Annotations:   f.Deploy.Annotations,
@@ -193,15 +204,17 @@ func createPipelineRunTemplatePAC(f fn.Function, labels map[string]string) error
 		Registry:      f.Registry,
 		BuilderImage:  getBuilderImage(f),
 		BuildEnvs:     buildEnvs,
+		Buildpacks:    f.Build.Buildpacks,
 
 		PipelineName:    getPipelineName(f),
 		PipelineRunName: fmt.Sprintf("%s-run", getPipelineName(f)),
 		PvcName:         getPipelinePvcName(f),
 		SecretName:      getPipelineSecretName(f),
+		CacheImage:      f.Build.Cache.Image,
 
 		PipelinesTargetBranch: pipelinesTargetBranch,
 
-		GitCloneTaskRef: taskGitCloneRef,
+		GitCloneTaskRef: gitCloneTaskRef,
 
 		PipelineYamlURL: fmt.Sprintf("%s/%s", resourcesDirectory, pipelineFileNamePAC),
 
@@ -216,6 +229,8 @@ func createPipelineRunTemplatePAC(f fn.Function, labels map[string]string) error
 		template = packRunTemplatePAC
 	} else if f.Build.Builder == builders.S2I {
 		template = s2iRunTemplatePAC
+	} else if f.Build.Builder == builders.Host {
+		template = hostRunTemplatePAC
 	} else {
 		return builders.ErrBuilderNotSupported{Builder: f.Build.Builder}
 	}
@@ -276,6 +291,55 @@ func deleteAllPipelineTemplates(f fn.Function) string {
 	return ""
 }
 
+// getTaskName returns the name of the Task defined by the given yaml.
+func getTaskName(taskYaml string) (string, error) {
+	var task struct {
+		Metadata struct {
+			Name string `yaml:"name"`
+		} `yaml:"metadata"`
+	}
+	if err := yaml.Unmarshal([]byte(taskYaml), &task); err != nil {
+		return "", err
+	}
+	return task.Metadata.Name, nil
+}
+
+// setTaskRefs sets the Task references of the Pipeline template data, which
+// either embed the Tasks or reference them from the function's task bundle.
+// With a bundle, git-clone is referenced from the bundle too.
+func setTaskRefs(f fn.Function, data *templateData) error {
+	bundle := getTaskBundle(f)
+	if bundle != "" && data.GitCloneTaskRef != "" {
+		data.GitCloneTaskRef = gitCloneBundleTaskRef(bundle)
+	}
+	for _, val := range []struct {
+		ref   string
+		field *string
+	}{
+		{getBuildpackTask(), &data.FuncBuildpacksTaskRef},
+		{getS2ITask(), &data.FuncS2iTaskRef},
+		{getHostTask(), &data.FuncHostTaskRef},
+		{getDeployTask(), &data.FuncDeployTaskRef},
+		{getScaffoldTask(), &data.FuncScaffoldTaskRef},
+		{getReportTask(), &data.FuncReportTaskRef},
+	} {
+		if bundle != "" {
+			name, err := getTaskName(val.ref)
+			if err != nil {
+				return err
+			}
+			*val.field = bundleTaskRef(bundle, name)
+			continue
+		}
+		ts, err := getTaskSpec(val.ref)
+		if err != nil {
+			return err
+		}
+		*val.field = ts
+	}
+	return nil
+}
+
 func getTaskSpec(taskYaml string) (string, error) {
 	var err error
 	var data map[string]any
@@ -308,8 +372,10 @@ func createAndApplyPipelineTemplate(f fn.Function, namespace string, labels map[
 	// otherwise sources have been already uploaded to workspace PVC.
 	gitCloneTaskRef := ""
 	runAfterFetchSources := ""
+	fetchedGitRevision := ""
 	if f.Build.Git.URL != "" {
 		runAfterFetchSources = runAfterFetchSourcesRef
+		fetchedGitRevision = fetchedGitRevisionRef
 		gitCloneTaskRef = taskGitCloneTaskRef
 	}
 
@@ -319,23 +385,12 @@ func createAndApplyPipelineTemplate(f fn.Function, namespace string, labels map[
 		Labels:               labels,
 		PipelineName:         getPipelineName(f),
 		RunAfterFetchSources: runAfterFetchSources,
+		FetchedGitRevision:   fetchedGitRevision,
 		GitCloneTaskRef:      gitCloneTaskRef,
 	}
 
-	for _, val := range []struct {
-		ref   string
-		field *string
-	}{
-		{getBuildpackTask(), &data.FuncBuildpacksTaskRef},
-		{getS2ITask(), &data.FuncS2iTaskRef},
-		{getDeployTask(), &data.FuncDeployTaskRef},
-		{getScaffoldTask(), &data.FuncScaffoldTaskRef},
-	} {
-		ts, err := getTaskSpec(val.ref)
-		if err != nil {
-			return err
-		}
-		*val.field = ts
+	if err := setTaskRefs(f, &data); err != nil {
+		return err
 	}
 
 	var template string
@@ -343,6 +398,8 @@ func createAndApplyPipelineTemplate(f fn.Function, namespace string, labels map[
 		template = packPipelineTemplate
 	} else if f.Build.Builder == builders.S2I {
 		template = s2iPipelineTemplate
+	} else if f.Build.Builder == builders.Host {
+		template = hostPipelineTemplate
 	} else {
 		return builders.ErrBuilderNotSupported{Builder: f.Build.Builder}
 	}
@@ -350,9 +407,9 @@ func createAndApplyPipelineTemplate(f fn.Function, namespace string, labels map[
 	return createAndApplyResource(f.Root, pipelineFileName, template, "pipeline", getPipelineName(f), namespace, data)
 }
 
-// createAndApplyPipelineRunTemplate creates and applies PipelineRun template for a standard on-cluster build
-// all resources are created on the fly, if there's a PipelineRun defined in the project directory, it is used instead
-func createAndApplyPipelineRunTemplate(f fn.Function, namespace string, labels map[string]string) error {
+// newRunTemplateData returns the data of the PipelineRun templates for a
+// standard on-cluster build.
+func newRunTemplateData(f fn.Function, labels map[string]string) templateData {
 	contextDir := f.Build.Git.ContextDir
 	if contextDir == "" && f.Build.Builder == builders.S2I {
 		// TODO(lkingland): could instead update S2I to interpret empty string
@@ -379,7 +436,7 @@ func createAndApplyPipelineRunTemplate(f fn.Function, namespace string, labels m
 		s2iImageScriptsUrl = quarkusS2iImageScriptsUrl
 	}
 
-	data := templateData{
+	return templateData{
 		FunctionName:  f.Name,
 		Annotations:   f.Deploy.Annotations,
 		Labels:        labels,
@@ -388,23 +445,33 @@ func createAndApplyPipelineRunTemplate(f fn.Function, namespace string, labels m
 		Registry:      f.Registry,
 		BuilderImage:  getBuilderImage(f),
 		BuildEnvs:     buildEnvs,
+		Buildpacks:    f.Build.Buildpacks,
 
 		PipelineName:    getPipelineName(f),
 		PipelineRunName: getPipelineRunGenerateName(f),
 		PvcName:         getPipelinePvcName(f),
 		SecretName:      getPipelineSecretName(f),
+		CacheImage:      f.Build.Cache.Image,
 
 		S2iImageScriptsUrl: s2iImageScriptsUrl,
 
 		RepoUrl:  f.Build.Git.URL,
 		Revision: pipelinesTargetBranch,
 	}
+}
+
+// createAndApplyPipelineRunTemplate creates and applies PipelineRun template for a standard on-cluster build
+// all resources are created on the fly, if there's a PipelineRun defined in the project directory, it is used instead
+func createAndApplyPipelineRunTemplate(f fn.Function, namespace string, labels map[string]string) error {
+	data := newRunTemplateData(f, labels)
 
 	var template string
 	if f.Build.Builder == builders.Pack {
 		template = packRunTemplate
 	} else if f.Build.Builder == builders.S2I {
 		template = s2iRunTemplate
+	} else if f.Build.Builder == builders.Host {
+		template = hostRunTemplate
 	} else {
 		return builders.ErrBuilderNotSupported{Builder: f.Build.Builder}
 	}
@@ -0,0 +1,225 @@
+package tekton
+
+const (
+	// hostPipelineTemplate contains the Host builder template used for both Tekton standard and PAC Pipeline
+	hostPipelineTemplate = `
+apiVersion: tekton.dev/v1beta1
+kind: Pipeline
+metadata:
+  labels:
+    {{range $key, $value := .Labels -}}
+     "{{$key}}": "{{$value}}"
+    {{end}}
+  annotations:
+    {{range $key, $value := .Annotations -}}
+     "{{$key}}": "{{$value}}"
+    {{end}}
+  name: {{.PipelineName}}
+spec:
+  params:
+    - default: ''
+      description: Git repository that hosts the function project
+      name: gitRepository
+      type: string
+    - description: Git revision to build
+      name: gitRevision
+      type: string
+    - default: ''
+      description: Path where the function project is
+      name: contextDir
+      type: string
+    - description: Function image name
+      name: imageName
+      type: string
+    - description: The registry associated with the function image
+      name: registry
+      type: string
+    - description: Builder image to be used
+      name: builderImage
+      type: string
+  tasks:
+    {{.GitCloneTaskRef}}
+    - name: build
+      params:
+        - name: IMAGE
+          value: $(params.imageName)
+        - name: REGISTRY
+          value: $(params.registry)
+        - name: PATH_CONTEXT
+          value: $(params.contextDir)
+        - name: BUILDER_IMAGE
+          value: $(params.builderImage)
+      {{.RunAfterFetchSources}}
+      {{.FuncHostTaskRef}}
+      workspaces:
+        - name: source
+          workspace: source-workspace
+        - name: cache
+          workspace: cache-workspace
+        - name: dockerconfig
+          workspace: dockerconfig-workspace
+    - name: deploy
+      params:
+        - name: path
+          value: $(workspaces.source.path)/$(params.contextDir)
+        - name: image
+          value: $(params.imageName)@$(tasks.build.results.IMAGE_DIGEST)
+        - name: pipelineRun
+          value: $(context.pipelineRun.name)
+        - name: gitRevision
+          value: "{{.FetchedGitRevision}}"
+      runAfter:
+        - build
+      {{.FuncDeployTaskRef}}
+      workspaces:
+        - name: source
+          workspace: source-workspace
+  finally:
+    - name: report
+      params:
+        - name: name
+          value: {{.FunctionName}}
+        - name: pipelineRun
+          value: $(context.pipelineRun.name)
+        - name: gitRevision
+          value: "{{.FetchedGitRevision}}"
+      when:
+        - input: $(tasks.status)
+          operator: in
+          values: ["Failed"]
+        - input: $(tasks.deploy.status)
+          operator: notin
+          values: ["Failed"]
+      {{.FuncReportTaskRef}}
+    {{- if .FetchedGitRevision}}
+    # Tekton skips the above when the commit was not fetched
+    - name: report-fetch
+      params:
+        - name: name
+          value: {{.FunctionName}}
+        - name: pipelineRun
+          value: $(context.pipelineRun.name)
+      when:
+        - input: $(tasks.fetch-sources.status)
+          operator: in
+          values: ["Failed"]
+      {{.FuncReportTaskRef}}
+    {{- end}}
+  workspaces:
+    - description: Directory where function source is located.
+      name: source-workspace
+    - description: Directory where build cache is stored.
+      name: cache-workspace
+    - description: Directory containing image registry credentials stored in config.json file.
+      name: dockerconfig-workspace
+      optional: true
+`
+	// hostRunTemplate contains the Host builder template used for Tekton standard PipelineRun
+	hostRunTemplate = `
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  labels:
+    {{range $key, $value := .Labels -}}
+     "{{$key}}": "{{$value}}"
+    {{end}}
+    tekton.dev/pipeline: {{.PipelineName}}
+  annotations:
+    # User defined Annotations
+    {{range $key, $value := .Annotations -}}
+     "{{$key}}": "{{$value}}"
+    {{end}}
+  {{if .FixedPipelineRunName}}name{{else}}generateName{{end}}: {{.PipelineRunName}}
+spec:
+  params:
+    - name: gitRepository
+      value: {{.RepoUrl}}
+    - name: gitRevision
+      value: {{.Revision}}
+    - name: contextDir
+      value: {{.ContextDir}}
+    - name: imageName
+      value: {{.FunctionImage}}
+    - name: registry
+      value: {{.Registry}}
+    - name: builderImage
+      value: {{.BuilderImage}}
+  pipelineRef:
+   name: {{.PipelineName}}
+  workspaces:
+    - name: source-workspace
+      persistentVolumeClaim:
+        claimName: {{.PvcName}}
+      subPath: source
+    - name: cache-workspace
+      persistentVolumeClaim:
+        claimName: {{.PvcName}}
+      subPath: cache
+    - name: dockerconfig-workspace
+      secret:
+        secretName: {{.SecretName}}
+`
+	// hostRunTemplatePAC contains the Host builder template used for Tekton PAC PipelineRun
+	hostRunTemplatePAC = `
+apiVersion: tekton.dev/v1beta1
+kind: PipelineRun
+metadata:
+  labels:
+    {{range $key, $value := .Labels -}}
+     "{{$key}}": "{{$value}}"
+    {{end}}
+    tekton.dev/pipeline: {{.PipelineName}}
+  annotations:
+    # The event we are targeting as seen from the webhook payload
+    # this can be an array too, i.e: [pull_request, push]
+    pipelinesascode.tekton.dev/on-event: "[push]"
+
+    # The branch or tag we are targeting (ie: main, refs/tags/*)
+    pipelinesascode.tekton.dev/on-target-branch: "[{{.PipelinesTargetBranch}}]"
+
+    {{- if .GitCloneTaskRef}}
+    # Fetch the git-clone task from hub
+    pipelinesascode.tekton.dev/task: {{.GitCloneTaskRef}}
+    {{- end}}
+
+    # Fetch the pipelie definition from the .tekton directory
+    pipelinesascode.tekton.dev/pipeline: {{.PipelineYamlURL}}
+
+    # How many runs we want to keep attached to this event
+    pipelinesascode.tekton.dev/max-keep-runs: "5"
+
+    # User defined Annotations
+    {{range $key, $value := .Annotations -}}
+     "{{$key}}": "{{$value}}"
+    {{end}}
+  generateName: {{.PipelineRunName}}
+spec:
+  params:
+    - name: gitRepository
+      value: {{.RepoUrl}}
+    - name: gitRevision
+      value: {{.Revision}}
+    - name: contextDir
+      value: {{.ContextDir}}
+    - name: imageName
+      value: {{.FunctionImage}}
+    - name: registry
+      value: {{.Registry}}
+    - name: builderImage
+      value: {{.BuilderImage}}
+  pipelineRef:
+   name: {{.PipelineName}}
+  workspaces:
+    - name: source-workspace
+      persistentVolumeClaim:
+        claimName: {{.PvcName}}
+      subPath: source
+    - name: cache-workspace
+      persistentVolumeClaim:
+        claimName: {{.PvcName}}
+      subPath: cache
+    - name: dockerconfig-workspace
+      secret:
+        secretName: {{.SecretName}}
+`
+)
@@ -40,6 +40,14 @@ spec:
     - description: Environment variables to set during build time
       name: buildEnvs
       type: array
+    - default: []
+      description: Buildpacks to use instead of the builder's default order
+      name: buildpacks
+      type: array
+    - default: ''
+      description: Image to persist the build cache in
+      name: cacheImage
+      type: string
   tasks:
     {{.GitCloneTaskRef}}
     - name: scaffold
@@ -64,6 +72,11 @@ spec:
         - name: ENV_VARS
           value:
             - '$(params.buildEnvs[*])'
+        - name: BUILDPACKS
+          value:
+            - '$(params.buildpacks[*])'
+        - name: CACHE_IMAGE
+          value: $(params.cacheImage)
       runAfter:
         - scaffold
       {{.FuncBuildpacksTaskRef}}
@@ -80,12 +93,47 @@ spec:
           value: $(workspaces.source.path)/$(params.contextDir)
         - name: image
           value: $(params.imageName)@$(tasks.build.results.IMAGE_DIGEST)
+        - name: pipelineRun
+          value: $(context.pipelineRun.name)
+        - name: gitRevision
+          value: "{{.FetchedGitRevision}}"
       runAfter:
         - build
       {{.FuncDeployTaskRef}}
       workspaces:
         - name: source
           workspace: source-workspace
+  finally:
+    - name: report
+      params:
+        - name: name
+          value: {{.FunctionName}}
+        - name: pipelineRun
+          value: $(context.pipelineRun.name)
+        - name: gitRevision
+          value: "{{.FetchedGitRevision}}"
+      when:
+        - input: $(tasks.status)
+          operator: in
+          values: ["Failed"]
+        - input: $(tasks.deploy.status)
+          operator: notin
+          values: ["Failed"]
+      {{.FuncReportTaskRef}}
+    {{- if .FetchedGitRevision}}
+    # Tekton skips the above when the commit was not fetched
+    - name: report-fetch
+      params:
+        - name: name
+          value: {{.FunctionName}}
+        - name: pipelineRun
+          value: $(context.pipelineRun.name)
+      when:
+        - input: $(tasks.fetch-sources.status)
+          operator: in
+          values: ["Failed"]
+      {{.FuncReportTaskRef}}
+    {{- end}}
   workspaces:
     - description: Directory where function source is located.
       name: source-workspace
@@ -111,7 +159,7 @@ metadata:
     {{range $key, $value := .Annotations -}}
      "{{$key}}": "{{$value}}"
     {{end}}
-  generateName: {{.PipelineRunName}}
+  {{if .FixedPipelineRunName}}name{{else}}generateName{{end}}: {{.PipelineRunName}}
 spec:
   params:
     - name: gitRepository
@@ -131,6 +179,13 @@ spec:
         {{range .BuildEnvs -}}
            - {{.}}
         {{end}}
+    - name: buildpacks
+      value: {{if not .Buildpacks}}[]{{end}}
+        {{range .Buildpacks -}}
+           - {{.}}
+        {{end}}
+    - name: cacheImage
+      value: "{{.CacheImage}}"
   pipelineRef:
    name: {{.PipelineName}}
   workspaces:
@@ -164,8 +219,10 @@ metadata:
     # The branch or tag we are targeting (ie: main, refs/tags/*)
     pipelinesascode.tekton.dev/on-target-branch: "[{{.PipelinesTargetBranch}}]"
 
+    {{- if .GitCloneTaskRef}}
     # Fetch the git-clone task from hub
     pipelinesascode.tekton.dev/task: {{.GitCloneTaskRef}}
+    {{- end}}
 
     # How many runs we want to keep attached to this event
     pipelinesascode.tekton.dev/max-keep-runs: "5"
@@ -194,6 +251,13 @@ spec:
         {{range .BuildEnvs -}}
            - {{.}}
         {{end}}
+    - name: buildpacks
+      value: {{if not .Buildpacks}}[]{{end}}
+        {{range .Buildpacks -}}
+           - {{.}}
+        {{end}}
+    - name: cacheImage
+      value: "{{.CacheImage}}"
   pipelineRef:
    name: {{.PipelineName}}
   workspaces:
@@ -86,12 +86,47 @@ spec:
           value: $(workspaces.source.path)/$(params.contextDir)
         - name: image
           value: $(params.imageName)@$(tasks.build.results.IMAGE_DIGEST)
+        - name: pipelineRun
+          value: $(context.pipelineRun.name)
+        - name: gitRevision
+          value: "{{.FetchedGitRevision}}"
       runAfter:
         - build
       {{.FuncDeployTaskRef}}
       workspaces:
         - name: source
           workspace: source-workspace
+  finally:
+    - name: report
+      params:
+        - name: name
+          value: {{.FunctionName}}
+        - name: pipelineRun
+          value: $(context.pipelineRun.name)
+        - name: gitRevision
+          value: "{{.FetchedGitRevision}}"
+      when:
+        - input: $(tasks.status)
+          operator: in
+          values: ["Failed"]
+        - input: $(tasks.deploy.status)
+          operator: notin
+          values: ["Failed"]
+      {{.FuncReportTaskRef}}
+    {{- if .FetchedGitRevision}}
+    # Tekton skips the above when the commit was not fetched
+    - name: report-fetch
+      params:
+        - name: name
+          value: {{.FunctionName}}
+        - name: pipelineRun
+          value: $(context.pipelineRun.name)
+      when:
+        - input: $(tasks.fetch-sources.status)
+          operator: in
+          values: ["Failed"]
+      {{.FuncReportTaskRef}}
+    {{- end}}
   workspaces:
     - description: Directory where function source is located.
       name: source-workspace
@@ -116,7 +151,7 @@ metadata:
     {{range $key, $value := .Annotations -}}
      "{{$key}}": "{{$value}}"
     {{end}}
-  generateName: {{.PipelineRunName}}
+  {{if .FixedPipelineRunName}}name{{else}}generateName{{end}}: {{.PipelineRunName}}
 spec:
   params:
     - name: gitRepository
@@ -171,8 +206,10 @@ metadata:
     # The branch or tag we are targeting (ie: main, refs/tags/*)
     pipelinesascode.tekton.dev/on-target-branch: "[{{.PipelinesTargetBranch}}]"
 
+    {{- if .GitCloneTaskRef}}
     # Fetch the git-clone task from hub
     pipelinesascode.tekton.dev/task: {{.GitCloneTaskRef}}
+    {{- end}}
 
     # Fetch the pipelie definition from the .tekton directory
     pipelinesascode.tekton.dev/pipeline: {{.PipelineYamlURL}}
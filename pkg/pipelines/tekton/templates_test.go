@@ -1,11 +1,14 @@
 package tekton
 
 import (
+	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/manifestival/manifestival"
 	"github.com/manifestival/manifestival/fake"
+	"gopkg.in/yaml.v3"
 
 	"knative.dev/func/pkg/builders"
 	fn "knative.dev/func/pkg/functions"
@@ -38,6 +41,12 @@ func Test_createPipelineTemplatePAC(t *testing.T) {
 			builder: builders.S2I,
 			wantErr: false,
 		},
+		{
+			name:    "correct - host builder",
+			root:    "testdata/testCreatePipelineTemplateHost",
+			builder: builders.Host,
+			wantErr: false,
+		},
 		{
 			name:    "incorrect - foo builder",
 			root:    "testdata/testCreatePipelineTemplateFoo",
@@ -100,6 +109,12 @@ func Test_createPipelineRunTemplatePAC(t *testing.T) {
 			builder: builders.S2I,
 			wantErr: false,
 		},
+		{
+			name:    "correct - host builder",
+			root:    "testdata/testCreatePipelineRunTemplateHost",
+			builder: builders.Host,
+			wantErr: false,
+		},
 		{
 			name:    "incorrect - foo builder",
 			root:    "testdata/testCreatePipelineRunTemplateFoo",
@@ -143,6 +158,66 @@ func Test_createPipelineRunTemplatePAC(t *testing.T) {
 	}
 }
 
+// Test_createPipelineRunTemplatePAC_Buildpacks ensures that the function's
+// buildpacks are passed to the pack PipelineRun, and that an empty list is
+// rendered as a valid empty array.
+func Test_createPipelineRunTemplatePAC_Buildpacks(t *testing.T) {
+	tests := []struct {
+		name       string
+		buildpacks []string
+	}{
+		{name: "without buildpacks", buildpacks: nil},
+		{name: "with buildpacks", buildpacks: []string{"paketo-buildpacks/go", "docker://example.com/alice/bp:v1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			f := fn.Function{
+				Root:    root,
+				Name:    "testfunc",
+				Runtime: "go",
+				Image:   "docker.io/alice/testfunc",
+				Build:   fn.BuildSpec{Builder: builders.Pack, Buildpacks: tt.buildpacks},
+			}
+			if err := createPipelineRunTemplatePAC(f, make(map[string]string)); err != nil {
+				t.Fatal(err)
+			}
+
+			bb, err := os.ReadFile(filepath.Join(root, resourcesDirectory, pipelineRunFilenamePAC))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var run struct {
+				Spec struct {
+					Params []struct {
+						Name  string `yaml:"name"`
+						Value any    `yaml:"value"`
+					} `yaml:"params"`
+				} `yaml:"spec"`
+			}
+			if err = yaml.Unmarshal(bb, &run); err != nil {
+				t.Fatal(err)
+			}
+
+			want := []any{}
+			for _, bp := range tt.buildpacks {
+				want = append(want, bp)
+			}
+			for _, p := range run.Spec.Params {
+				if p.Name != "buildpacks" {
+					continue
+				}
+				if !reflect.DeepEqual(p.Value, want) {
+					t.Fatalf("expected buildpacks %v, got %#v", want, p.Value)
+				}
+				return
+			}
+			t.Fatal("buildpacks param not found")
+		})
+	}
+}
+
 // testData are used by Test_createAndApplyPipelineTemplate() and Test_createAndApplyPipelineRunTemplate()
 var testData = []struct {
 	name      string
@@ -265,6 +340,22 @@ var testData = []struct {
 		namespace: "test-ns",
 		wantErr:   false,
 	},
+	{
+		name:      "correct - host & go",
+		root:      "testdata/testCreatePipelineHostGo",
+		runtime:   "go",
+		builder:   builders.Host,
+		namespace: "test-ns",
+		wantErr:   false,
+	},
+	{
+		name:      "correct - host & python",
+		root:      "testdata/testCreatePipelineHostPython",
+		runtime:   "python",
+		builder:   builders.Host,
+		namespace: "test-ns",
+		wantErr:   false,
+	},
 }
 
 func Test_createAndApplyPipelineRunTemplate(t *testing.T) {
@@ -4,16 +4,25 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/google/go-containerregistry/pkg/name"
+
 	"knative.dev/func/pkg/builders"
 	"knative.dev/func/pkg/builders/s2i"
 	fn "knative.dev/func/pkg/functions"
+	"knative.dev/func/pkg/oci"
 )
 
 var (
 	// ErrRuntimeRequired indicates the required value of Function Runtime was not provided
 	ErrRuntimeRequired = errors.New("runtime is required to build")
 
-	ErrBuilpacksNotSupported = errors.New("additional Buildpacks are not supported for on cluster build")
+	// ErrCacheImageNotSupported indicates that a build cache image was
+	// configured for a builder which can not use it
+	ErrCacheImageNotSupported = errors.New("build cache image is only supported by the pack builder")
+
+	// ErrTaskBundleNotPinned indicates that the task bundle is not referenced
+	// by digest
+	ErrTaskBundleNotPinned = errors.New("task bundle must be referenced by digest (registry/repository@sha256:<digest>)")
 )
 
 type ErrRuntimeNotSupported struct {
@@ -25,17 +34,31 @@ func (e ErrRuntimeNotSupported) Error() string {
 }
 
 func validatePipeline(f fn.Function) error {
+	if f.Build.Cache.Image != "" && f.Build.Builder != builders.Pack {
+		return ErrCacheImageNotSupported
+	}
+	if f.Build.TaskBundle != "" {
+		// Tags are mutable, the Tasks could change under the function
+		if _, err := name.NewDigest(f.Build.TaskBundle); err != nil {
+			return fmt.Errorf("%w: %q: %v", ErrTaskBundleNotPinned, f.Build.TaskBundle, err)
+		}
+	}
+
 	if f.Build.Builder == builders.Pack {
 		if f.Runtime == "" {
 			return ErrRuntimeRequired
 		}
-
-		if len(f.Build.Buildpacks) > 0 {
-			return ErrBuilpacksNotSupported
-		}
 	} else if f.Build.Builder == builders.S2I {
 		_, err := s2i.BuilderImage(f, builders.S2I)
 		return err
+	} else if f.Build.Builder == builders.Host {
+		if f.Runtime == "" {
+			return ErrRuntimeRequired
+		}
+
+		if !oci.IsSupported(f.Runtime) {
+			return ErrRuntimeNotSupported{Runtime: f.Runtime}
+		}
 	} else {
 		return builders.ErrUnknownBuilder{Name: f.Build.Builder}
 	}
@@ -67,7 +67,7 @@ func Test_validatePipeline(t *testing.T) {
 		{
 			name:     "Supported runtime - pack builder - with additional Buildpacks",
 			function: fn.Function{Build: fn.BuildSpec{Builder: builders.Pack, Buildpacks: testBuildpacks}, Runtime: "node"},
-			wantErr:  true,
+			wantErr:  false,
 		},
 		{
 			name:     "Supported runtime - Go - pack builder",
@@ -104,6 +104,46 @@ func Test_validatePipeline(t *testing.T) {
 			function: fn.Function{Build: fn.BuildSpec{Builder: builders.S2I}, Runtime: "rust"},
 			wantErr:  true,
 		},
+		{
+			name:     "Without runtime - host builder",
+			function: fn.Function{Build: fn.BuildSpec{Builder: builders.Host}},
+			wantErr:  true,
+		},
+		{
+			name:     "Supported runtime - Go - host builder",
+			function: fn.Function{Build: fn.BuildSpec{Builder: builders.Host}, Runtime: "go"},
+			wantErr:  false,
+		},
+		{
+			name:     "Supported runtime - Python - host builder",
+			function: fn.Function{Build: fn.BuildSpec{Builder: builders.Host}, Runtime: "python"},
+			wantErr:  false,
+		},
+		{
+			name:     "Unsupported runtime - Node - host builder",
+			function: fn.Function{Build: fn.BuildSpec{Builder: builders.Host}, Runtime: "node"},
+			wantErr:  true,
+		},
+		{
+			name:     "Build cache image - pack builder",
+			function: fn.Function{Build: fn.BuildSpec{Builder: builders.Pack, Cache: fn.BuildCache{Image: "quay.io/foo/cache"}}, Runtime: "go"},
+			wantErr:  false,
+		},
+		{
+			name:     "Build cache image - s2i builder",
+			function: fn.Function{Build: fn.BuildSpec{Builder: builders.S2I, Cache: fn.BuildCache{Image: "quay.io/foo/cache"}}, Runtime: "node"},
+			wantErr:  true,
+		},
+		{
+			name:     "Task bundle - pinned by digest",
+			function: fn.Function{Build: fn.BuildSpec{Builder: builders.Pack, TaskBundle: "quay.io/foo/func-tasks@sha256:0000000000000000000000000000000000000000000000000000000000000000"}, Runtime: "go"},
+			wantErr:  false,
+		},
+		{
+			name:     "Task bundle - tag",
+			function: fn.Function{Build: fn.BuildSpec{Builder: builders.Pack, TaskBundle: "quay.io/foo/func-tasks:v1"}, Runtime: "go"},
+			wantErr:  true,
+		},
 	}
 
 	for _, tt := range tests {
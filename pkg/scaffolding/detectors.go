@@ -1,12 +1,10 @@
 package scaffolding
 
 import (
-	"fmt"
-	"go/ast"
-	"go/parser"
-	"go/token"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -15,6 +13,13 @@ type detector interface {
 	Detect(dir string) (static, instanced bool, err error)
 }
 
+// verifier is a detector which can also verify that the source code
+// correctly implements the detected signature, returning an error which
+// locates the problem in the source.
+type verifier interface {
+	Verify(dir string, s Signature) error
+}
+
 // newDetector returns a deector instance for the given runtime.
 func newDetector(runtime string) (detector, error) {
 	switch runtime {
@@ -23,11 +28,11 @@ func newDetector(runtime string) (detector, error) {
 	case "python":
 		return &pythonDetector{}, nil
 	case "rust":
-		return nil, ErrDetectorNotImplemented{runtime}
+		return &rustDetector{}, nil
 	case "node":
-		return nil, ErrDetectorNotImplemented{runtime}
+		return &nodeDetector{}, nil
 	case "typescript":
-		return nil, ErrDetectorNotImplemented{runtime}
+		return &typescriptDetector{}, nil
 	case "quarkus":
 		return nil, ErrDetectorNotImplemented{runtime}
 	case "java":
@@ -37,58 +42,122 @@ func newDetector(runtime string) (detector, error) {
 	}
 }
 
-// GO
+// PYTHON
 
-type goDetector struct{}
+type pythonDetector struct{}
 
-func (d goDetector) Detect(dir string) (static, instanced bool, err error) {
-	files, err := os.ReadDir(dir)
-	if err != nil {
-		return static, instanced, fmt.Errorf("signature detector encountered an error when scanning the function's source code. %w", err)
-	}
-	for _, file := range files {
-		filename := filepath.Join(dir, file.Name())
-		if file.IsDir() || !strings.HasSuffix(filename, ".go") {
-			continue
-		}
-		if d.hasFunctionDeclaration(filename, "New") {
-			instanced = true
-		}
-		if d.hasFunctionDeclaration(filename, "Handle") {
-			static = true
-		}
+func (d pythonDetector) Detect(dir string) (static, instanced bool, err error) {
+	// The python middleware handles detecting if the Function has the static
+	// and/or instanced method signatures, so we can presume the suggested
+	// default of "instanced" here.
+	// Ideally we'd refactor this to support in-code the idea of "detection
+	// not necessary for this runtime".
+	instanced = true
+	return
+}
+
+// NODE
+
+// The JavaScript (and TypeScript) module forms which export a static
+// 'handle' function, or an instanced 'new' factory function which returns an
+// object implementing 'handle' (and optionally lifecycle methods).
+var (
+	jsStaticExport = regexp.MustCompile(`module\.exports\s*=\s*\{[^}]*\bhandle\b|` +
+		`(module\.)?exports\.handle\s*=|` +
+		`export\s+(async\s+)?function\s+handle\b|` +
+		`export\s+(const|let|var)\s+handle\b|` +
+		`export\s*\{[^}]*\bhandle\b[^}]*\}`)
+	jsInstancedExport = regexp.MustCompile(`module\.exports\s*=\s*\{[^}]*\bnew\s*:|` +
+		`(module\.)?exports\.new\s*=|` +
+		`export\s*\{[^}]*\bas\s+new\b[^}]*\}`)
+	cComments = regexp.MustCompile(`(?s)/\*.*?\*/|//[^\n]*`)
+)
+
+type nodeDetector struct{}
+
+// Detect the exports of the package's main module (package.json "main",
+// default index.js) and any other top-level modules.
+func (d nodeDetector) Detect(dir string) (static, instanced bool, err error) {
+	files := []string{filepath.Join(dir, packageMain(dir))}
+	for _, ext := range []string{"*.js", "*.cjs", "*.mjs"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, ext))
+		files = append(files, matches...)
 	}
+	static, instanced = detectExports(files, jsStaticExport, jsInstancedExport)
 	return
 }
 
-func (d goDetector) hasFunctionDeclaration(filename, function string) bool {
-	astFile, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.SkipObjectResolution)
-	if err != nil {
-		return false
+// packageMain returns the main module of the node package in dir.
+func packageMain(dir string) string {
+	pkg := struct {
+		Main string `json:"main"`
+	}{}
+	if bb, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		_ = json.Unmarshal(bb, &pkg)
+	}
+	if pkg.Main == "" {
+		return "index.js"
 	}
-	for _, decl := range astFile.Decls {
-		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
-			// Name matches and it has no reciver.  I.e. a package level function
-			if funcDecl.Name.Name == function && funcDecl.Recv == nil {
-				return true
+	return filepath.FromSlash(pkg.Main)
+}
+
+// TYPESCRIPT
+
+type typescriptDetector struct{}
+
+// Detect the exports of the TypeScript sources in src (or the package root).
+func (d typescriptDetector) Detect(dir string) (static, instanced bool, err error) {
+	var files []string
+	for _, pattern := range []string{"src/*.ts", "*.ts"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern)))
+		for _, m := range matches {
+			if !strings.HasSuffix(m, ".d.ts") {
+				files = append(files, m)
 			}
 		}
 	}
-	return false
+	static, instanced = detectExports(files, jsStaticExport, jsInstancedExport)
+	return
 }
 
-// PYTHON
+// RUST
 
-type pythonDetector struct{}
+// A Rust function is a library crate (src/lib.rs) whose handler module
+// (src/handler.rs) implements a static 'handle' (or, for HTTP, 'index')
+// function, or whose library root implements an instanced 'new' function.
+var (
+	rustStatic    = regexp.MustCompile(`(?m)^pub\s+async\s+fn\s+(handle|index)\s*[(<]`)
+	rustInstanced = regexp.MustCompile(`(?m)^pub\s+fn\s+new\s*[(<]`)
+)
 
-func (d pythonDetector) Detect(dir string) (static, instanced bool, err error) {
-	// The python middleware handles detecting if the Function has the static
-	// and/or instanced method signatures, so we can presume the suggested
-	// default of "instanced" here.
-	// Ideally we'd refactor this to support in-code the idea of "detection
-	// not necessary for this runtime".
-	instanced = true
+type rustDetector struct{}
+
+func (d rustDetector) Detect(dir string) (static, instanced bool, err error) {
+	var (
+		lib     = filepath.Join(dir, "src", "lib.rs")
+		handler = filepath.Join(dir, "src", "handler.rs")
+	)
+	static, _ = detectExports([]string{lib, handler}, rustStatic, nil)
+	instanced, _ = detectExports([]string{lib}, rustInstanced, nil)
 	return
 }
 
-// TODO: Other Detectors
+// detectExports returns whether any of the given files, with comments
+// removed, match the static and instanced expressions.  Files which do not
+// exist are ignored.
+func detectExports(files []string, staticExpr, instancedExpr *regexp.Regexp) (static, instanced bool) {
+	for _, file := range files {
+		bb, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		bb = cComments.ReplaceAll(bb, nil)
+		if staticExpr != nil && staticExpr.Match(bb) {
+			static = true
+		}
+		if instancedExpr != nil && instancedExpr.Match(bb) {
+			instanced = true
+		}
+	}
+	return
+}
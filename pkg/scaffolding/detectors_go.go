@@ -0,0 +1,468 @@
+package scaffolding
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// GO
+
+// goDetector detects and verifies the method signatures of a Go function by
+// parsing and type-checking its package.
+//
+// The function's dependencies need not be available: imported packages are
+// stubbed, and the types of parameters and results are identified by their
+// import path and name.  Types which can not be identified this way (for
+// example those of a dot-import) are presumed correct, leaving them to the
+// compiler.
+type goDetector struct{}
+
+func (d goDetector) Detect(dir string) (static, instanced bool, err error) {
+	p, err := loadGoPackage(dir)
+	if err != nil {
+		return
+	}
+	handle, constructor := p.funcs["Handle"], p.funcs["New"]
+	if handle != nil && constructor != nil {
+		return true, true, ErrInvalidSignature{p.position(constructor.Name.Pos()),
+			fmt.Sprintf("function may not implement both the instanced (New) and static (Handle at %v) method signatures simultaneously", p.position(handle.Name.Pos()))}
+	}
+	return handle != nil, constructor != nil, nil
+}
+
+// Verify that the function's package correctly implements the signature.
+func (d goDetector) Verify(dir string, s Signature) error {
+	p, err := loadGoPackage(dir)
+	if err != nil {
+		return err
+	}
+	switch s {
+	case StaticHTTP:
+		return p.verifyHTTPHandler(p.funcs["Handle"])
+	case StaticCloudevents:
+		return p.verifyCloudeventHandler(p.funcs["Handle"], true)
+	case InstancedHTTP, InstancedCloudevents:
+		constructor := p.funcs["New"]
+		handle, err := p.instanceHandler(constructor)
+		if err != nil {
+			return err
+		}
+		if handle != nil {
+			if s == InstancedHTTP {
+				err = p.verifyHTTPHandler(handle)
+			} else {
+				err = p.verifyCloudeventHandler(handle, false)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return p.verifyLifecycle(constructor)
+	}
+	return nil
+}
+
+// goPackage is the parsed and type-checked source of a function.
+type goPackage struct {
+	fset  *token.FileSet
+	pkg   *types.Package
+	info  *types.Info
+	funcs map[string]*ast.FuncDecl    // package-level functions by name
+	decls map[token.Pos]*ast.FuncDecl // all functions and methods by position
+}
+
+// loadGoPackage parses and type-checks the Go package in dir, excluding
+// tests and files excluded by build constraints when building for linux.
+// Syntax errors are returned, type errors are ignored.
+func loadGoPackage(dir string) (*goPackage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("signature detector encountered an error when scanning the function's source code. %w", err)
+	}
+	var (
+		p = &goPackage{
+			fset: token.NewFileSet(),
+			info: &types.Info{
+				Defs: map[*ast.Ident]types.Object{},
+				Uses: map[*ast.Ident]types.Object{},
+			},
+			funcs: map[string]*ast.FuncDecl{},
+			decls: map[token.Pos]*ast.FuncDecl{},
+		}
+		ctx   = build.Default
+		files []*ast.File
+	)
+	ctx.GOOS = "linux"
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if ok, err := ctx.MatchFile(dir, name); err != nil || !ok {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		// Positions are reported relative to the function's root
+		file, err := parser.ParseFile(p.fset, name, src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+		for _, decl := range file.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok {
+				p.decls[fd.Name.Pos()] = fd
+				if fd.Recv == nil {
+					p.funcs[fd.Name.Name] = fd
+				}
+			}
+		}
+	}
+	if len(files) == 0 {
+		return p, nil
+	}
+	conf := types.Config{
+		Importer: stubImporter{},
+		Error:    func(error) {}, // missing dependencies are expected
+	}
+	p.pkg, _ = conf.Check(files[0].Name.Name, p.fset, files, p.info)
+	return p, nil
+}
+
+// position of pos in the function's source.
+func (p *goPackage) position(pos token.Pos) token.Position {
+	return p.fset.Position(pos)
+}
+
+// Types of parameters and results, identified by import path and name.
+const (
+	goContext        = "context.Context"
+	goResponseWriter = "net/http.ResponseWriter"
+	goRequest        = "*net/http.Request"
+	goEvent          = "github.com/cloudevents/sdk-go/v2/event.Event"
+	goEventPtr       = "*" + goEvent
+	goError          = "error"
+	goResult         = "github.com/cloudevents/sdk-go/v2/protocol.Result"
+)
+
+// goAliases are types re-exported by other packages.
+var goAliases = map[string]string{
+	"github.com/cloudevents/sdk-go/v2.Event":  goEvent,
+	"github.com/cloudevents/sdk-go/v2.Result": goResult,
+}
+
+// typeOf returns the import path qualified name of the type expressed by
+// expr, and false if it can not be determined.
+func (p *goPackage) typeOf(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return p.typeOf(e.X)
+	case *ast.StarExpr:
+		t, ok := p.typeOf(e.X)
+		return "*" + t, ok
+	case *ast.Ident:
+		// Predeclared types, or those declared in the function's package
+		// which, unless aliases, can not be any of the expected types.
+		obj, ok := p.info.Uses[e].(*types.TypeName)
+		if !ok || (obj.IsAlias() && obj.Pkg() != nil) {
+			return "", false
+		}
+		return e.Name, true
+	case *ast.MapType:
+		k, kok := p.typeOf(e.Key)
+		v, vok := p.typeOf(e.Value)
+		return "map[" + k + "]" + v, kok && vok
+	case *ast.SelectorExpr:
+		x, ok := e.X.(*ast.Ident)
+		if !ok {
+			return "", false
+		}
+		pkg, ok := p.info.Uses[x].(*types.PkgName)
+		if !ok {
+			return "", false
+		}
+		t := pkg.Imported().Path() + "." + e.Sel.Name
+		if alias, ok := goAliases[t]; ok {
+			t = alias
+		}
+		return t, true
+	}
+	return "", false
+}
+
+// is returns true if expr is one of the given types, or its type can not
+// be determined.
+func (p *goPackage) is(expr ast.Expr, tt ...string) bool {
+	t, ok := p.typeOf(expr)
+	if !ok {
+		return true
+	}
+	for _, want := range tt {
+		if t == want {
+			return true
+		}
+	}
+	return false
+}
+
+// fields flattens a parameter or result list to one expression per value.
+func fields(l *ast.FieldList) (ff []*ast.Field) {
+	if l == nil {
+		return
+	}
+	for _, f := range l.List {
+		for i := 0; i < len(f.Names) || i == 0; i++ {
+			ff = append(ff, f)
+		}
+	}
+	return
+}
+
+// verifyHTTPHandler verifies fd is an HTTP handler:
+//
+//	Handle(http.ResponseWriter, *http.Request)
+func (p *goPackage) verifyHTTPHandler(fd *ast.FuncDecl) error {
+	const expected = "an HTTP function's Handle must be of the form Handle(http.ResponseWriter, *http.Request)"
+	params, results := fields(fd.Type.Params), fields(fd.Type.Results)
+	if len(params) != 2 {
+		return ErrInvalidSignature{p.position(fd.Name.Pos()),
+			fmt.Sprintf("Handle has %v parameters; %v", len(params), expected)}
+	}
+	for i, want := range []string{goResponseWriter, goRequest} {
+		if !p.is(params[i].Type, want) {
+			return ErrInvalidSignature{p.position(params[i].Type.Pos()),
+				fmt.Sprintf("Handle parameter %v has type %v; %v", i+1, types.ExprString(params[i].Type), expected)}
+		}
+	}
+	if len(results) > 0 {
+		return ErrInvalidSignature{p.position(fd.Type.Results.Pos()),
+			fmt.Sprintf("Handle may not return a value; %v", expected)}
+	}
+	return nil
+}
+
+// verifyCloudeventHandler verifies fd is a CloudEvent handler of the form
+// accepted by the CloudEvents SDK, with any of the parameters or results
+// omitted:
+//
+//	Handle(context.Context, event.Event) (*event.Event, error)
+//
+// A static handler may also return a protocol.Result in place of the error.
+func (p *goPackage) verifyCloudeventHandler(fd *ast.FuncDecl, static bool) error {
+	const expected = "a CloudEvents function's Handle must be of the form Handle(context.Context, event.Event) (*event.Event, error), with any parameters or results omitted"
+	errTypes := []string{goError}
+	if static {
+		errTypes = append(errTypes, goResult)
+	}
+
+	params := fields(fd.Type.Params)
+	i := 0
+	if i < len(params) && p.is(params[i].Type, goContext) {
+		i++
+	}
+	if i < len(params) && p.is(params[i].Type, goEvent) {
+		i++
+	}
+	if i < len(params) {
+		return ErrInvalidSignature{p.position(params[i].Type.Pos()),
+			fmt.Sprintf("Handle parameter %v has unexpected type %v; %v", i+1, types.ExprString(params[i].Type), expected)}
+	}
+
+	results := fields(fd.Type.Results)
+	i = 0
+	if i < len(results) && p.is(results[i].Type, goEventPtr) {
+		i++
+	}
+	if i < len(results) && p.is(results[i].Type, errTypes...) {
+		i++
+	}
+	if i < len(results) {
+		return ErrInvalidSignature{p.position(results[i].Type.Pos()),
+			fmt.Sprintf("Handle result %v has unexpected type %v; %v", i+1, types.ExprString(results[i].Type), expected)}
+	}
+	return nil
+}
+
+// instance verifies that New is a constructor of an instance, and returns
+// the type of the instance.  Nil is returned without error if the instance's
+// type can not be inspected, such as when it is declared in another package.
+func (p *goPackage) instance(constructor *ast.FuncDecl) (types.Type, error) {
+	if n := len(fields(constructor.Type.Params)); n > 0 {
+		return nil, ErrInvalidSignature{p.position(constructor.Type.Params.Pos()),
+			fmt.Sprintf("New may not accept parameters, has %v", n)}
+	}
+	if n := len(fields(constructor.Type.Results)); n != 1 {
+		return nil, ErrInvalidSignature{p.position(constructor.Name.Pos()),
+			fmt.Sprintf("New must return the function instance, returns %v values", n)}
+	}
+	obj, ok := p.info.Defs[constructor.Name].(*types.Func)
+	if !ok {
+		return nil, nil
+	}
+	t := obj.Type().(*types.Signature).Results().At(0).Type()
+	if !p.declared(t) {
+		return nil, nil
+	}
+	return t, nil
+}
+
+// method returns the declaration of the named method of the instance type t
+// returned by New.  Returned is false if t has no such method, and a nil
+// declaration if it can not be inspected (such as an interface method).
+func (p *goPackage) method(constructor *ast.FuncDecl, t types.Type, name string) (*ast.FuncDecl, bool, error) {
+	sel := types.NewMethodSet(t).Lookup(p.pkg, name)
+	if sel != nil {
+		return p.decls[sel.Obj().Pos()], true, nil // nil for interface and promoted foreign methods
+	}
+	if _, isPtr := t.(*types.Pointer); !isPtr && types.NewMethodSet(types.NewPointer(t)).Lookup(p.pkg, name) != nil {
+		tn := types.TypeString(t, p.qualifier)
+		return nil, false, ErrInvalidSignature{p.position(constructor.Type.Results.Pos()),
+			fmt.Sprintf("New returns %v, but its %v method has a pointer receiver; return *%v instead", tn, name, tn)}
+	}
+	return nil, false, nil
+}
+
+// instanceHandler verifies that New is a constructor of an instance with a
+// Handle method, and returns the method's declaration.  Nil is returned
+// without error if the instance's type can not be inspected.
+func (p *goPackage) instanceHandler(constructor *ast.FuncDecl) (*ast.FuncDecl, error) {
+	t, err := p.instance(constructor)
+	if t == nil || err != nil {
+		return nil, err
+	}
+	handle, ok, err := p.method(constructor, t, "Handle")
+	if err == nil && !ok {
+		err = ErrInvalidSignature{p.position(constructor.Type.Results.Pos()),
+			fmt.Sprintf("New returns %v, which has no Handle method", types.TypeString(t, p.qualifier))}
+	}
+	return handle, err
+}
+
+// goLifecycle are the optional methods of an instance which are invoked by
+// the middleware when it is started and stopped, and when its readiness and
+// liveness are checked at the function's health endpoints.
+var goLifecycle = []struct {
+	name    string
+	params  []string
+	results []string
+}{
+	{"Start", []string{goContext, "map[string]string"}, []string{goError}},
+	{"Stop", []string{goContext}, []string{goError}},
+	{"Ready", []string{goContext}, []string{"bool", goError}},
+	{"Alive", []string{goContext}, []string{"bool", goError}},
+}
+
+// verifyLifecycle verifies any lifecycle methods implemented by the instance
+// returned by New.  A method of the same name but another signature would
+// otherwise silently not be invoked.
+func (p *goPackage) verifyLifecycle(constructor *ast.FuncDecl) error {
+	t, err := p.instance(constructor)
+	if t == nil || err != nil {
+		return err
+	}
+	for _, m := range goLifecycle {
+		fd, _, err := p.method(constructor, t, m.name)
+		if err != nil {
+			return err
+		}
+		if fd == nil {
+			continue
+		}
+		results := strings.Join(goShortTypes(m.results), ", ")
+		if len(m.results) > 1 {
+			results = "(" + results + ")"
+		}
+		expected := fmt.Sprintf("%v must be of the form %v(%v) %v", m.name, m.name,
+			strings.Join(goShortTypes(m.params), ", "), results)
+		if err := p.verifyTypes(fd, "parameter", fields(fd.Type.Params), m.params, expected); err != nil {
+			return err
+		}
+		if err := p.verifyTypes(fd, "result", fields(fd.Type.Results), m.results, expected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyTypes verifies that the parameters or results ff of fd are exactly
+// of the given types.
+func (p *goPackage) verifyTypes(fd *ast.FuncDecl, kind string, ff []*ast.Field, tt []string, expected string) error {
+	if len(ff) != len(tt) {
+		return ErrInvalidSignature{p.position(fd.Name.Pos()),
+			fmt.Sprintf("%v has %v %vs; %v", fd.Name.Name, len(ff), kind, expected)}
+	}
+	for i, f := range ff {
+		if !p.is(f.Type, tt[i]) {
+			return ErrInvalidSignature{p.position(f.Type.Pos()),
+				fmt.Sprintf("%v %v %v has type %v; %v", fd.Name.Name, kind, i+1, types.ExprString(f.Type), expected)}
+		}
+	}
+	return nil
+}
+
+// goShortTypes returns the types as they would typically be written.
+func goShortTypes(tt []string) []string {
+	short := make([]string, len(tt))
+	for i, t := range tt {
+		short[i] = path.Base(t)
+	}
+	return short
+}
+
+// declared returns true if t is (a pointer to) a type declared in the
+// function's package.
+func (p *goPackage) declared(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() == p.pkg
+}
+
+// qualifier omits the function's own package name from type names.
+func (p *goPackage) qualifier(pkg *types.Package) string {
+	if pkg == p.pkg {
+		return ""
+	}
+	return pkg.Name()
+}
+
+// stubImporter satisfies the imports of a function's package with empty
+// packages, such that the package can be type-checked without its
+// dependencies.
+type stubImporter struct{}
+
+// goMajorVersion matches the major version element of a module path.
+var goMajorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// goPackageNames are the names of packages which differ from their path.
+var goPackageNames = map[string]string{
+	"github.com/cloudevents/sdk-go/v2": "cloudevents",
+}
+
+func (stubImporter) Import(importPath string) (*types.Package, error) {
+	name, ok := goPackageNames[importPath]
+	if !ok {
+		elems := strings.Split(importPath, "/")
+		name = elems[len(elems)-1]
+		if len(elems) > 1 && goMajorVersion.MatchString(name) {
+			name = elems[len(elems)-2]
+		}
+		name = strings.TrimSuffix(strings.TrimPrefix(path.Base(name), "go-"), "-go")
+		name = strings.NewReplacer("-", "_", ".", "_").Replace(name)
+	}
+	pkg := types.NewPackage(importPath, name)
+	pkg.MarkComplete()
+	return pkg, nil
+}
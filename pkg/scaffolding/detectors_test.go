@@ -7,6 +7,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	. "knative.dev/func/pkg/testing"
@@ -16,14 +17,12 @@ import (
 // identify the signature to expect of a function's source.
 func TestDetector_Go(t *testing.T) {
 	// NOTE:
-	// The detector need only check the function's name; not the entire signature
-	// because invocation hint (http vs cloudevent) is available in the
-	// function's metadata, and the detector needs to be as simple as possible
-	// while fulfilling its purpose of detecting which signature is _expected_
-	// of the source code, not whether or not it actually does:  that's the job
-	// of the compiler later.  This detection is used to determine which
-	// scaffolding code needs to be written to get the user to a proper
-	// complile attempt.
+	// Detection is by the function's name (Handle or New), with the invocation
+	// hint (http vs cloudevent) available in the function's metadata.  The
+	// detected signature is then verified against the parameter and result
+	// types of the handler, such that an incorrect handler is reported with
+	// its position rather than failing the later compilation of the
+	// scaffolding.  See TestDetector_GoVerify.
 	tests := []struct {
 		Name string    // Name of the test
 		Sig  Signature // Signature Expected
@@ -38,7 +37,13 @@ func TestDetector_Go(t *testing.T) {
 			Src: `
 package f
 
-func New() { }
+import "net/http"
+
+type F struct{}
+
+func New() *F { return &F{} }
+
+func (f *F) Handle(w http.ResponseWriter, r *http.Request) {}
 	`},
 		{
 			Name: "Static HTTP",
@@ -47,7 +52,9 @@ func New() { }
 			Src: `
 package f
 
-func Handle() { }
+import "net/http"
+
+func Handle(w http.ResponseWriter, r *http.Request) { }
 	`},
 		{
 			Name: "Instanced Cloudevents",
@@ -56,7 +63,18 @@ func Handle() { }
 			Inv:  "cloudevent", // Invoke is the only place Cloudevents is singular
 			Src: `
 package f
-func New() { }
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+type F struct{}
+
+func New() *F { return &F{} }
+
+func (f *F) Handle(ctx context.Context, e event.Event) (*event.Event, error) { return nil, nil }
 	`},
 		{
 			Name: "Static Cloudevents",
@@ -90,6 +108,9 @@ package f
 			Err:  nil,
 			Src: `
 package f
+
+import "net/http"
+
 /*
 This comment block would cause the function to be detected as instanced
 without the use of the language parser.
@@ -97,7 +118,7 @@ without the use of the language parser.
 func New()
 
 */
-func Handle() { }
+func Handle(w http.ResponseWriter, r *http.Request) { }
 	`},
 		{
 			Name: "Instanced with Handler",
@@ -106,11 +127,13 @@ func Handle() { }
 			Src: `
 package f
 
+import "net/http"
+
 type F struct{}
 
 func New() *F { return &F{} }
 
-func (f *MyFunction) Handle() {}
+func (f *F) Handle(w http.ResponseWriter, r *http.Request) {}
 	`},
 	}
 
@@ -143,3 +166,315 @@ func (f *MyFunction) Handle() {}
 		})
 	}
 }
+
+// TestDetector_GoVerify ensures that incorrect Go handlers are reported
+// with the position and a description of the problem.
+func TestDetector_GoVerify(t *testing.T) {
+	tests := []struct {
+		Name string // Name of the test
+		Inv  string // invocation hint; "http" (default) or "cloudevent"
+		Pos  string // Position of the error expected, or "" for none
+		Msg  string // Substring of the error expected
+		Src  string // Source code to check
+	}{
+		{
+			Name: "HTTP wrong parameter type",
+			Pos:  "function.go:5:38",
+			Msg:  "Handle parameter 2 has type http.Request",
+			Src: `package f
+
+import "net/http"
+
+func Handle(w http.ResponseWriter, r http.Request) {}
+`},
+		{
+			Name: "HTTP with context",
+			Pos:  "function.go:8:6",
+			Msg:  "Handle has 3 parameters",
+			Src: `package f
+
+import (
+	"context"
+	"net/http"
+)
+
+func Handle(ctx context.Context, w http.ResponseWriter, r *http.Request) {}
+`},
+		{
+			Name: "HTTP aliased import",
+			Src: `package f
+
+import nethttp "net/http"
+
+func Handle(res nethttp.ResponseWriter, req *nethttp.Request) {}
+`},
+		{
+			Name: "Cloudevents wrong parameter type",
+			Inv:  "cloudevent",
+			Pos:  "function.go:10:36",
+			Msg:  "Handle parameter 2 has unexpected type *event.Event",
+			Src: `package f
+
+import (
+	"context"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// Handle an event.
+func Handle(ctx context.Context, e *event.Event) error { return nil }
+`},
+		{
+			Name: "Cloudevents wrong result type",
+			Inv:  "cloudevent",
+			Pos:  "function.go:5:26",
+			Msg:  "Handle result 1 has unexpected type ce.Event",
+			Src: `package f
+
+import ce "github.com/cloudevents/sdk-go/v2"
+
+func Handle(e ce.Event) (ce.Event, error) { return e, nil }
+`},
+		{
+			Name: "Cloudevents protocol result",
+			Inv:  "cloudevent",
+			Src: `package f
+
+import cloudevents "github.com/cloudevents/sdk-go/v2"
+
+func Handle(e cloudevents.Event) cloudevents.Result { return nil }
+`},
+		{
+			Name: "Instanced pointer receiver",
+			Pos:  "function.go:7:12",
+			Msg:  "has a pointer receiver",
+			Src: `package f
+
+import "net/http"
+
+type F struct{}
+
+func New() F { return F{} }
+
+func (f *F) Handle(w http.ResponseWriter, r *http.Request) {}
+`},
+		{
+			Name: "Instanced missing Handle",
+			Pos:  "function.go:5:12",
+			Msg:  "New returns *F, which has no Handle method",
+			Src: `package f
+
+type F struct{}
+
+func New() *F { return &F{} }
+`},
+		{
+			Name: "Instanced wrong Handle",
+			Inv:  "cloudevent",
+			Pos:  "function.go:9:22",
+			Msg:  "Handle parameter 1 has unexpected type string",
+			Src: `package f
+
+type F struct{}
+
+func New() *F { return &F{} }
+
+// Handle is declared in another file in practice, but is found by
+// type-checking the package as a whole.
+func (f *F) Handle(s string) {}
+`},
+		{
+			Name: "Instanced lifecycle",
+			Src: `package f
+
+import (
+	"context"
+	"net/http"
+)
+
+type F struct{}
+
+func New() *F { return &F{} }
+
+func (f *F) Handle(w http.ResponseWriter, r *http.Request)         {}
+func (f *F) Start(ctx context.Context, cfg map[string]string) error { return nil }
+func (f *F) Stop(ctx context.Context) error                         { return nil }
+func (f *F) Ready(ctx context.Context) (bool, error)                { return true, nil }
+func (f *F) Alive(ctx context.Context) (bool, error)                { return true, nil }
+`},
+		{
+			Name: "Instanced lifecycle wrong parameter",
+			Pos:  "function.go:13:44",
+			Msg:  "Start parameter 2 has type map[string]any; Start must be of the form Start(context.Context, map[string]string) error",
+			Src: `package f
+
+import (
+	"context"
+	"net/http"
+)
+
+type F struct{}
+
+func New() *F { return &F{} }
+
+func (f *F) Handle(w http.ResponseWriter, r *http.Request) {}
+func (f *F) Start(ctx context.Context, cfg map[string]any) error { return nil }
+`},
+		{
+			Name: "Instanced lifecycle missing result",
+			Inv:  "cloudevent",
+			Pos:  "function.go:9:13",
+			Msg:  "Ready has 1 results",
+			Src: `package f
+
+import "context"
+
+type F struct{}
+
+func New() *F { return &F{} }
+
+func (f *F) Ready(ctx context.Context) bool { return true }
+func (f *F) Handle()                        {}
+`},
+		{
+			Name: "Syntax error",
+			Pos:  "function.go:3:1",
+			Src: `package f
+
+fun Handle() {}
+`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.WriteFile(filepath.Join(root, "function.go"), []byte(test.Src), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			_, err := detectSignature(root, "go", test.Inv)
+			if test.Pos == "" {
+				if err != nil {
+					t.Fatalf("unexpected error. %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error not received")
+			}
+			if !strings.HasPrefix(err.Error(), test.Pos+":") || !strings.Contains(err.Error(), test.Msg) {
+				t.Fatalf("expected error at %v containing %q, got %q", test.Pos, test.Msg, err)
+			}
+		})
+	}
+}
+
+// TestDetector_Scripted ensures that the node, typescript and rust detectors
+// correctly identify the signature to expect of a function's source.
+func TestDetector_Scripted(t *testing.T) {
+	tests := []struct {
+		Name    string    // Name of the test
+		Runtime string    // Runtime of the source
+		File    string    // Path of the source file, relative to the root
+		Sig     Signature // Signature Expected
+		Err     bool      // Error Expected
+		Inv     string    // invocation hint; "http" (default) or "cloudevent"
+		Src     string    // Source code to check
+	}{
+		{
+			Name:    "Node Static HTTP",
+			Runtime: "node",
+			File:    "index.js",
+			Sig:     StaticHTTP,
+			Src:     "const handle = async (context) => {};\nmodule.exports = { handle };\n",
+		},
+		{
+			Name:    "Node Instanced Cloudevents",
+			Runtime: "node",
+			File:    "index.js",
+			Sig:     InstancedCloudevents,
+			Inv:     "cloudevent",
+			Src:     "exports.new = () => ({ handle: async (context, event) => event });\n",
+		},
+		{
+			Name:    "Node Comments Ignored",
+			Runtime: "node",
+			File:    "index.js",
+			Sig:     StaticHTTP,
+			Src:     "// exports.new = () => {}\n/* module.exports = { new: f } */\nexports.handle = async () => {};\n",
+		},
+		{
+			Name:    "Node No Signatures Found - error",
+			Runtime: "node",
+			File:    "index.js",
+			Err:     true,
+			Src:     "module.exports = {};\n",
+		},
+		{
+			Name:    "TypeScript Static HTTP",
+			Runtime: "typescript",
+			File:    "src/index.ts",
+			Sig:     StaticHTTP,
+			Src:     "export const handle: HTTPFunction = async (context) => {};\n",
+		},
+		{
+			Name:    "TypeScript Instanced HTTP",
+			Runtime: "typescript",
+			File:    "src/index.ts",
+			Sig:     InstancedHTTP,
+			Src:     "function create() { return new F(); }\nexport { create as new };\n",
+		},
+		{
+			Name:    "TypeScript Static and Instanced - error",
+			Runtime: "typescript",
+			File:    "src/index.ts",
+			Err:     true,
+			Src:     "export function handle() {}\nexport { create as new };\n",
+		},
+		{
+			Name:    "Rust Static HTTP",
+			Runtime: "rust",
+			File:    "src/handler.rs",
+			Sig:     StaticHTTP,
+			Src:     "pub async fn index(req: HttpRequest) -> HttpResponse {}\n",
+		},
+		{
+			Name:    "Rust Instanced Cloudevents",
+			Runtime: "rust",
+			File:    "src/lib.rs",
+			Sig:     InstancedCloudevents,
+			Inv:     "cloudevent",
+			Src:     "pub struct F;\n\npub fn new() -> F { F }\n",
+		},
+		{
+			Name:    "Rust No Signatures Found - error",
+			Runtime: "rust",
+			File:    "src/lib.rs",
+			Err:     true,
+			Src:     "// pub fn new() -> F { F }\nfn index() {}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			root := t.TempDir()
+			path := filepath.Join(root, filepath.FromSlash(test.File))
+			if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(test.Src), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := detectSignature(root, test.Runtime, test.Inv)
+			if err != nil && !test.Err {
+				t.Fatalf("unexpected error. %v", err)
+			}
+			if err == nil && test.Err {
+				t.Fatal("expected error not received")
+			}
+			if s != test.Sig {
+				t.Fatalf("Expected signature '%v', got '%v'", test.Sig, s)
+			}
+		})
+	}
+}
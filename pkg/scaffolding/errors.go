@@ -1,6 +1,9 @@
 package scaffolding
 
-import "fmt"
+import (
+	"fmt"
+	"go/token"
+)
 
 type ScaffoldingError struct {
 	Msg string
@@ -37,3 +40,17 @@ type ErrRuntimeNotRecognized struct {
 func (e ErrRuntimeNotRecognized) Error() string {
 	return fmt.Sprintf("signature not found.  The runtime %v is not recognized", e.Runtime)
 }
+
+// ErrInvalidSignature indicates a function's source code does not correctly
+// implement a method signature, located at the offending declaration.
+type ErrInvalidSignature struct {
+	Pos token.Position // file (relative to the function's root), line and column
+	Msg string
+}
+
+func (e ErrInvalidSignature) Error() string {
+	if !e.Pos.IsValid() {
+		return e.Msg
+	}
+	return fmt.Sprintf("%v: %v", e.Pos, e.Msg)
+}
@@ -0,0 +1,92 @@
+package scaffolding
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrRuntimeNotDetected indicates the language runtime of existing source
+// code could not be inferred from its project files.
+var ErrRuntimeNotDetected = errors.New("language runtime not detected")
+
+// projectFiles are the files which indicate a project's runtime, in order of
+// precedence.  For example a TypeScript project also has a package.json.
+var projectFiles = []struct {
+	file    string
+	runtime string
+}{
+	{"go.mod", "go"},
+	{"tsconfig.json", "typescript"},
+	{"package.json", "node"},
+	{"pyproject.toml", "python"},
+	{"requirements.txt", "python"},
+	{"setup.py", "python"},
+	{"Cargo.toml", "rust"},
+}
+
+// DetectRuntime infers the language runtime of the source code in dir from
+// its project files (go.mod, package.json, pyproject.toml etc).
+func DetectRuntime(dir string) (string, error) {
+	for _, p := range projectFiles {
+		if _, err := os.Stat(filepath.Join(dir, p.file)); err == nil {
+			return p.runtime, nil
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	return "", ErrRuntimeNotDetected
+}
+
+// DetectInvoke infers the invocation hint of the source code in dir:
+// "cloudevent" if it uses a CloudEvents SDK, otherwise "http".
+func DetectInvoke(dir, runtime string) (string, error) {
+	var (
+		files []string
+		sdk   string
+	)
+	switch runtime {
+	case "go":
+		files, sdk = globFiles(dir, "*.go"), "github.com/cloudevents/sdk-go"
+	case "python":
+		files, sdk = globFiles(dir, "*.py", "pyproject.toml", "requirements.txt"), "cloudevents"
+	case "node", "typescript":
+		files, sdk = []string{filepath.Join(dir, "package.json")}, "cloudevents"
+	case "rust":
+		files, sdk = []string{filepath.Join(dir, "Cargo.toml")}, "cloudevents-sdk"
+	}
+	for _, file := range files {
+		bb, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		if bytes.Contains(bb, []byte(sdk)) {
+			return "cloudevent", nil
+		}
+	}
+	return "http", nil
+}
+
+// globFiles returns the files in dir matching any of the patterns.
+func globFiles(dir string, patterns ...string) (files []string) {
+	for _, p := range patterns {
+		matches, _ := filepath.Glob(filepath.Join(dir, p))
+		for _, m := range matches {
+			if !strings.HasSuffix(m, "_test.go") {
+				files = append(files, m)
+			}
+		}
+	}
+	return
+}
+
+// Detect the method signature implemented by the source code in src given
+// the runtime and invocation hint.  Returned is an ErrDetectorNotImplemented
+// for runtimes whose signatures can not yet be detected.
+func Detect(src, runtime, invoke string) (Signature, error) {
+	return detectSignature(src, runtime, invoke)
+}
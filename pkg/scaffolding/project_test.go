@@ -0,0 +1,76 @@
+//go:build !integration
+// +build !integration
+
+package scaffolding
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDetectRuntime ensures the runtime of a project is inferred from its
+// project files.
+func TestDetectRuntime(t *testing.T) {
+	tests := []struct {
+		Files   []string
+		Runtime string
+	}{
+		{[]string{"go.mod", "handle.go"}, "go"},
+		{[]string{"package.json", "index.js"}, "node"},
+		{[]string{"package.json", "tsconfig.json"}, "typescript"},
+		{[]string{"pyproject.toml"}, "python"},
+		{[]string{"requirements.txt"}, "python"},
+		{[]string{"Cargo.toml"}, "rust"},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		for _, f := range test.Files {
+			if err := os.WriteFile(filepath.Join(dir, f), []byte{}, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		runtime, err := DetectRuntime(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if runtime != test.Runtime {
+			t.Errorf("expected runtime %v for %v, got %v", test.Runtime, test.Files, runtime)
+		}
+	}
+
+	if _, err := DetectRuntime(t.TempDir()); !errors.Is(err, ErrRuntimeNotDetected) {
+		t.Fatalf("expected ErrRuntimeNotDetected, got %v", err)
+	}
+}
+
+// TestDetectInvoke ensures projects which use a CloudEvents SDK are detected
+// as such.
+func TestDetectInvoke(t *testing.T) {
+	tests := []struct {
+		Runtime string
+		File    string
+		Content string
+		Invoke  string
+	}{
+		{"go", "handle.go", "package f\nimport \"net/http\"\n", "http"},
+		{"go", "handle.go", "package f\nimport \"github.com/cloudevents/sdk-go/v2/event\"\n", "cloudevent"},
+		{"python", "func.py", "from cloudevents.http import CloudEvent\n", "cloudevent"},
+		{"node", "package.json", `{"dependencies": {"cloudevents": "^8.0.0"}}`, "cloudevent"},
+		{"node", "package.json", `{"dependencies": {}}`, "http"},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, test.File), []byte(test.Content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		invoke, err := DetectInvoke(dir, test.Runtime)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if invoke != test.Invoke {
+			t.Errorf("expected %v for %v %q, got %v", test.Invoke, test.Runtime, test.Content, invoke)
+		}
+	}
+}
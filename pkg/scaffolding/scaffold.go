@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
+	"text/template"
 
 	"knative.dev/func/pkg/filesystem"
 )
@@ -30,7 +32,12 @@ import (
 //	invoke:  the optional invocatin hint (default "http")
 //	fs:      filesytem which contains scaffolding at '[runtime]/scaffolding'
 //	         (exclusive with 'repo')
-func Write(out, src, runtime, invoke string, fs filesystem.Filesystem) (err error) {
+//	options: optional settings such as WithHealthEndpoints
+func Write(out, src, runtime, invoke string, fs filesystem.Filesystem, options ...Option) (err error) {
+	oo := writeOptions{}
+	for _, o := range options {
+		o(&oo)
+	}
 
 	// detect the signature of the source code in the given location, presuming
 	// a runtime and invocation hint (default "http")
@@ -84,6 +91,11 @@ func Write(out, src, runtime, invoke string, fs filesystem.Filesystem) (err erro
 		}
 	}
 
+	// Serve the function's health checks at its configured endpoints
+	if err = writeHealthEndpoints(out, oo.readiness, oo.liveness); err != nil {
+		return ScaffoldingError{"cannot write health endpoints", err}
+	}
+
 	// Copy the certs from the filesystem to the build directory
 	if _, err := fs.Stat("certs"); err != nil {
 		return ScaffoldingError{"certs directory not found in filesystem", err}
@@ -106,6 +118,76 @@ func Write(out, src, runtime, invoke string, fs filesystem.Filesystem) (err erro
 	return
 }
 
+// Option configures the scaffolding written.
+type Option func(*writeOptions)
+
+type writeOptions struct {
+	readiness, liveness string
+}
+
+// WithHealthEndpoints serves the function's readiness and liveness checks at
+// the given paths in addition to the defaults (/health/readiness and
+// /health/liveness).  Empty paths retain the defaults.  Applies to
+// scaffolding which declares its health endpoints (Go and Python).
+func WithHealthEndpoints(readiness, liveness string) Option {
+	return func(o *writeOptions) {
+		o.readiness = readiness
+		o.liveness = liveness
+	}
+}
+
+// healthEndpointFiles are the files of scaffolding which declare the paths
+// of its health endpoints.  They are rendered as Go text/template with
+// healthEndpointData.
+var healthEndpointFiles = []string{
+	"health.go",                           // Go
+	filepath.Join("service", "health.py"), // Python
+}
+
+// healthEndpointData is the data with which healthEndpointFiles are rendered.
+type healthEndpointData struct {
+	Readiness string
+	Liveness  string
+}
+
+// writeHealthEndpoints renders the paths of the health endpoints declared
+// by the scaffolding in out, if any, defaulting to those of the middleware.
+func writeHealthEndpoints(out, readiness, liveness string) error {
+	for _, p := range []string{readiness, liveness} {
+		if p != "" && (!strings.HasPrefix(p, "/") || p == "/" || strings.ContainsAny(p, "\"\\\n")) {
+			return fmt.Errorf("invalid health endpoint %q. Must be an absolute path other than '/'", p)
+		}
+	}
+	data := healthEndpointData{Readiness: readiness, Liveness: liveness}
+	if data.Readiness == "" {
+		data.Readiness = "/health/readiness"
+	}
+	if data.Liveness == "" {
+		data.Liveness = "/health/liveness"
+	}
+	for _, file := range healthEndpointFiles {
+		path := filepath.Join(out, file)
+		text, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		t, err := template.New(file).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return err
+		}
+		b := bytes.Buffer{}
+		if err = t.Execute(&b, data); err != nil {
+			return err
+		}
+		if err = os.WriteFile(path, b.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // detectSignature returns the Signature of the source code at the given
 // location assuming a provided runtime and invocation hint.
 func detectSignature(src, runtime, invoke string) (s Signature, err error) {
@@ -123,7 +205,12 @@ func detectSignature(src, runtime, invoke string) (s Signature, err error) {
 		return s, fmt.Errorf("function may not implement both the static and instanced method signatures simultaneously")
 	} else if !static && !instanced {
 		return s, fmt.Errorf("function does not implement any known method signatures or does not compile")
-	} else {
-		return toSignature(instanced, invoke), nil
 	}
+	s = toSignature(instanced, invoke)
+	if v, ok := d.(verifier); ok {
+		if err = v.Verify(src, s); err != nil {
+			return UnknownSignature, err
+		}
+	}
+	return
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"knative.dev/func/pkg/filesystem"
@@ -24,9 +25,9 @@ func TestWrite_RuntimeErrors(t *testing.T) {
 	}{
 		{"go", nil},
 		{"python", nil},
-		{"rust", &ErrDetectorNotImplemented{}},
-		{"node", &ErrDetectorNotImplemented{}},
-		{"typescript", &ErrDetectorNotImplemented{}},
+		{"rust", nil},
+		{"node", nil},
+		{"typescript", nil},
 		{"quarkus", &ErrDetectorNotImplemented{}},
 		{"java", &ErrDetectorNotImplemented{}},
 		{"other", &ErrRuntimeNotRecognized{}},
@@ -73,9 +74,13 @@ func TestWrite(t *testing.T) {
 	impl := `
 package f
 
+import "net/http"
+
 type F struct{}
 
 func New() *F { return nil }
+
+func (f *F) Handle(w http.ResponseWriter, r *http.Request) {}
 `
 	err = os.WriteFile(filepath.Join(root, "f.go"), []byte(impl), os.ModePerm)
 	if err != nil {
@@ -121,6 +126,66 @@ func New() *F { return nil }
 
 }
 
+// TestWrite_HealthEndpoints ensures that the health endpoints declared by
+// the Go and Python scaffolding are set to those provided.
+func TestWrite_HealthEndpoints(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := filesystem.NewOsFilesystem(filepath.Join(cwd, "..", "..", "templates"))
+
+	tests := []struct {
+		runtime string
+		file    string
+		src     map[string]string
+	}{
+		{"go", "health.go", map[string]string{
+			"go.mod": "module foo",
+			"f.go":   "package f\n\nimport \"net/http\"\n\nfunc Handle(w http.ResponseWriter, r *http.Request) {}\n",
+		}},
+		{"python", filepath.Join("service", "health.py"), map[string]string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.runtime, func(t *testing.T) {
+			root := t.TempDir()
+			for name, src := range test.src {
+				if err := os.WriteFile(filepath.Join(root, name), []byte(src), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			out := filepath.Join(root, "out")
+			if err := Write(out, root, test.runtime, "", fs, WithHealthEndpoints("/ready", "/live$1")); err != nil {
+				t.Fatal(err)
+			}
+			bb, err := os.ReadFile(filepath.Join(out, test.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(bb), `"/ready"`) || !strings.Contains(string(bb), `"/live$1"`) {
+				t.Fatalf("expected health endpoints to be written, got:\n%s", bb)
+			}
+
+			// The defaults are rendered when none are provided
+			defaults := filepath.Join(root, "defaults")
+			if err := Write(defaults, root, test.runtime, "", fs); err != nil {
+				t.Fatal(err)
+			}
+			if bb, err = os.ReadFile(filepath.Join(defaults, test.file)); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(bb), `"/health/readiness"`) || !strings.Contains(string(bb), `"/health/liveness"`) {
+				t.Fatalf("expected default health endpoints to be written, got:\n%s", bb)
+			}
+
+			// Invalid paths are rejected
+			if err := Write(out, root, test.runtime, "", fs, WithHealthEndpoints("ready", "")); err == nil {
+				t.Fatal("expected a relative health endpoint to be rejected")
+			}
+		})
+	}
+}
+
 // TestWrite_ScaffoldingNotFound ensures that a typed error is returned
 // when scaffolding is not found.
 func TestWrite_ScaffoldingNotFound(t *testing.T) {
@@ -136,9 +201,13 @@ func TestWrite_ScaffoldingNotFound(t *testing.T) {
 	impl := `
 package f
 
+import "net/http"
+
 type F struct{}
 
 func New() *F { return nil }
+
+func (f *F) Handle(w http.ResponseWriter, r *http.Request) {}
 `
 	err = os.WriteFile(filepath.Join(root, "f.go"), []byte(impl), os.ModePerm)
 	if err != nil {
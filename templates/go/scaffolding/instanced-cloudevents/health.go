@@ -0,0 +1,25 @@
+package main
+
+import "net/http"
+
+// Paths at which the function's readiness and liveness are served, in
+// addition to the middleware's defaults of /health/readiness and
+// /health/liveness.  These are rendered from the function's configured
+// health endpoints when scaffolded.
+const (
+	readinessPath = "{{.Readiness}}"
+	livenessPath  = "{{.Liveness}}"
+)
+
+// withHealthEndpoints serves the service's readiness and liveness checks,
+// which invoke the function's Ready and Alive methods if implemented, at the
+// configured paths, and all other requests using the given handler.
+func withHealthEndpoints(h http.Handler, ready, alive http.HandlerFunc) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(readinessPath, ready)
+	if livenessPath != readinessPath {
+		mux.HandleFunc(livenessPath, alive)
+	}
+	mux.Handle("/", h)
+	return mux
+}
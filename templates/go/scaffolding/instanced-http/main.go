@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -10,7 +11,11 @@ import (
 )
 
 func main() {
-	if err := http.Start(f.New()); err != nil {
+	// The instance's optional Start, Stop, Ready and Alive methods are
+	// invoked by the service.
+	svc := http.New(f.New())
+	svc.Handler = withHealthEndpoints(svc.Handler, svc.Ready, svc.Alive)
+	if err := svc.Start(context.Background()); err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -10,7 +11,9 @@ import (
 )
 
 func main() {
-	if err := ce.Start(ce.DefaultHandler{Handler: f.Handle}); err != nil {
+	svc := ce.New(ce.DefaultHandler{Handler: f.Handle})
+	svc.Handler = withHealthEndpoints(svc.Handler, svc.Ready, svc.Alive)
+	if err := svc.Start(context.Background()); err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
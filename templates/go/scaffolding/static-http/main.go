@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -17,7 +18,9 @@ func (f fx) Handle(w http.ResponseWriter, r *http.Request) {
 }
 
 func main() {
-	if err := fhttp.Start(fx(f.Handle)); err != nil {
+	svc := fhttp.New(fx(f.Handle))
+	svc.Handler = withHealthEndpoints(svc.Handler, svc.Ready, svc.Alive)
+	if err := svc.Start(context.Background()); err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
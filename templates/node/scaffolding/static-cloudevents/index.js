@@ -0,0 +1,7 @@
+const { start } = require('faas-js-runtime');
+const func = require('function');
+
+start(func).catch(err => {
+  console.error(err);
+  process.exit(1);
+});
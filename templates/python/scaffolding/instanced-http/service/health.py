@@ -0,0 +1,49 @@
+"""
+Serves a Function instance's readiness and liveness at the paths configured
+as the Function's health endpoints, in addition to the middleware's defaults
+of /health/readiness and /health/liveness.  The paths are rendered from the
+Function's configuration when scaffolded.
+"""
+import inspect
+
+READINESS_PATH = "{{.Readiness}}"
+LIVENESS_PATH = "{{.Liveness}}"
+
+
+class HealthEndpoints:
+    """ HealthEndpoints wraps a Function instance, delegating to it all
+    requests other than those to the configured health endpoints, which
+    invoke the instance's optional 'ready' and 'alive' methods.  The
+    instance's other optional lifecycle methods ('start' and 'stop') are
+    likewise delegated, and invoked by the middleware.
+    """
+
+    def __init__(self, f):
+        self._f = f
+
+    def __getattr__(self, name):
+        return getattr(self._f, name)
+
+    async def handle(self, scope, receive, send):
+        check = {
+            READINESS_PATH: "ready",
+            LIVENESS_PATH: "alive",
+        }.get(scope.get("path"))
+        if scope.get("type") != "http" or check is None:
+            return await self._f.handle(scope, receive, send)
+
+        ok, message = True, "OK"
+        if hasattr(self._f, check):
+            result = getattr(self._f, check)()
+            if inspect.isawaitable(result):
+                result = await result
+            ok, message = result if isinstance(result, tuple) else (result, "")
+        await send({
+            "type": "http.response.start",
+            "status": 200 if ok else 503,
+            "headers": [[b"content-type", b"text/plain"]],
+        })
+        await send({
+            "type": "http.response.body",
+            "body": str(message).encode(),
+        })
@@ -7,10 +7,18 @@ final container.
 import logging
 from func_python.http import serve
 
+from health import HealthEndpoints
+
 logging.basicConfig(level=logging.INFO)
 
 try:
-    from function import new as handler  # type: ignore[import]
+    from function import new as _new  # type: ignore[import]
+
+    def new():
+        """ new instance of the Function, served at its health endpoints """
+        return HealthEndpoints(_new())
+
+    handler = new
 except ImportError:
     try:
         from function import handle as handler  # type: ignore[import]
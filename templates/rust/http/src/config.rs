@@ -6,7 +6,7 @@ use log::info;
 ///
 /// This function should contain all custom configuration for your function application.
 ///
-/// ```rust
+/// ```rust,ignore
 /// fn configure(cfg: &mut ServiceConfig) {
 ///     let db_driver = my_db();
 ///     cfg.data(db_driver.clone());
@@ -15,7 +15,7 @@ use log::info;
 ///
 /// Then you can use configured resources in your function.
 ///
-/// ```rust
+/// ```rust,ignore
 /// pub async fn index(
 ///     req: HttpRequest,
 ///     driver: Data<DbDriver>,
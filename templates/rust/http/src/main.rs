@@ -1,14 +1,12 @@
 use actix_web::{web, App, HttpResponse, HttpServer};
 use env_logger::{Builder, Env};
+use function::{config, handler};
 use std::{
     env,
     io::{Error, ErrorKind, Result},
     num::ParseIntError,
 };
 
-mod config;
-mod handler;
-
 #[actix_web::main]
 async fn main() -> Result<()> {
     Builder::from_env(Env::default().default_filter_or("info")).init();
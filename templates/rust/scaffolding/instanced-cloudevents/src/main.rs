@@ -0,0 +1,46 @@
+use actix_web::{web, App, HttpResponse, HttpServer};
+use cloudevents::Event;
+use env_logger::{Builder, Env};
+use std::{
+    env,
+    io::{Error, ErrorKind, Result},
+    num::ParseIntError,
+};
+
+#[actix_web::main]
+async fn main() -> Result<()> {
+    Builder::from_env(Env::default().default_filter_or("info,actix_web=warn")).init();
+
+    let port = match env::var("PORT") {
+        Ok(v) => v
+            .parse()
+            .map_err(|e: ParseIntError| Error::new(ErrorKind::Other, e.to_string()))?,
+        Err(_) => 8080,
+    };
+
+    // A single instance of the function, shared by all requests, whose
+    // 'handle' method is invoked for each event.
+    let instance = web::Data::new(function::new());
+
+    HttpServer::new(move || {
+        let instance = instance.clone();
+        App::new()
+            .wrap(actix_web::middleware::Logger::default())
+            .route(
+                "/",
+                web::post().to(move |event: Event| {
+                    let instance = instance.clone();
+                    async move { instance.handle(event).await }
+                }),
+            )
+            .route("/", web::get().to(HttpResponse::MethodNotAllowed))
+            .route(
+                "/health/{_:(readiness|liveness)}",
+                web::get().to(HttpResponse::Ok),
+            )
+    })
+    .bind(("0.0.0.0", port))?
+    .workers(1)
+    .run()
+    .await
+}
@@ -0,0 +1,41 @@
+use actix_web::{web, App, HttpRequest, HttpResponse, HttpServer};
+use env_logger::{Builder, Env};
+use std::{
+    env,
+    io::{Error, ErrorKind, Result},
+    num::ParseIntError,
+};
+
+#[actix_web::main]
+async fn main() -> Result<()> {
+    Builder::from_env(Env::default().default_filter_or("info")).init();
+
+    let port = match env::var("PORT") {
+        Ok(v) => v
+            .parse()
+            .map_err(|e: ParseIntError| Error::new(ErrorKind::Other, e.to_string()))?,
+        Err(_) => 8080,
+    };
+
+    // A single instance of the function, shared by all requests, whose
+    // 'handle' method is invoked for each request.
+    let instance = web::Data::new(function::new());
+
+    HttpServer::new(move || {
+        let instance = instance.clone();
+        App::new()
+            .wrap(actix_web::middleware::Logger::default())
+            .route(
+                "/health/{_:(readiness|liveness)}",
+                web::get().to(HttpResponse::Ok),
+            )
+            .default_service(web::to(move |req: HttpRequest, body: web::Bytes| {
+                let instance = instance.clone();
+                async move { instance.handle(req, body).await }
+            }))
+    })
+    .bind(("0.0.0.0", port))?
+    .workers(1)
+    .run()
+    .await
+}
@@ -0,0 +1,37 @@
+use actix_web::{web, App, HttpResponse, HttpServer};
+use env_logger::{Builder, Env};
+use function::{config, handler};
+use std::{
+    env,
+    io::{Error, ErrorKind, Result},
+    num::ParseIntError,
+};
+
+#[actix_web::main]
+async fn main() -> Result<()> {
+    Builder::from_env(Env::default().default_filter_or("info")).init();
+
+    let port = match env::var("PORT") {
+        Ok(v) => v
+            .parse()
+            .map_err(|e: ParseIntError| Error::new(ErrorKind::Other, e.to_string()))?,
+        Err(_) => 8080,
+    };
+
+    // Serve the function's static handler
+    HttpServer::new(|| {
+        App::new()
+            .wrap(actix_web::middleware::Logger::default())
+            .configure(config::configure)
+            .route("/", web::get().to(handler::index))
+            .route("/", web::post().to(handler::index))
+            .route(
+                "/health/{_:(readiness|liveness)}",
+                web::get().to(HttpResponse::Ok),
+            )
+    })
+    .bind(("0.0.0.0", port))?
+    .workers(1)
+    .run()
+    .await
+}